@@ -3,6 +3,8 @@ package main
 import (
 	"errors"
 	"flag"
+	"io/ioutil"
+	"strings"
 
 	"fmt"
 
@@ -15,7 +17,12 @@ import (
 
 func main() {
 	var (
-		filename = flag.String("f", "FastBoot_MAIN_v8.db", "FastbootFile location")
+		filename   = flag.String("f", "FastBoot_MAIN_v8.db", "FastbootFile location")
+		generation = flag.Bool("g", false, "The file is a fastboot generation (FastBoot_MAIN_v13_0.db), with a header")
+		portable   = flag.Bool("p", false, "The file is a signed portable fastboot (FastBoot_MAIN_v13_portable.db)")
+		trusted    = flag.String("trust", "", "Comma separated public keys a portable fastboot must be signed by")
+		sign       = flag.String("sign", "", "Private key to sign the fastboot generation with, written to -o")
+		out        = flag.String("o", "FastBoot_portable.db", "Where -sign writes the portable fastboot")
 	)
 
 	flag.Parse()
@@ -33,6 +40,45 @@ func main() {
 		fmt.Fprintln(os.Stderr, "LoadDBStateList LoadFromFile returned nil")
 		panic(errors.New("failed to load from file"))
 	}
+	if *portable {
+		p := new(state.PortableFastBoot)
+		if err := p.UnmarshalBinary(b); err != nil {
+			panic(err)
+		}
+		if _, err := p.Verify(strings.Split(*trusted, ","), 0, nil); err != nil {
+			panic(err)
+		}
+		fmt.Printf("Signed by %x\n", p.Signature.GetPubBytes())
+		b = p.Generation
+		*generation = true
+	}
+	if *sign != "" {
+		key, err := primitives.NewPrivateKeyFromHex(*sign)
+		if err != nil {
+			panic(err)
+		}
+		p, err := state.NewPortableFastBoot(b, key)
+		if err != nil {
+			panic(err)
+		}
+		data, err := p.MarshalBinary()
+		if err != nil {
+			panic(err)
+		}
+		if err := ioutil.WriteFile(*out, data, 0644); err != nil {
+			panic(err)
+		}
+		fmt.Printf("Wrote %s signed by %s\n", *out, key.PublicKeyString())
+		return
+	}
+	if *generation {
+		header := new(state.FastBootHeader)
+		b, err = header.UnmarshalBinaryData(b)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("Generation: version %d, DBHeight %d, KeyMR %s\n", header.Version, header.DBHeight, header.KeyMR.String())
+	}
 	h := primitives.NewZeroHash()
 	b, err = h.UnmarshalBinaryData(b)
 	if err != nil {
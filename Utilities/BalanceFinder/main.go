@@ -104,7 +104,7 @@ func main() {
 	fmt.Printf("Addresses and balances written to '%s'\n", Out)
 
 	for k, v := range fct {
-		fmt.Fprintf(f, "%s: %d.%d\n", primitives.ConvertFctAddressToUserStr(factoid.NewAddress(k[:])), v/1e8, v%1e8)
+		fmt.Fprintf(f, "%s: %s\n", primitives.ConvertFctAddressToUserStr(factoid.NewAddress(k[:])), FactoshisToFct(v))
 	}
 
 	for k, v := range ec {
@@ -199,6 +199,15 @@ func FindBalance(reader tools.Fetcher) (map[[32]byte]int64, map[[32]byte]int64,
 	return fctAddressMap, ecAddressMap, nil
 }
 
+// FactoshisToFct formats factoshis as FCT with all 8 decimals, so DatabaseIntegrityCheck can read them back exactly
+func FactoshisToFct(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, v/1e8, v%1e8)
+}
+
 func DebugIfNeg(addr [32]byte, amt int64, fct bool, height uint32) {
 	if amt < 0 && Debug && height > 97886 {
 		str := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(addr[:]))
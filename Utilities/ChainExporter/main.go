@@ -0,0 +1,87 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/FactomProject/factomd/Utilities/tools"
+	"github.com/FactomProject/factomd/database/chainExporter"
+)
+
+const level string = "level"
+const bolt string = "bolt"
+
+func main() {
+	var (
+		out         = flag.String("o", "export", "Directory to write the CSV files to")
+		start       = flag.Uint("start", 0, "First directory block height to export")
+		end         = flag.Int64("end", -1, "Last directory block height to export, the head by default")
+		incremental = flag.Bool("incremental", false, "Export the heights above the last incremental export to the directory, up to the head")
+	)
+	flag.Parse()
+
+	fmt.Println("Usage:")
+	fmt.Println("ChainExporter [flags] level/bolt DBFileLocation")
+	fmt.Println("Program will export the blocks, entries and transactions to CSV files")
+
+	if len(flag.Args()) < 2 {
+		fmt.Println("\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 2 {
+		fmt.Println("\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	levelBolt := flag.Args()[0]
+	if levelBolt != level && levelBolt != bolt {
+		fmt.Println("\nFirst argument should be `level` or `bolt`")
+		os.Exit(1)
+	}
+	path := flag.Args()[1]
+	if _, err := os.Stat(path); err != nil {
+		fmt.Println("\nNo database at", path)
+		os.Exit(1)
+	}
+
+	dbo := tools.NewDBReader(levelBolt, path)
+	defer dbo.Close()
+	e := &chainExporter.Exporter{DB: dbo, Dir: *out}
+
+	if *incremental {
+		if *start != 0 || *end >= 0 {
+			fmt.Println("\n-incremental exports from the last incremental export to the head, it takes no -start or -end")
+			os.Exit(1)
+		}
+		from, to, err := e.ExportIncremental()
+		if err != nil {
+			fmt.Println("Exporting:", err)
+			dbo.Close()
+			os.Exit(1)
+		}
+		if to < from {
+			fmt.Printf("Nothing new to export, %s is up to %d\n", *out, to)
+			return
+		}
+		fmt.Printf("Exported %d to %d to %s\n", from, to, *out)
+		return
+	}
+
+	last := uint32(math.MaxUint32)
+	if *end >= 0 {
+		last = uint32(*end)
+	}
+	to, err := e.Export(uint32(*start), last)
+	if err != nil {
+		fmt.Println("Exporting:", err)
+		dbo.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %d to %d to %s\n", *start, to, *out)
+}
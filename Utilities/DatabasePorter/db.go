@@ -1,10 +1,10 @@
 package main
 
 import (
-	//"fmt"
+	"fmt"
 	"os"
+	"path/filepath"
 
-	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/hybridDB"
 	"github.com/FactomProject/factomd/database/mapdb"
@@ -13,34 +13,39 @@ import (
 
 //DBInit
 
-func InitBolt(cfg *util.FactomdConfig) interfaces.DBOverlay {
-	//fmt.Println("InitBolt")
-	path := cfg.App.BoltDBPath + "/"
-
-	os.MkdirAll(path, 0777)
-	dbase := hybridDB.NewBoltMapHybridDB(nil, path+"FactomBolt-Import.db")
-	return databaseOverlay.NewOverlay(dbase)
-}
-
-func InitLevelDB(cfg *util.FactomdConfig) interfaces.DBOverlay {
-	//fmt.Println("InitLevelDB")
-	path := cfg.App.LdbPath + "/" + "FactoidLevel-Import.db"
-
-	dbase, err := hybridDB.NewLevelMapHybridDB(path, false)
-
-	if err != nil || dbase == nil {
-		dbase, err = hybridDB.NewLevelMapHybridDB(path, true)
+// OpenDatabase opens a database of a type as factomd.conf names them, LDB, Bolt or Map, creating it if
+// asked to
+func OpenDatabase(dbType string, path string, create bool) (*databaseOverlay.Overlay, error) {
+	switch dbType {
+	case "LDB":
+		dbase, err := hybridDB.NewLevelMapHybridDB(path, create)
 		if err != nil {
-			panic(err)
+			return nil, err
+		}
+		return databaseOverlay.NewOverlay(dbase), nil
+	case "Bolt":
+		if create {
+			os.MkdirAll(filepath.Dir(path), 0777)
+		} else if _, err := os.Stat(path); err != nil {
+			return nil, err
 		}
+		return databaseOverlay.NewOverlay(hybridDB.NewBoltMapHybridDB(nil, path)), nil
+	case "Map":
+		dbase := new(mapdb.MapDB)
+		dbase.Init(nil)
+		return databaseOverlay.NewOverlay(dbase), nil
 	}
-
-	return databaseOverlay.NewOverlay(dbase)
+	return nil, fmt.Errorf("unknown database type %q, expected LDB, Bolt or Map", dbType)
 }
 
-func InitMapDB(cfg *util.FactomdConfig) interfaces.DBOverlay {
-	//fmt.Println("InitMapDB")
-	dbase := new(mapdb.MapDB)
-	dbase.Init(nil)
-	return databaseOverlay.NewOverlay(dbase)
+// ImportPath is where the porter saves by default: beside the database of the factomd.conf, with -Import
+// at the end of its name
+func ImportPath(cfg *util.FactomdConfig) string {
+	switch cfg.App.DBType {
+	case "Bolt":
+		return filepath.Join(cfg.App.BoltDBPath, "FactomBolt-Import.db")
+	case "LDB":
+		return filepath.Join(cfg.App.LdbPath, "FactoidLevel-Import.db")
+	}
+	return ""
 }
@@ -0,0 +1,157 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/p2p"
+)
+
+// P2PSource requests DBStates from factomd peers, as a node does when it syncs, spreading the requests over
+// the peers at random.  The head is learned from the heartbeats the federated servers broadcast.
+type P2PSource struct {
+	proxy   *engine.P2PProxy
+	network *p2p.Controller
+	Timeout time.Duration // How long to wait for a DBState before asking again
+
+	mutex     sync.Mutex
+	head      uint32                          // Highest height the peers can serve
+	heard     bool                            // A heartbeat has set the head
+	requested map[uint32]bool                 // Heights being fetched
+	states    map[uint32]*messages.DBStateMsg // DBStates received for the heights being fetched
+}
+
+var _ Source = (*P2PSource)(nil)
+
+// NewP2PSource connects to the given comma separated peers, and only to them
+func NewP2PSource(peers string, network string, port string) (*P2PSource, error) {
+	var networkID p2p.NetworkID
+	switch strings.ToUpper(network) {
+	case "MAIN":
+		networkID = p2p.MainNet
+	case "TEST":
+		networkID = p2p.TestNet
+	case "LOCAL":
+		networkID = p2p.LocalNet
+	default:
+		return nil, fmt.Errorf("unknown network %q, expected MAIN, TEST or LOCAL", network)
+	}
+
+	ci := p2p.ControllerInit{
+		NodeName:                 "DatabasePorter",
+		Port:                     port,
+		PeersFile:                filepath.Join(os.TempDir(), "porterpeers.json"),
+		Network:                  networkID,
+		Exclusive:                true,
+		ExclusiveIn:              true,
+		ConfigPeers:              peers,
+		ConnectionMetricsChannel: make(chan interface{}, p2p.StandardChannelSize),
+	}
+	s := new(P2PSource)
+	s.Timeout = 20 * time.Second
+	s.requested = make(map[uint32]bool)
+	s.states = make(map[uint32]*messages.DBStateMsg)
+	s.network = new(p2p.Controller).Init(ci)
+	s.network.StartNetwork()
+	s.proxy = new(engine.P2PProxy).Init("DatabasePorter", "P2P Network").(*engine.P2PProxy)
+	s.proxy.FromNetwork = s.network.FromNetwork
+	s.proxy.ToNetwork = s.network.ToNetwork
+	s.proxy.StartProxy()
+
+	go s.receive()
+	return s, nil
+}
+
+// receive keeps the DBStates asked for, and the head the heartbeats announce
+func (s *P2PSource) receive() {
+	for {
+		msg, err := s.proxy.Receive()
+		if err != nil || msg == nil {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+
+		s.mutex.Lock()
+		switch m := msg.(type) {
+		case *messages.Heartbeat:
+			// The heartbeat holds the height being built, and peers only serve the blocks below their
+			// highest saved one.
+			if m.DBHeight > 1 && m.DBHeight-2 > s.head {
+				s.head = m.DBHeight - 2
+			}
+			s.heard = true
+		case *messages.DBStateMsg:
+			height := m.DirectoryBlock.GetDatabaseHeight()
+			if s.requested[height] {
+				s.states[height] = m
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// Head waits for a heartbeat, for up to two minutes, as they come once a minute
+func (s *P2PSource) Head() (uint32, error) {
+	for start := time.Now(); time.Since(start) < 2*time.Minute; time.Sleep(100 * time.Millisecond) {
+		s.mutex.Lock()
+		head, heard := s.head, s.heard
+		s.mutex.Unlock()
+		if heard {
+			return head, nil
+		}
+	}
+	return 0, fmt.Errorf("no heartbeats from the peers, give the height to sync to with -end")
+}
+
+func (s *P2PSource) FetchBlockSet(height uint32) (*BlockSet, error) {
+	s.mutex.Lock()
+	s.requested[height] = true
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.requested, height)
+		delete(s.states, height)
+		s.mutex.Unlock()
+	}()
+
+	for tries := 0; tries < 3; tries++ {
+		msg := new(messages.DBStateMissing)
+		msg.Peer2Peer = true // To a random peer
+		msg.Timestamp = primitives.NewTimestampNow()
+		msg.DBHeightStart = height
+		msg.DBHeightEnd = height
+		s.proxy.Send(msg)
+
+		for start := time.Now(); time.Since(start) < s.Timeout; time.Sleep(10 * time.Millisecond) {
+			s.mutex.Lock()
+			m := s.states[height]
+			s.mutex.Unlock()
+			if m != nil {
+				set := new(BlockSet)
+				set.DBlock = m.DirectoryBlock
+				set.ABlock = m.AdminBlock
+				set.FBlock = m.FactoidBlock
+				set.ECBlock = m.EntryCreditBlock
+				set.EBlocks = m.EBlocks
+				set.Entries = m.Entries
+				return set, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no peer sent the DBState at %d", height)
+}
+
+func (s *P2PSource) Close() {
+	s.network.NetworkStop()
+}
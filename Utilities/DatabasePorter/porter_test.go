@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// testFed is the federated server of the test database
+var testFed, _ = primitives.HexToHash("38bab1455b7bd7e5efd15c53c777c79d0c988e9210f1da49a99d95b3a6417be9")
+
+// signedSource is the test database with a signing key for its federated server, added in the genesis
+// admin block, and each admin block signing the directory block before it
+type signedSource struct {
+	sets []*BlockSet
+}
+
+var _ Source = (*signedSource)(nil)
+
+func newSignedSource(t *testing.T, key *primitives.PrivateKey) *signedSource {
+	db := &fetcherSource{fetcher: testHelper.CreateAndPopulateTestDatabaseOverlay()}
+	head, err := db.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := new(signedSource)
+	for h := uint32(0); h <= head; h++ {
+		set, err := db.FetchBlockSet(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var prev *BlockSet
+		if h == 0 {
+			set.ABlock.AddFederatedServerSigningKey(testFed, *key.Pub)
+		} else {
+			prev = s.sets[h-1]
+			signLink(set, prev, testFed, key)
+		}
+		relink(set, prev)
+		s.sets = append(s.sets, set)
+	}
+	return s
+}
+
+// signLink adds the signature of the directory block before a set to its admin block
+func signLink(set *BlockSet, prev *BlockSet, signer interfaces.IHash, key *primitives.PrivateKey) {
+	data, err := prev.DBlock.GetHeader().MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	set.ABlock.AddDBSig(signer, key.Sign(data))
+}
+
+// relink makes a set's blocks name each other again after its admin block, or the one before it, changed
+func relink(set *BlockSet, prev *BlockSet) {
+	if prev != nil {
+		backRef, _ := prev.ABlock.BackReferenceHash()
+		set.ABlock.GetHeader().SetPrevBackRefHash(backRef)
+		set.DBlock.GetHeader().SetPrevKeyMR(prev.DBlock.GetKeyMR())
+		set.DBlock.GetHeader().SetPrevFullHash(prev.DBlock.GetFullHash())
+	}
+	set.DBlock.SetABlockHash(set.ABlock)
+	set.DBlock.BuildKeyMerkleRoot()
+}
+
+func (s *signedSource) Head() (uint32, error) {
+	return uint32(len(s.sets) - 1), nil
+}
+
+func (s *signedSource) FetchBlockSet(height uint32) (*BlockSet, error) {
+	if int(height) >= len(s.sets) {
+		return nil, fmt.Errorf("directory block %d not found", height)
+	}
+	return s.sets[height], nil
+}
+
+func (s *signedSource) Close() {}
+
+func (s *signedSource) verifier() *Verifier {
+	return NewVerifier(0, s.sets[0].DBlock.GetKeyMR(), nil)
+}
+
+func TestSyncResumes(t *testing.T) {
+	source := newSignedSource(t, primitives.RandomPrivateKey())
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	head, err := source.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := Sync(source, dbo, source.verifier(), head/2, 4, 0, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != int(head/2)+1 {
+		t.Errorf("Saved %d blocks, expected %d", saved, head/2+1)
+	}
+
+	saved, err = Sync(source, dbo, source.verifier(), head, 4, 0, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != int(head-head/2) {
+		t.Errorf("Resumed and saved %d blocks, expected %d", saved, head-head/2)
+	}
+
+	want := source.sets[head].DBlock
+	got, err := dbo.FetchDBlockHead()
+	if err != nil || got == nil {
+		t.Fatalf("No head after the sync %v", err)
+	}
+	if !got.GetKeyMR().IsSameAs(want.GetKeyMR()) {
+		t.Errorf("Synced to %x, expected %x", got.GetKeyMR().Bytes(), want.GetKeyMR().Bytes())
+	}
+}
+
+// droppingSource loses the entries of a height
+type droppingSource struct {
+	*signedSource
+	height uint32
+}
+
+func (s *droppingSource) FetchBlockSet(height uint32) (*BlockSet, error) {
+	set, err := s.signedSource.FetchBlockSet(height)
+	if err == nil && height == s.height {
+		dropped := *set
+		dropped.Entries = set.Entries[1:]
+		set = &dropped
+	}
+	return set, err
+}
+
+func TestSyncStopsAtBadBlocks(t *testing.T) {
+	source := &droppingSource{newSignedSource(t, primitives.RandomPrivateKey()), 3}
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	head, err := source.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := Sync(source, dbo, source.verifier(), head, 4, 0, make(chan struct{}))
+	if err == nil {
+		t.Error("Saved a height that is missing an entry")
+	}
+	if saved != 3 {
+		t.Errorf("Saved %d blocks, expected the 3 below the bad one", saved)
+	}
+	got, _ := dbo.FetchDBlockHead()
+	if got == nil || got.GetDatabaseHeight() != 2 {
+		t.Errorf("Expected the database to end below the bad height, at %v", got)
+	}
+
+	set := *source.sets[2]
+	set.DBlock = source.sets[4].DBlock
+	if _, err := source.verifier().VerifyLink(got, &set); err == nil {
+		t.Error("Linked the block at 4 to the one at 2")
+	}
+}
+
+func TestVerifyLinkNeedsAuthorities(t *testing.T) {
+	key, other := primitives.RandomPrivateKey(), primitives.RandomPrivateKey()
+	source := newSignedSource(t, key)
+	genesis, set := source.sets[0], source.sets[1]
+	unsigned := set.ABlock.GetABEntries()
+	unsigned = unsigned[: len(unsigned)-1 : len(unsigned)-1] // Without the signature newSignedSource added
+
+	type signature struct {
+		signer interfaces.IHash
+		key    *primitives.PrivateKey
+	}
+	// link verifies the block at 1 signed with the given signatures
+	link := func(v *Verifier, sigs ...signature) error {
+		set.ABlock.SetABEntries(unsigned)
+		for _, sig := range sigs {
+			signLink(set, genesis, sig.signer, sig.key)
+		}
+		relink(set, genesis)
+		if _, err := v.VerifyLink(nil, genesis); err != nil {
+			return err
+		}
+		_, err := v.VerifyLink(genesis.DBlock, set)
+		return err
+	}
+	anchor := genesis.DBlock.GetKeyMR()
+	someone := primitives.Sha([]byte("someone"))
+
+	if err := link(NewVerifier(0, anchor, nil), signature{testFed, key}); err != nil {
+		t.Errorf("The federated server's signature wasn't enough: %v", err)
+	}
+	if err := link(NewVerifier(0, anchor, nil)); err == nil {
+		t.Error("Linked a block nobody signed")
+	}
+	if err := link(NewVerifier(0, anchor, nil), signature{testFed, other}, signature{someone, other}); err == nil {
+		t.Error("Linked a block signed by keys that aren't the authorities'")
+	}
+	if err := link(NewVerifier(0, anchor, other.Pub[:]), signature{someone, other}); err != nil {
+		t.Errorf("The bootstrap key's signature wasn't enough: %v", err)
+	}
+	if err := link(NewVerifier(0, someone, nil), signature{testFed, key}); err == nil {
+		t.Error("Synced a chain that doesn't start at the anchor")
+	}
+}
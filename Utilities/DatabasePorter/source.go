@@ -0,0 +1,146 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/Utilities/tools"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// BlockSet is everything saved at a height: the directory block, the blocks it names and their entries
+type BlockSet struct {
+	DBlock  interfaces.IDirectoryBlock
+	ABlock  interfaces.IAdminBlock
+	ECBlock interfaces.IEntryCreditBlock
+	FBlock  interfaces.IFBlock
+	EBlocks []interfaces.IEntryBlock
+	Entries []interfaces.IEBEntry
+}
+
+// Source is where the porter gets blocks from.  It must be safe to fetch from several goroutines at once.
+type Source interface {
+	// Head is the highest height the source can provide
+	Head() (uint32, error)
+	FetchBlockSet(height uint32) (*BlockSet, error)
+	Close()
+}
+
+// NewSource makes the source a -from location names.  p2p://host:port[,host:port...] requests DBStates from
+// the given factomd peers, http://host:port or host:port reads the API of a factomd, and a path reads a
+// factomd database, a leveldb directory or a bolt file.
+func NewSource(from string, network string, port string) (Source, error) {
+	switch {
+	case strings.HasPrefix(from, "p2p://"):
+		return NewP2PSource(strings.TrimPrefix(from, "p2p://"), network, port)
+	case strings.HasPrefix(from, "http://"):
+		return &fetcherSource{fetcher: tools.NewAPIReader(strings.TrimPrefix(from, "http://"))}, nil
+	}
+
+	info, err := os.Stat(from)
+	if err != nil {
+		if strings.Contains(from, "/") {
+			return nil, err
+		}
+		return &fetcherSource{fetcher: tools.NewAPIReader(from)}, nil
+	}
+	dbType := "Bolt"
+	if info.IsDir() {
+		dbType = "LDB"
+	}
+	dbo, err := OpenDatabase(dbType, from, false)
+	if err != nil {
+		return nil, err
+	}
+	return &fetcherSource{fetcher: dbo, closer: dbo}, nil
+}
+
+// fetcherSource gets blocks one at a time from the API or a database
+type fetcherSource struct {
+	fetcher tools.Fetcher
+	closer  *databaseOverlay.Overlay // The database, if the source is one
+}
+
+var _ Source = (*fetcherSource)(nil)
+
+func (s *fetcherSource) Head() (uint32, error) {
+	head, err := s.fetcher.FetchDBlockHead()
+	if err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, fmt.Errorf("the source has no blocks")
+	}
+	return head.GetDatabaseHeight(), nil
+}
+
+func (s *fetcherSource) FetchBlockSet(height uint32) (*BlockSet, error) {
+	set := new(BlockSet)
+	dblock, err := s.fetcher.FetchDBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	if dblock == nil {
+		return nil, fmt.Errorf("directory block %d not found", height)
+	}
+	set.DBlock = dblock
+
+	for _, e := range dblock.GetDBEntries() {
+		chainID := e.GetChainID().Bytes()
+		switch {
+		case bytes.Equal(chainID, constants.ADMIN_CHAINID):
+			set.ABlock, err = s.fetcher.FetchABlockByHeight(height)
+		case bytes.Equal(chainID, constants.FACTOID_CHAINID):
+			set.FBlock, err = s.fetcher.FetchFBlockByHeight(height)
+		case bytes.Equal(chainID, constants.EC_CHAINID):
+			set.ECBlock, err = s.fetcher.FetchECBlockByHeight(height)
+		default:
+			err = s.fetchEBlock(set, e.GetKeyMR())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// fetchEBlock adds an entry block and its entries to the set
+func (s *fetcherSource) fetchEBlock(set *BlockSet, keymr interfaces.IHash) error {
+	eblock, err := s.fetcher.FetchEBlock(keymr)
+	if err != nil {
+		return err
+	}
+	if eblock == nil {
+		return fmt.Errorf("entry block %x not found", keymr.Bytes())
+	}
+	set.EBlocks = append(set.EBlocks, eblock)
+
+	for _, hash := range eblock.GetEntryHashes() {
+		if hash.IsMinuteMarker() {
+			continue
+		}
+		entry, err := s.fetcher.FetchEntry(hash)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return fmt.Errorf("entry %x not found", hash.Bytes())
+		}
+		set.Entries = append(set.Entries, entry)
+	}
+	return nil
+}
+
+func (s *fetcherSource) Close() {
+	if s.closer != nil {
+		s.closer.Close()
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+)
+
+// VerifyBlockSet checks a set holds the blocks its directory block names, by their KeyMRs, and every entry
+// of its entry blocks, by their hashes.  Sets are checked on their own, so this can run as they are fetched.
+func VerifyBlockSet(set *BlockSet, height uint32) error {
+	if set.DBlock == nil {
+		return fmt.Errorf("no directory block")
+	}
+	if h := set.DBlock.GetDatabaseHeight(); h != height {
+		return fmt.Errorf("asked for the directory block at %d, got the one at %d", height, h)
+	}
+
+	eblocks := make(map[[32]byte]interfaces.IEntryBlock)
+	for _, eblock := range set.EBlocks {
+		eblocks[eblock.DatabasePrimaryIndex().Fixed()] = eblock
+	}
+	named := 0
+	for _, e := range set.DBlock.GetDBEntries() {
+		var block interface {
+			DatabasePrimaryIndex() interfaces.IHash
+		}
+		switch e.GetChainID().String() {
+		case "000000000000000000000000000000000000000000000000000000000000000a":
+			if set.ABlock != nil {
+				block = set.ABlock
+			}
+		case "000000000000000000000000000000000000000000000000000000000000000f":
+			if set.FBlock != nil {
+				block = set.FBlock
+			}
+		case "000000000000000000000000000000000000000000000000000000000000000c":
+			if set.ECBlock != nil {
+				block = set.ECBlock
+			}
+		default:
+			eblock := eblocks[e.GetKeyMR().Fixed()]
+			if eblock == nil {
+				return fmt.Errorf("missing the entry block %x", e.GetKeyMR().Bytes())
+			}
+			if !eblock.GetChainID().IsSameAs(e.GetChainID()) {
+				return fmt.Errorf("entry block %x is of chain %x, not %x", e.GetKeyMR().Bytes(), eblock.GetChainID().Bytes(), e.GetChainID().Bytes())
+			}
+			named++
+			continue
+		}
+		if block == nil {
+			return fmt.Errorf("missing the block of chain %x", e.GetChainID().Bytes())
+		}
+		if keymr := block.DatabasePrimaryIndex(); !keymr.IsSameAs(e.GetKeyMR()) {
+			return fmt.Errorf("the block of chain %x has KeyMR %x, the directory block names %x", e.GetChainID().Bytes(), keymr.Bytes(), e.GetKeyMR().Bytes())
+		}
+	}
+	if named != len(eblocks) || len(eblocks) != len(set.EBlocks) {
+		return fmt.Errorf("%d entry blocks for the %d the directory block names", len(set.EBlocks), named)
+	}
+
+	entries := make(map[[32]byte]interfaces.IEBEntry)
+	for _, entry := range set.Entries {
+		entries[entry.GetHash().Fixed()] = entry
+	}
+	used := make(map[[32]byte]bool)
+	for _, eblock := range set.EBlocks {
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			entry := entries[hash.Fixed()]
+			if entry == nil {
+				return fmt.Errorf("missing the entry %x of entry block %x", hash.Bytes(), eblock.DatabasePrimaryIndex().Bytes())
+			}
+			if !entry.GetChainID().IsSameAs(eblock.GetChainID()) {
+				return fmt.Errorf("entry %x is of chain %x, not %x", hash.Bytes(), entry.GetChainID().Bytes(), eblock.GetChainID().Bytes())
+			}
+			used[hash.Fixed()] = true
+		}
+	}
+	if len(used) != len(entries) {
+		return fmt.Errorf("%d entries that are in no entry block", len(entries)-len(used))
+	}
+	return nil
+}
+
+// Verifier checks the directory blocks link into one chain, from a directory block known to be good, each
+// signed by a majority of the federated servers of its time.  The federated servers and their signing keys
+// are followed through the admin blocks as the blocks are checked.
+type Verifier struct {
+	AnchorHeight uint32           // Height of the directory block known to be good
+	AnchorKeyMR  interfaces.IHash // Its KeyMR
+	BootstrapKey []byte           // The network's bootstrap key, which signs for it as one authority
+
+	feds map[[32]byte]bool   // Identity chains of the federated servers
+	keys map[[32]byte][]byte // Signing keys of the servers by identity chain
+}
+
+// NewVerifier checks a chain passes through the directory block with the given KeyMR at anchorHeight
+func NewVerifier(anchorHeight uint32, anchorKeyMR interfaces.IHash, bootstrapKey []byte) *Verifier {
+	v := new(Verifier)
+	v.AnchorHeight = anchorHeight
+	v.AnchorKeyMR = anchorKeyMR
+	v.BootstrapKey = bootstrapKey
+	v.feds = make(map[[32]byte]bool)
+	v.keys = make(map[[32]byte][]byte)
+	return v
+}
+
+// NewNetworkVerifier makes the verifier of a network.  The chain is anchored to the checkpoint, written as
+// height:keymr, or without one to the network's genesis block.
+func NewNetworkVerifier(network string, checkpoint string, cfg *util.FactomdConfig) (*Verifier, error) {
+	var networkID uint32
+	var key string
+	switch strings.ToUpper(network) {
+	case "MAIN":
+		networkID, key = constants.MAIN_NETWORK_ID, "0426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613a"
+	case "TEST":
+		networkID, key = constants.TEST_NETWORK_ID, "49b6edd274e7d07c94d4831eca2f073c207248bde1bf989d2183a8cebca227b7"
+	case "LOCAL":
+		networkID, key = constants.LOCAL_NETWORK_ID, "cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a"
+	case "CUSTOM":
+		key = cfg.App.CustomBootstrapKey
+		if checkpoint == "" {
+			return nil, fmt.Errorf("a CUSTOM network needs a checkpoint to anchor the chain to")
+		}
+	default:
+		return nil, fmt.Errorf("unknown network %q, expected MAIN, TEST, LOCAL or CUSTOM", network)
+	}
+	bootstrapKey, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("bad bootstrap key %q: %v", key, err)
+	}
+
+	if checkpoint != "" {
+		height, keymr, err := state.ParseFastBootCheckpoint(checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		return NewVerifier(height, keymr, bootstrapKey), nil
+	}
+	genesis, _, _, _ := state.GenerateGenesisBlocks(networkID, nil)
+	return NewVerifier(0, genesis.GetKeyMR(), bootstrapKey), nil
+}
+
+// Load follows the authorities through the admin blocks already in a database, up to its head, and checks
+// the anchor if the database holds it
+func (v *Verifier) Load(dbo interfaces.DBOverlay, head interfaces.IDirectoryBlock) error {
+	if head == nil {
+		return nil
+	}
+	for h := uint32(0); h <= head.GetDatabaseHeight(); h++ {
+		ablock, err := dbo.FetchABlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if ablock == nil {
+			return fmt.Errorf("no admin block at %d in the database", h)
+		}
+		v.apply(ablock)
+	}
+	if head.GetDatabaseHeight() < v.AnchorHeight {
+		return nil
+	}
+	anchor, err := dbo.FetchDBlockByHeight(v.AnchorHeight)
+	if err != nil {
+		return err
+	}
+	if anchor == nil || !anchor.GetKeyMR().IsSameAs(v.AnchorKeyMR) {
+		return fmt.Errorf("the database doesn't hold the directory block %x at %d", v.AnchorKeyMR.Bytes(), v.AnchorHeight)
+	}
+	return nil
+}
+
+// VerifyLink checks a set follows the directory block before it, nil if it is the first, and that a
+// majority of the federated servers signed that block in the set's admin block.  The signatures that
+// aren't good, or aren't by an authority, are counted.  The first block must be at height zero, and the
+// block at the anchor height the anchor.  Once a set is verified the authorities are updated from its
+// admin block.
+func (v *Verifier) VerifyLink(prev interfaces.IDirectoryBlock, set *BlockSet) (badSigs int, err error) {
+	header := set.DBlock.GetHeader()
+	if set.ABlock == nil {
+		return 0, fmt.Errorf("no admin block at %d", header.GetDBHeight())
+	}
+	if header.GetDBHeight() == v.AnchorHeight && !set.DBlock.GetKeyMR().IsSameAs(v.AnchorKeyMR) {
+		return 0, fmt.Errorf("the block at %d is %x, not the anchor %x", header.GetDBHeight(), set.DBlock.GetKeyMR().Bytes(), v.AnchorKeyMR.Bytes())
+	}
+	if prev == nil {
+		if header.GetDBHeight() != 0 || !header.GetPrevKeyMR().IsZero() {
+			return 0, fmt.Errorf("the first block at %d follows %x", header.GetDBHeight(), header.GetPrevKeyMR().Bytes())
+		}
+		v.apply(set.ABlock)
+		return 0, nil
+	}
+
+	if header.GetDBHeight() != prev.GetDatabaseHeight()+1 {
+		return 0, fmt.Errorf("the block at %d follows the one at %d", header.GetDBHeight(), prev.GetDatabaseHeight())
+	}
+	if !header.GetPrevKeyMR().IsSameAs(prev.GetKeyMR()) {
+		return 0, fmt.Errorf("the block at %d follows %x, not %x", header.GetDBHeight(), header.GetPrevKeyMR().Bytes(), prev.GetKeyMR().Bytes())
+	}
+	if !header.GetPrevFullHash().IsSameAs(prev.GetFullHash()) {
+		return 0, fmt.Errorf("the block at %d follows the full hash %x, not %x", header.GetDBHeight(), header.GetPrevFullHash().Bytes(), prev.GetFullHash().Bytes())
+	}
+
+	signed, err := prev.GetHeader().MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	// Servers promoted or given a key in this admin block may sign already, as factomd allows, and the
+	// majority is of the servers that stay
+	next := v.copy()
+	next.apply(set.ABlock)
+	staying := 0
+	for id := range v.feds {
+		if next.feds[id] {
+			staying++
+		}
+	}
+
+	signers := make(map[string]bool)
+	for _, e := range set.ABlock.GetABEntries() {
+		sig, ok := e.(*adminBlock.DBSignatureEntry)
+		if !ok {
+			continue
+		}
+		signer := v.signer(sig)
+		if signer == "" {
+			signer = next.signer(sig)
+		}
+		if signer == "" || !sig.PrevDBSig.Verify(signed) {
+			badSigs++
+			continue
+		}
+		signers[signer] = true
+	}
+	if need := staying/2 + 1; len(signers) < need {
+		return badSigs, fmt.Errorf("the block at %d is signed by %d of the %d authorities it needs, with %d bad signatures", prev.GetDatabaseHeight(), len(signers), need, badSigs)
+	}
+	*v = *next
+	return badSigs, nil
+}
+
+// signer names the authority a signature is by, the identity chain of a federated server or "bootstrap",
+// and is empty if it isn't by one
+func (v *Verifier) signer(sig *adminBlock.DBSignatureEntry) string {
+	key := sig.PrevDBSig.GetKey()
+	if len(v.BootstrapKey) > 0 && bytes.Equal(key, v.BootstrapKey) {
+		return "bootstrap"
+	}
+	if sig.IdentityAdminChainID == nil {
+		return ""
+	}
+	id := sig.IdentityAdminChainID.Fixed()
+	if v.feds[id] && bytes.Equal(key, v.keys[id]) {
+		return sig.IdentityAdminChainID.String()
+	}
+	return ""
+}
+
+// apply updates the federated servers and their keys from an admin block
+func (v *Verifier) apply(ablock interfaces.IAdminBlock) {
+	for _, e := range ablock.GetABEntries() {
+		switch entry := e.(type) {
+		case *adminBlock.AddFederatedServer:
+			v.feds[entry.IdentityChainID.Fixed()] = true
+		case *adminBlock.AddAuditServer:
+			delete(v.feds, entry.IdentityChainID.Fixed())
+		case *adminBlock.RemoveFederatedServer:
+			delete(v.feds, entry.IdentityChainID.Fixed())
+		case *adminBlock.AddFederatedServerSigningKey:
+			v.keys[entry.IdentityChainID.Fixed()] = entry.PublicKey[:]
+		}
+	}
+}
+
+func (v *Verifier) copy() *Verifier {
+	c := NewVerifier(v.AnchorHeight, v.AnchorKeyMR, v.BootstrapKey)
+	for id := range v.feds {
+		c.feds[id] = true
+	}
+	for id, key := range v.keys {
+		c.keys[id] = key
+	}
+	return c
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+func main() {
+	var (
+		file = flag.String("f", "", "Read the data from a file instead, one hex string per line")
+		raw  = flag.Bool("raw", false, "The file holds a single binary message or block rather than hex")
+	)
+	flag.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("Decode [-f file [-raw]] [hex ...]")
+		fmt.Println("Decodes messages, blocks and entries given in hex on the command line, in a file or on stdin")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Messages are unmarshalled through the general factory, the same as factomd sets it up
+	messages.General = new(msgsupport.GeneralFactory)
+	primitives.General = messages.General
+
+	failed := false
+	decode := func(p []byte) {
+		resp, err := wsapi.DecodeRaw(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			return
+		}
+		out, err := json.MarshalIndent(resp, "", "\t")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			return
+		}
+		fmt.Println(string(out))
+	}
+	decodeHex := func(s string) {
+		p, err := hex.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: not hex: %v\n", err)
+			failed = true
+			return
+		}
+		decode(p)
+	}
+
+	switch {
+	case *file != "" && *raw:
+		p, err := ioutil.ReadFile(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		decode(p)
+	case *file != "":
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		decodeLines(f, decodeHex)
+	case flag.NArg() > 0:
+		for _, arg := range flag.Args() {
+			decodeHex(arg)
+		}
+	default:
+		decodeLines(os.Stdin, decodeHex)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// decodeLines calls decodeHex for every line that isn't blank
+func decodeLines(r io.Reader, decodeHex func(string)) {
+	scanner := bufio.NewScanner(r)
+	// A block in hex can be much longer than the default line limit
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			decodeHex(scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
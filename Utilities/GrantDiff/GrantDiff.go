@@ -0,0 +1,111 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+)
+
+func main() {
+	var (
+		network  = flag.String("network", "MAIN", "Network whose hard coded grants and payout frequency are used (MAIN, TEST, LOCAL, CUSTOM)")
+		proposed = flag.String("f", "", "Proposed JSON grant list")
+		current  = flag.String("current", "", "JSON grant list paid now on top of the hard coded grants, if any")
+		add      = flag.Bool("add", false, "The proposed list is added to the -current grants, as a grant chain entry is, rather than replacing the grant file")
+		sign     = flag.String("sign", "", "Private key to sign the proposed list with, printing the external IDs of its grant chain entry")
+	)
+	flag.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("GrantDiff [-network MAIN] [-current grants.json [-add]] [-sign key] -f proposed.json")
+		fmt.Println("Shows how a proposed grant list changes the grants paid, height by height")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *proposed == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	globals.Params.NetworkName = strings.ToUpper(*network)
+	switch globals.Params.NetworkName {
+	case "LOCAL":
+		constants.SetLocalCoinBaseConstants()
+	case "CUSTOM":
+		constants.SetCustomCoinBaseConstants()
+	}
+
+	hardcoded := state.GetHardCodedGrants()
+	currentGrants := hardcoded
+	if *current != "" {
+		list, _ := readList(*current)
+		merged, errs := state.MergeGrants(hardcoded, [][]state.HardGrant{list})
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s is not paid: %v\n", *current, err)
+		}
+		currentGrants = merged
+	}
+
+	list, data := readList(*proposed)
+	base := hardcoded
+	if *add {
+		base = currentGrants
+	}
+	proposedGrants, errs := state.MergeGrants(base, [][]state.HardGrant{list})
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "%s would not be paid: %v\n", *proposed, err)
+	}
+
+	var removed, added uint64
+	for _, c := range state.DiffGrants(currentGrants, proposedGrants) {
+		fmt.Printf("Height %d\n", c.Height)
+		for _, g := range c.Removed {
+			fmt.Printf("  - %s FCT to %s\n", primitives.ConvertDecimalToString(g.Amount), primitives.ConvertFctAddressToUserStr(g.Address))
+			removed += g.Amount
+		}
+		for _, g := range c.Added {
+			fmt.Printf("  + %s FCT to %s\n", primitives.ConvertDecimalToString(g.Amount), primitives.ConvertFctAddressToUserStr(g.Address))
+			added += g.Amount
+		}
+	}
+	fmt.Printf("Removed %s FCT, added %s FCT\n", primitives.ConvertDecimalToString(removed), primitives.ConvertDecimalToString(added))
+
+	sum := sha256.Sum256(data)
+	fmt.Printf("GrantFileChecksum = %x\n", sum)
+	if *sign != "" {
+		key, err := primitives.NewPrivateKeyFromHex(*sign)
+		if err != nil {
+			panic(err)
+		}
+		for i, id := range state.SignGrantList(data, key) {
+			fmt.Printf("ExtID[%d] = %s\n", i, hex.EncodeToString(id))
+		}
+	}
+}
+
+// readList reads and checks a grant list, exiting if it is bad
+func readList(filename string) ([]state.HardGrant, []byte) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	list, err := state.ParseGrantList(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+		os.Exit(1)
+	}
+	return list, data
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/FactomProject/factomd/Utilities/tools"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+)
+
+const level string = "level"
+const bolt string = "bolt"
+
+func main() {
+	cfg := util.ReadConfig("")
+
+	var (
+		network     = flag.String("network", "", "Network name the fastboot generations were saved under, by default the name of the directory holding the database")
+		location    = flag.String("fastboot", "", "Directory of the fastboot generations, by default the one above the network directory")
+		generations = flag.Int("generations", cfg.App.FastBootGenerations, "Number of fastboot generations kept")
+		inDB        = flag.Bool("fastbootindb", cfg.App.FastBootInDB, "The fastboot generations are kept in the database")
+		anchors     = flag.Bool("anchors", true, "Rebuild the anchor information (DirBlockInfo) from the anchors left")
+		verify      = flag.Bool("verify", true, "Check the integrity of the database once rolled back")
+	)
+	flag.Parse()
+
+	fmt.Println("Usage:")
+	fmt.Println("Rollback [flags] level/bolt Height DBFileLocation")
+	fmt.Println("Program will remove every block above the directory block height, so factomd restarts from it")
+
+	if len(flag.Args()) < 3 {
+		fmt.Println("\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 3 {
+		fmt.Println("\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	levelBolt := flag.Args()[0]
+	if levelBolt != level && levelBolt != bolt {
+		fmt.Println("\nFirst argument should be `level` or `bolt`")
+		os.Exit(1)
+	}
+
+	height, err := strconv.Atoi(flag.Args()[1])
+	if err != nil || height < 0 {
+		fmt.Println("\nSecond argument should be a height of zero or more instead of", flag.Args()[1])
+		os.Exit(1)
+	}
+
+	path := flag.Args()[2]
+	if _, err := os.Stat(path); err != nil {
+		fmt.Println("\nNo database at", path)
+		os.Exit(1)
+	}
+	// factomd keeps the database in <location>/<network>/, see State.InitLevelDB
+	if *network == "" {
+		*network = filepath.Base(filepath.Dir(filepath.Clean(path)))
+	}
+	if *location == "" {
+		*location = filepath.Dir(filepath.Dir(filepath.Clean(path)))
+	}
+
+	dbo := tools.NewDBReader(levelBolt, path)
+	defer dbo.Close()
+
+	head, err := dbo.FetchDBlockHead()
+	if err != nil || head == nil {
+		fmt.Println("No directory block head in the database", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Rolling back from %d to %d\n", head.GetDatabaseHeight(), height)
+	removed, err := dbo.RollBackTo(uint32(height))
+	fmt.Printf("\tRemoved %d directory blocks\n", removed)
+	if err != nil {
+		fmt.Println("Rolling back:", err)
+		fmt.Println("Run again to finish the rollback")
+		dbo.Close()
+		os.Exit(1)
+	}
+
+	if *anchors {
+		fmt.Printf("\tRebuilding DirBlockInfo\n")
+		if err := dbo.ReparseAnchorChains(); err != nil {
+			fmt.Println("Rebuilding DirBlockInfo:", err)
+		}
+	}
+
+	// factomd restores from a generation at or below the height and replays the blocks above it on the
+	// next boot, so the generations saved above it are all that has to go
+	s := new(state.State)
+	s.FactomNodeName = "Rollback"
+	s.DB = dbo
+	sss := &state.StateSaverStruct{FastBootLocation: *location, Generations: *generations, InDB: *inDB}
+	dropped, err := sss.RollBackSaveState(s, *network)
+	fmt.Printf("\tRemoved %d fastboot generations of %s\n", dropped, *network)
+	if err != nil {
+		fmt.Println("Removing the fastboot generations:", err)
+		dbo.Close()
+		os.Exit(1)
+	}
+
+	if *verify {
+		fmt.Printf("\tChecking the database\n")
+		problems := tools.CheckDatabase(dbo)
+		fmt.Println("")
+		if problems > 0 {
+			fmt.Printf("Found %d problems\n", problems)
+			dbo.Close()
+			os.Exit(1)
+		}
+	}
+
+	head, err = dbo.FetchDBlockHead()
+	if err != nil || head == nil {
+		fmt.Println("No directory block head after the rollback", err)
+		dbo.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Head - %d %v\n", head.GetDatabaseHeight(), head.GetKeyMR())
+}
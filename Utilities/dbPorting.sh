@@ -1,11 +1,9 @@
 echo "Porting DB"
 cd DatabasePorter
 go build
-./DatabasePorter level
-./DatabasePorter level
-./DatabasePorter level
+./DatabasePorter -db LDB -dbpath database/ldb/FactoidLevel-Import.db
 cd ..
 cd DatabaseIntegrityCheck
 ./DatabaseIntegrityCheck level ../DatabasePorter/database/ldb/FactoidLevel-Import.db
 cd ..
-echo "Done porting DB"
\ No newline at end of file
+echo "Done porting DB"
@@ -0,0 +1,597 @@
+package tools
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// CheckSet is the checks a Checker runs, any combination of them
+type CheckSet uint
+
+const (
+	CheckLinkage    CheckSet = 1 << iota // Blocks follow the ones before them, and the indexes and chain heads name them
+	CheckKeyMR                           // Blocks and entries hash to the keys they are saved under
+	CheckEntries                         // The entry blocks and entries the directory blocks name are saved
+	CheckBalances                        // Balances recomputed from the transactions don't go negative, and match BalanceFinder's
+	CheckSignatures                      // Directory blocks are signed by the authority set
+
+	CheckAll = CheckLinkage | CheckKeyMR | CheckEntries | CheckBalances | CheckSignatures
+)
+
+var checkNames = []string{"linkage", "keymr", "entries", "balances", "signatures"}
+
+// ParseChecks reads a comma separated list of check names, or all
+func ParseChecks(list string) (CheckSet, error) {
+	var checks CheckSet
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			checks |= CheckAll
+			continue
+		}
+		found := false
+		for i, n := range checkNames {
+			if n == name {
+				checks |= 1 << uint(i)
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown check %q, expected all or some of %s", name, strings.Join(checkNames, ","))
+		}
+	}
+	return checks, nil
+}
+
+// Names lists the checks of the set
+func (c CheckSet) Names() []string {
+	var names []string
+	for i, n := range checkNames {
+		if c&(1<<uint(i)) != 0 {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+const (
+	SeverityError   = "error"   // The database is damaged
+	SeverityWarning = "warning" // Looks wrong, but may be how the chain is
+)
+
+// Defect is one problem a check found
+type Defect struct {
+	Height   uint32 `json:"height"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Block    string `json:"block,omitempty"` // KeyMR, hash or address the problem is with
+	Problem  string `json:"problem"`
+	Repair   string `json:"repair"`
+}
+
+// Report is the result of a check
+type Report struct {
+	Start    uint32   `json:"start"`
+	End      uint32   `json:"end"`
+	Checks   []string `json:"checks"`
+	Heights  uint32   `json:"heights"` // Number of heights checked
+	Errors   int      `json:"errors"`
+	Warnings int      `json:"warnings"`
+	Defects  []Defect `json:"defects"`
+}
+
+// checkpoint is the progress of a check, saved to resume it from
+type checkpoint struct {
+	Start     uint32          `json:"start"`
+	End       uint32          `json:"end"`
+	Checks    []string        `json:"checks"`
+	RangeSize uint32          `json:"rangesize"`
+	Done      []uint32        `json:"done"` // First heights of the ranges checked
+	Defects   []Defect        `json:"defects"`
+	Ledger    json.RawMessage `json:"ledger,omitempty"` // Balances and authorities as far as they were recomputed
+}
+
+// ErrCheckStopped is returned by Run when asked to stop before the check is done
+var ErrCheckStopped = errors.New("check stopped")
+
+// Checker checks a database by ranges of heights, several at once.  Balances and signatures depend on
+// everything before them, so they are recomputed from height 0 in one pass beside the ranges.
+type Checker struct {
+	DB         interfaces.DBOverlay
+	Checks     CheckSet
+	Workers    int    // Ranges checked at once
+	RangeSize  uint32 // Heights in a range
+	Checkpoint string // File the progress is saved to after each range and resumed from, "" to keep none
+
+	// Balances BalanceFinder computed at the end height, compared with the ones recomputed.  nil compares none
+	ReferenceFCT map[[32]byte]int64
+	ReferenceEC  map[[32]byte]int64
+
+	// The identity and key that sign the blocks of the network before the admin blocks name the authorities
+	BootstrapIdentity interfaces.IHash
+	BootstrapKey      interfaces.IHash
+
+	mutex    sync.Mutex
+	progress checkpoint
+	err      error
+}
+
+// Run checks the heights from start to end, stopping early, with the progress saved, if stop is closed
+func (c *Checker) Run(start uint32, end uint32, stop <-chan struct{}) (*Report, error) {
+	if c.Workers < 1 {
+		c.Workers = 1
+	}
+	if c.RangeSize < 1 {
+		c.RangeSize = 1000
+	}
+	head, err := c.DB.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("no directory block head")
+	}
+	if end > head.GetDatabaseHeight() {
+		end = head.GetDatabaseHeight()
+	}
+	if start > end {
+		return nil, fmt.Errorf("nothing to check from %d to %d", start, end)
+	}
+	if err := c.load(start, end); err != nil {
+		return nil, err
+	}
+
+	done := make(map[uint32]bool)
+	for _, from := range c.progress.Done {
+		done[from] = true
+	}
+	var ranges []uint32
+	for from := uint64(start); from <= uint64(end); from += uint64(c.RangeSize) {
+		if !done[uint32(from)] {
+			ranges = append(ranges, uint32(from))
+		}
+	}
+
+	var wg sync.WaitGroup
+	work := make(chan uint32)
+	for i := 0; i < c.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for from := range work {
+				to := end
+				if uint64(from)+uint64(c.RangeSize)-1 < uint64(end) {
+					to = from + c.RangeSize - 1
+				}
+				defects, err := c.checkRange(from, to, head.GetDatabaseHeight())
+				if err != nil {
+					c.fail(fmt.Errorf("heights %d-%d: %v", from, to, err))
+					continue
+				}
+				c.finishRange(from, to, defects)
+			}
+		}()
+	}
+	if c.Checks&(CheckBalances|CheckSignatures) != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.checkLedger(start, end, stop); err != nil {
+				c.fail(err)
+			}
+		}()
+	}
+
+feed:
+	for _, from := range ranges {
+		select {
+		case work <- from:
+		case <-stop:
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	select {
+	case <-stop:
+		return nil, ErrCheckStopped
+	default:
+	}
+	return c.report(), nil
+}
+
+func (c *Checker) fail(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// load starts the progress afresh, or from the checkpoint if there is one of the same check
+func (c *Checker) load(start uint32, end uint32) error {
+	c.progress = checkpoint{Start: start, End: end, Checks: c.Checks.Names(), RangeSize: c.RangeSize}
+	if c.Checkpoint == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(c.Checkpoint)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	saved := checkpoint{}
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return fmt.Errorf("reading the checkpoint %s: %v", c.Checkpoint, err)
+	}
+	if saved.Start != start || saved.End != end || saved.RangeSize != c.RangeSize || strings.Join(saved.Checks, ",") != strings.Join(c.progress.Checks, ",") {
+		return fmt.Errorf("the checkpoint %s is of a check of %s from %d to %d by %d, remove it to start this one",
+			c.Checkpoint, strings.Join(saved.Checks, ","), saved.Start, saved.End, saved.RangeSize)
+	}
+	c.progress = saved
+	fmt.Fprintf(os.Stderr, "Resuming from %s with %d ranges checked\n", c.Checkpoint, len(saved.Done))
+	return nil
+}
+
+// save writes the progress to the checkpoint, through a temporary file so a crash doesn't leave half of
+// it.  Called with the mutex held.
+func (c *Checker) save() error {
+	if c.Checkpoint == "" {
+		return nil
+	}
+	b, err := json.Marshal(&c.progress)
+	if err != nil {
+		return err
+	}
+	tmp := c.Checkpoint + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Checkpoint)
+}
+
+func (c *Checker) finishRange(from uint32, to uint32, defects []Defect) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.progress.Done = append(c.progress.Done, from)
+	c.progress.Defects = append(c.progress.Defects, defects...)
+	if err := c.save(); err != nil && c.err == nil {
+		c.err = err
+	}
+	fmt.Fprintf(os.Stderr, "Checked heights %d-%d, %d defects\n", from, to, len(defects))
+}
+
+// finishLedger records how far the balances and authorities are recomputed
+func (c *Checker) finishLedger(ledger json.RawMessage, defects []Defect) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.progress.Ledger = ledger
+	c.progress.Defects = append(c.progress.Defects, defects...)
+	if err := c.save(); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *Checker) report() *Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r := &Report{Start: c.progress.Start, End: c.progress.End, Checks: c.progress.Checks, Defects: c.progress.Defects}
+	if r.Defects == nil {
+		r.Defects = []Defect{}
+	}
+	for _, from := range c.progress.Done {
+		to := r.End
+		if uint64(from)+uint64(c.RangeSize)-1 < uint64(r.End) {
+			to = from + c.RangeSize - 1
+		}
+		r.Heights += to - from + 1
+	}
+	sort.SliceStable(r.Defects, func(i, j int) bool {
+		if r.Defects[i].Height != r.Defects[j].Height {
+			return r.Defects[i].Height < r.Defects[j].Height
+		}
+		return r.Defects[i].Check < r.Defects[j].Check
+	})
+	for _, d := range r.Defects {
+		if d.Severity == SeverityWarning {
+			r.Warnings++
+		} else {
+			r.Errors++
+		}
+	}
+	return r
+}
+
+// resync is the repair of a height whose blocks are missing or damaged
+func resync(height uint32) string {
+	if height == 0 {
+		return "Sync the database again from scratch with DatabasePorter"
+	}
+	return fmt.Sprintf("Roll back to %d with Rollback and sync again with DatabasePorter", height-1)
+}
+
+// heightBlocks are the blocks saved at a height, nil where one is missing
+type heightBlocks struct {
+	DBlock  interfaces.IDirectoryBlock
+	ABlock  interfaces.IAdminBlock
+	FBlock  interfaces.IFBlock
+	ECBlock interfaces.IEntryCreditBlock
+}
+
+// checkRange runs the checks of single heights over a range
+func (c *Checker) checkRange(from uint32, to uint32, head uint32) ([]Defect, error) {
+	if c.Checks&(CheckLinkage|CheckKeyMR|CheckEntries) == 0 {
+		return nil, nil
+	}
+
+	var defects []Defect
+	var prev *heightBlocks
+	if from > 0 {
+		// Its problems are of the range before
+		var err error
+		prev, _, err = c.fetchHeight(from - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for h := from; h <= to && h >= from; h++ {
+		blocks, found, err := c.fetchHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		defects = append(defects, found...)
+		if c.Checks&CheckLinkage != 0 {
+			defects = append(defects, c.checkLinks(h, blocks, prev)...)
+		}
+		if c.Checks&(CheckKeyMR|CheckEntries) != 0 && blocks.DBlock != nil {
+			found, err = c.checkEntries(h, blocks.DBlock)
+			if err != nil {
+				return nil, err
+			}
+			defects = append(defects, found...)
+		}
+		prev = blocks
+	}
+	if to == head && c.Checks&CheckLinkage != 0 {
+		found, err := c.checkHeads(head)
+		if err != nil {
+			return nil, err
+		}
+		defects = append(defects, found...)
+	}
+	return defects, nil
+}
+
+// fetchHeight loads the directory, admin, factoid and entry credit blocks of a height by the KeyMRs that
+// name them, checking they are there and hash to those KeyMRs, and that the height indexes name them
+func (c *Checker) fetchHeight(h uint32) (*heightBlocks, []Defect, error) {
+	var defects []Defect
+	add := func(check string, block interfaces.IHash, problem string, args ...interface{}) {
+		d := Defect{Height: h, Check: check, Severity: SeverityError, Problem: fmt.Sprintf(problem, args...), Repair: resync(h)}
+		if block != nil {
+			d.Block = block.String()
+		}
+		defects = append(defects, d)
+	}
+
+	blocks := new(heightBlocks)
+	keyMR, err := c.DB.FetchDBKeyMRByHeight(h)
+	if err != nil {
+		return nil, nil, err
+	}
+	if keyMR == nil {
+		add("linkage", nil, "no directory block is indexed at the height")
+		return blocks, defects, nil
+	}
+	blocks.DBlock, err = c.DB.FetchDBlock(keyMR)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blocks.DBlock == nil {
+		add("linkage", keyMR, "the directory block indexed at the height is missing")
+		return blocks, defects, nil
+	}
+	if c.Checks&CheckKeyMR != 0 && !blocks.DBlock.GetKeyMR().IsSameAs(keyMR) {
+		add("keymr", keyMR, "the directory block hashes to %v", blocks.DBlock.GetKeyMR())
+	}
+	if height := blocks.DBlock.GetDatabaseHeight(); height != h {
+		add("linkage", keyMR, "the directory block indexed at the height is of height %d", height)
+	}
+
+	for _, e := range blocks.DBlock.GetDBEntries() {
+		var block interfaces.DatabaseBatchable
+		var numberBucket []byte
+		var name string
+		switch e.GetChainID().String() {
+		case "000000000000000000000000000000000000000000000000000000000000000a":
+			name, numberBucket = "admin", databaseOverlay.ADMINBLOCK_NUMBER
+			if blocks.ABlock, err = c.DB.FetchABlock(e.GetKeyMR()); blocks.ABlock != nil {
+				block = blocks.ABlock
+			}
+		case "000000000000000000000000000000000000000000000000000000000000000f":
+			name, numberBucket = "factoid", databaseOverlay.FACTOIDBLOCK_NUMBER
+			if blocks.FBlock, err = c.DB.FetchFBlock(e.GetKeyMR()); blocks.FBlock != nil {
+				block = blocks.FBlock
+			}
+		case "000000000000000000000000000000000000000000000000000000000000000c":
+			name, numberBucket = "entry credit", databaseOverlay.ENTRYCREDITBLOCK_NUMBER
+			if blocks.ECBlock, err = c.DB.FetchECBlock(e.GetKeyMR()); blocks.ECBlock != nil {
+				block = blocks.ECBlock
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if block == nil {
+			add("linkage", e.GetKeyMR(), "the %s block of the directory block is missing", name)
+			continue
+		}
+		if c.Checks&CheckKeyMR != 0 && !block.DatabasePrimaryIndex().IsSameAs(e.GetKeyMR()) {
+			add("keymr", e.GetKeyMR(), "the %s block hashes to %v", name, block.DatabasePrimaryIndex())
+		}
+		if c.Checks&CheckLinkage != 0 {
+			key := make([]byte, 4)
+			binary.BigEndian.PutUint32(key, h)
+			indexed, err := c.DB.Get(numberBucket, key, primitives.NewZeroHash())
+			if err != nil {
+				return nil, nil, err
+			}
+			if indexed == nil || !indexed.(interfaces.IHash).IsSameAs(e.GetKeyMR()) {
+				add("linkage", e.GetKeyMR(), "the %s block is not the one indexed at the height", name)
+			}
+		}
+	}
+	return blocks, defects, nil
+}
+
+// checkLinks checks the blocks of a height follow the ones before them
+func (c *Checker) checkLinks(h uint32, blocks *heightBlocks, prev *heightBlocks) []Defect {
+	if blocks.DBlock == nil || (h > 0 && (prev == nil || prev.DBlock == nil)) {
+		return nil // Reported missing
+	}
+	if prev == nil {
+		prev = new(heightBlocks)
+	}
+
+	var defects []Defect
+	add := func(block interfaces.IHash, err error) {
+		defects = append(defects, Defect{Height: h, Check: "linkage", Severity: SeverityError, Block: block.String(),
+			Problem: err.Error(), Repair: resync(h)})
+	}
+	if err := directoryBlock.CheckBlockPairIntegrity(blocks.DBlock, prev.DBlock); err != nil {
+		add(blocks.DBlock.GetKeyMR(), fmt.Errorf("directory block: %v", err))
+	}
+	if blocks.ABlock != nil && (h == 0 || prev.ABlock != nil) {
+		if err := adminBlock.CheckBlockPairIntegrity(blocks.ABlock, prev.ABlock); err != nil {
+			add(blocks.ABlock.DatabasePrimaryIndex(), fmt.Errorf("admin block: %v", err))
+		}
+	}
+	if blocks.ECBlock != nil && (h == 0 || prev.ECBlock != nil) {
+		if err := entryCreditBlock.CheckBlockPairIntegrity(blocks.ECBlock, prev.ECBlock); err != nil {
+			add(blocks.ECBlock.DatabasePrimaryIndex(), fmt.Errorf("entry credit block: %v", err))
+		}
+	}
+	if blocks.FBlock != nil && (h == 0 || prev.FBlock != nil) {
+		if err := factoid.CheckBlockPairIntegrity(blocks.FBlock, prev.FBlock); err != nil {
+			add(blocks.FBlock.DatabasePrimaryIndex(), fmt.Errorf("factoid block: %v", err))
+		}
+	}
+	return defects
+}
+
+// checkEntries checks the entry blocks of a directory block and their entries are saved, and hash to the
+// keys they are saved under
+func (c *Checker) checkEntries(h uint32, dblock interfaces.IDirectoryBlock) ([]Defect, error) {
+	var defects []Defect
+	add := func(check string, block interfaces.IHash, problem string, args ...interface{}) {
+		defects = append(defects, Defect{Height: h, Check: check, Severity: SeverityError, Block: block.String(),
+			Problem: fmt.Sprintf(problem, args...), Repair: resync(h)})
+	}
+
+	for _, e := range dblock.GetEBlockDBEntries() {
+		eblock, err := c.DB.FetchEBlock(e.GetKeyMR())
+		if err != nil {
+			return nil, err
+		}
+		if eblock == nil {
+			if c.Checks&CheckEntries != 0 {
+				add("entries", e.GetKeyMR(), "the entry block of chain %v is missing", e.GetChainID())
+			}
+			continue
+		}
+		if c.Checks&CheckKeyMR != 0 {
+			if keyMR, err := eblock.KeyMR(); err != nil || !keyMR.IsSameAs(e.GetKeyMR()) {
+				add("keymr", e.GetKeyMR(), "the entry block hashes to %v", keyMR)
+			}
+		}
+		if c.Checks&CheckEntries != 0 && !eblock.GetHeader().GetChainID().IsSameAs(e.GetChainID()) {
+			add("entries", e.GetKeyMR(), "the entry block is of chain %v, not %v", eblock.GetHeader().GetChainID(), e.GetChainID())
+		}
+
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			entry, err := c.DB.FetchEntry(hash)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				if c.Checks&CheckEntries != 0 {
+					add("entries", hash, "the entry of entry block %v is missing", e.GetKeyMR())
+				}
+				continue
+			}
+			if c.Checks&CheckKeyMR != 0 && !entry.GetHash().IsSameAs(hash) {
+				add("keymr", hash, "the entry hashes to %v", entry.GetHash())
+			}
+			if c.Checks&CheckEntries != 0 && !entry.GetChainIDHash().IsSameAs(eblock.GetHeader().GetChainID()) {
+				add("entries", hash, "the entry is of chain %v, not %v", entry.GetChainIDHash(), eblock.GetHeader().GetChainID())
+			}
+		}
+	}
+	return defects, nil
+}
+
+// checkHeads checks the chain heads of the directory, admin, factoid and entry credit chains are the blocks
+// at the head, and that no directory block is indexed above it
+func (c *Checker) checkHeads(head uint32) ([]Defect, error) {
+	var defects []Defect
+	dblock, err := c.DB.FetchDBlockByHeight(head)
+	if err != nil || dblock == nil {
+		return nil, err
+	}
+	heads := []interfaces.IDBEntry{&directoryBlock.DBEntry{ChainID: dblock.GetChainID(), KeyMR: dblock.GetKeyMR()}}
+	for _, e := range dblock.GetDBEntries() {
+		switch e.GetChainID().String() {
+		case "000000000000000000000000000000000000000000000000000000000000000a",
+			"000000000000000000000000000000000000000000000000000000000000000c",
+			"000000000000000000000000000000000000000000000000000000000000000f":
+			heads = append(heads, e)
+		}
+	}
+	for _, e := range heads {
+		index, err := c.DB.FetchHeadIndexByChainID(e.GetChainID())
+		if err != nil {
+			return nil, err
+		}
+		if index == nil || !index.IsSameAs(e.GetKeyMR()) {
+			defects = append(defects, Defect{Height: head, Check: "linkage", Severity: SeverityError, Block: e.GetKeyMR().String(),
+				Problem: fmt.Sprintf("the head of chain %v is %v, not the block at the head", e.GetChainID(), index),
+				Repair:  "Reset the chain heads with FixBlockHeads"})
+		}
+	}
+
+	above, err := c.DB.FetchDBKeyMRByHeight(head + 1)
+	if err != nil {
+		return nil, err
+	}
+	if above != nil {
+		defects = append(defects, Defect{Height: head + 1, Check: "linkage", Severity: SeverityError, Block: above.String(),
+			Problem: "a directory block is indexed above the head",
+			Repair:  fmt.Sprintf("Roll back to %d with Rollback, or reset the head with SetChainHead if the blocks above it are whole", head)})
+	}
+	return defects, nil
+}
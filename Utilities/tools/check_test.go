@@ -0,0 +1,211 @@
+package tools_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/Utilities/tools"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks("linkage, KeyMR")
+	if err != nil || checks != CheckLinkage|CheckKeyMR {
+		t.Errorf("Parsed %v, %v", checks, err)
+	}
+	if checks, err = ParseChecks("all"); err != nil || checks != CheckAll {
+		t.Errorf("Parsed %v, %v", checks, err)
+	}
+	if strings.Join(CheckAll.Names(), ",") != "linkage,keymr,entries,balances,signatures" {
+		t.Errorf("Names are %v", CheckAll.Names())
+	}
+	if _, err = ParseChecks("linkage,speed"); err == nil {
+		t.Error("Parsed an unknown check")
+	}
+}
+
+func TestCheckerFindsNoDefects(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	c := &Checker{DB: dbo, Checks: CheckLinkage | CheckKeyMR | CheckEntries, Workers: 3, RangeSize: 2}
+	r, err := c.Run(0, math.MaxUint32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Errors != 0 || r.Warnings != 0 {
+		t.Errorf("Found %v in a whole database", r.Defects)
+	}
+	if r.End != uint32(testHelper.BlockCount-1) || r.Heights != uint32(testHelper.BlockCount) {
+		t.Errorf("Checked %d heights to %d, expected %d", r.Heights, r.End, testHelper.BlockCount)
+	}
+
+	// The balances and signatures are only recomputed, nothing is compared
+	c = &Checker{DB: dbo, Checks: CheckAll, Workers: 2, RangeSize: 3}
+	if _, err := c.Run(0, math.MaxUint32, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckerReportsMissingEntry(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	height := uint32(testHelper.BlockCount / 2)
+	dblock, err := dbo.FetchDBlockByHeight(height)
+	if err != nil || dblock == nil {
+		t.Fatal("No directory block", err)
+	}
+	var missing string
+	for _, e := range dblock.GetEBlockDBEntries() {
+		eblock, err := dbo.FetchEBlock(e.GetKeyMR())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, hash := range eblock.GetEntryHashes() {
+			if missing == "" && !hash.IsMinuteMarker() {
+				if err := dbo.DeleteEntry(hash); err != nil {
+					t.Fatal(err)
+				}
+				missing = hash.String()
+			}
+		}
+	}
+	if missing == "" {
+		t.Fatal("No entry to remove")
+	}
+
+	c := &Checker{DB: dbo, Checks: CheckEntries, Workers: 2, RangeSize: 4}
+	r, err := c.Run(0, math.MaxUint32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Errors != 1 {
+		t.Fatalf("Found %v, expected the missing entry", r.Defects)
+	}
+	d := r.Defects[0]
+	if d.Height != height || d.Check != "entries" || d.Block != missing || !strings.Contains(d.Repair, "Rollback") {
+		t.Errorf("Reported %+v", d)
+	}
+}
+
+func TestCheckerResumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	stop := make(chan struct{})
+	close(stop)
+	c := &Checker{DB: dbo, Checks: CheckLinkage | CheckBalances, RangeSize: 2, Checkpoint: checkpoint}
+	if _, err := c.Run(0, math.MaxUint32, stop); err != ErrCheckStopped {
+		t.Fatalf("Stopped with %v", err)
+	}
+	if _, err := os.Stat(checkpoint); err != nil {
+		t.Fatal("No checkpoint saved", err)
+	}
+
+	// A different check doesn't take up the checkpoint
+	c = &Checker{DB: dbo, Checks: CheckLinkage, RangeSize: 2, Checkpoint: checkpoint}
+	if _, err := c.Run(0, math.MaxUint32, nil); err == nil {
+		t.Error("Resumed a different check")
+	}
+
+	c = &Checker{DB: dbo, Checks: CheckLinkage | CheckBalances, RangeSize: 2, Checkpoint: checkpoint}
+	r, err := c.Run(0, math.MaxUint32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Heights != uint32(testHelper.BlockCount) || r.Errors != 0 {
+		t.Errorf("Checked %d heights with %v", r.Heights, r.Defects)
+	}
+}
+
+func TestCheckerComparesBalances(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	var unknown [32]byte
+	unknown[0] = 1
+	c := &Checker{DB: dbo, Checks: CheckBalances, ReferenceFCT: map[[32]byte]int64{unknown: 5}}
+	r, err := c.Run(0, math.MaxUint32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(unknown[:]))
+	found := false
+	for _, d := range r.Defects {
+		if d.Check == "balances" && d.Block == user && d.Severity == SeverityError {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("The balance of %s was not reported in %v", user, r.Defects)
+	}
+}
+
+func TestCheckerStopsLedgerAtMissingBlock(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	height := uint32(testHelper.BlockCount / 2)
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, height)
+	if err := dbo.Delete(databaseOverlay.DIRECTORYBLOCK_NUMBER, key); err != nil {
+		t.Fatal(err)
+	}
+
+	var unknown [32]byte
+	unknown[0] = 1
+	c := &Checker{DB: dbo, Checks: CheckBalances, ReferenceFCT: map[[32]byte]int64{unknown: 5}}
+	r, err := c.Run(0, math.MaxUint32, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The missing block, and no balances compared at the end height they were not recomputed to
+	user := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(unknown[:]))
+	missing := 0
+	for _, d := range r.Defects {
+		if d.Severity != SeverityError {
+			continue
+		}
+		if d.Height != height || d.Check != "balances" || d.Block == user {
+			t.Errorf("Reported %+v", d)
+		}
+		missing++
+	}
+	if missing != 1 {
+		t.Errorf("Reported the missing block %d times", missing)
+	}
+}
+
+func TestReadBalances(t *testing.T) {
+	var addr [32]byte
+	addr[31] = 7
+	fa := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(addr[:]))
+	ec := primitives.ConvertECAddressToUserStr(factoid.NewAddress(addr[:]))
+
+	f, err := ioutil.TempFile("", "balances")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(fa + ": 12.00000034\n" + ec + ": 56\n")
+	f.Close()
+
+	fct, ecs, err := ReadBalances(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fct[addr] != 1200000034 || ecs[addr] != 56 {
+		t.Errorf("Read %d FCT and %d EC", fct[addr], ecs[addr])
+	}
+
+	ioutil.WriteFile(f.Name(), []byte(fa+": 1.5.5\n"), 0644)
+	if _, _, err := ReadBalances(f.Name()); err == nil {
+		t.Error("Read a bad amount")
+	}
+}
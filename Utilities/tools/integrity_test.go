@@ -1,9 +1,10 @@
-package main
+package tools_test
 
 import (
 	"os"
 	"testing"
 
+	. "github.com/FactomProject/factomd/Utilities/tools"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
@@ -12,7 +13,9 @@ import (
 
 func TestCheckDatabaseFromDBO(t *testing.T) {
 	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
-	CheckDatabase(dbo)
+	if problems := CheckDatabase(dbo); problems != 0 {
+		t.Errorf("Found %d problems in a whole database", problems)
+	}
 }
 
 func TestCheckDatabaseFromState(t *testing.T) {
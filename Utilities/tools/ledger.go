@@ -0,0 +1,416 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// ledger is what the balance and signature checks recompute from height 0, saved in the checkpoint.  The
+// maps are keyed by hex so they marshal to JSON.
+type ledger struct {
+	Height      uint32              `json:"height"` // Next height to recompute
+	FCT         map[string]int64    `json:"fct"`
+	EC          map[string]int64    `json:"ec"`
+	Federated   map[string]bool     `json:"federated"`       // Identities of the federated servers
+	SigningKeys map[string][]string `json:"signingkeys"`     // Keys the admin blocks gave each identity
+	Stuck       bool                `json:"stuck,omitempty"` // Stopped at a missing directory block, already reported
+}
+
+// checkLedger recomputes the balances and authority set from height 0 to end, reporting the problems at
+// start and above
+func (c *Checker) checkLedger(start uint32, end uint32, stop <-chan struct{}) error {
+	l := ledger{FCT: map[string]int64{}, EC: map[string]int64{}, Federated: map[string]bool{}, SigningKeys: map[string][]string{}}
+	c.mutex.Lock()
+	saved := c.progress.Ledger
+	c.mutex.Unlock()
+	if len(saved) > 0 {
+		if err := json.Unmarshal(saved, &l); err != nil {
+			return fmt.Errorf("reading the balances of the checkpoint: %v", err)
+		}
+	}
+	if l.Height > end {
+		return nil // Done before it stopped
+	}
+
+	var defects []Defect
+	var prev interfaces.IDirectoryBlock
+	for ; l.Height <= end; l.Height++ {
+		h := l.Height
+		select {
+		case <-stop:
+			return c.saveLedger(&l, defects)
+		default:
+		}
+
+		dblock, err := c.DB.FetchDBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if dblock == nil {
+			// Nothing above it can be recomputed, so the ledger stops here, unfinished, and the balances are
+			// not compared with the reference at the end.  It is reported even below start, as it leaves
+			// everything above it unchecked, but only once when resumed.
+			err = c.saveLedger(&l, defects)
+			if err != nil || l.Stuck {
+				return err
+			}
+			l.Stuck = true
+			b, err := json.Marshal(&l)
+			if err != nil {
+				return err
+			}
+			c.finishLedger(b, []Defect{{Height: h, Check: c.ledgerCheck(), Severity: SeverityError,
+				Problem: fmt.Sprintf("the directory block is missing, the heights from %d to %d are not recomputed", h, end),
+				Repair:  resync(h)}})
+			return nil
+		}
+		l.Stuck = false
+
+		var found []Defect
+		if c.Checks&CheckBalances != 0 {
+			found, err = c.checkBalances(&l, dblock)
+			if err != nil {
+				return err
+			}
+			defects = append(defects, found...)
+		}
+		if c.Checks&CheckSignatures != 0 {
+			found, err = c.checkSignatures(&l, dblock, prev)
+			if err != nil {
+				return err
+			}
+			defects = append(defects, found...)
+		}
+		prev = dblock
+
+		if h%c.RangeSize == c.RangeSize-1 {
+			if err := c.saveLedger(&l, defects); err != nil {
+				return err
+			}
+			defects = nil
+			fmt.Fprintf(os.Stderr, "Recomputed the balances and authorities to %d\n", h)
+		}
+	}
+
+	if c.Checks&CheckBalances != 0 {
+		defects = append(defects, c.compareBalances(&l, end)...)
+	}
+	l.Height = end + 1
+	return c.saveLedger(&l, defects)
+}
+
+// ledgerCheck names the check the ledger is stopped for
+func (c *Checker) ledgerCheck() string {
+	if c.Checks&CheckBalances != 0 {
+		return "balances"
+	}
+	return "signatures"
+}
+
+// saveLedger records the ledger, with the defects found since it was last saved, in the checkpoint.  The
+// height kept is the next one to recompute, so one that was only partly recomputed is done again.
+func (c *Checker) saveLedger(l *ledger, defects []Defect) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	var kept []Defect
+	for _, d := range defects {
+		if d.Height >= c.progress.Start {
+			kept = append(kept, d)
+		}
+	}
+	c.finishLedger(b, kept)
+	return nil
+}
+
+// checkBalances applies the transactions and commits of a height to the balances as BalanceFinder does,
+// warning of the balances they take below zero
+func (c *Checker) checkBalances(l *ledger, dblock interfaces.IDirectoryBlock) ([]Defect, error) {
+	h := dblock.GetDatabaseHeight()
+	var defects []Defect
+	spend := func(balances map[string]int64, addr [32]byte, amount int64, user string) {
+		key := hex.EncodeToString(addr[:])
+		was := balances[key]
+		balances[key] = was - amount
+		if was >= 0 && balances[key] < 0 {
+			defects = append(defects, Defect{Height: h, Check: "balances", Severity: SeverityWarning, Block: user,
+				Problem: fmt.Sprintf("the balance goes below zero to %d", balances[key]),
+				Repair:  fmt.Sprintf("Check the blocks of the address at %d against a trusted node, rolling back below them and syncing again if they differ", h)})
+		}
+	}
+
+	fblock, err := c.DB.FetchFBlockByHeight(h)
+	if err != nil {
+		return nil, err
+	}
+	if fblock == nil {
+		return []Defect{{Height: h, Check: "balances", Severity: SeverityError,
+			Problem: "the factoid block is missing, the balances above it are recomputed without it", Repair: resync(h)}}, nil
+	}
+	for _, t := range fblock.GetTransactions() {
+		for _, input := range t.GetInputs() {
+			addr := input.GetAddress().Fixed()
+			spend(l.FCT, addr, int64(input.GetAmount()), primitives.ConvertFctAddressToUserStr(factoid.NewAddress(addr[:])))
+		}
+		for _, output := range t.GetOutputs() {
+			addr := output.GetAddress().Fixed()
+			l.FCT[hex.EncodeToString(addr[:])] += int64(output.GetAmount())
+		}
+		for _, output := range t.GetECOutputs() {
+			addr := output.GetAddress().Fixed()
+			l.EC[hex.EncodeToString(addr[:])] += int64(output.GetAmount() / fblock.GetExchRate())
+		}
+	}
+
+	var ecblock interfaces.IEntryCreditBlock
+	for _, e := range dblock.GetDBEntries() {
+		if e.GetChainID().String() == "000000000000000000000000000000000000000000000000000000000000000c" {
+			ecblock, err = c.DB.FetchECBlock(e.GetKeyMR())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ecblock == nil {
+		// Mainnet never had the entry credit blocks of 70386 to 70410, BalanceFinder skips them too
+		return defects, nil
+	}
+	for _, entry := range ecblock.GetBody().GetEntries() {
+		var pub *primitives.ByteSlice32
+		var credits uint8
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			commit := entry.(*entryCreditBlock.CommitChain)
+			pub, credits = commit.ECPubKey, commit.Credits
+		case constants.ECIDEntryCommit:
+			commit := entry.(*entryCreditBlock.CommitEntry)
+			pub, credits = commit.ECPubKey, commit.Credits
+		default:
+			continue
+		}
+		addr := pub.Fixed()
+		spend(l.EC, addr, int64(credits), primitives.ConvertECAddressToUserStr(factoid.NewAddress(addr[:])))
+	}
+	return defects, nil
+}
+
+// compareBalances reports the addresses whose recomputed balance differs from the reference
+func (c *Checker) compareBalances(l *ledger, end uint32) []Defect {
+	var defects []Defect
+	compare := func(recomputed map[string]int64, reference map[[32]byte]int64, user func([]byte) string) {
+		if reference == nil {
+			return
+		}
+		keys := map[[32]byte]bool{}
+		for k := range reference {
+			keys[k] = true
+		}
+		for k := range recomputed {
+			var addr [32]byte
+			b, _ := hex.DecodeString(k)
+			copy(addr[:], b)
+			keys[addr] = true
+		}
+		for addr := range keys {
+			got, want := recomputed[hex.EncodeToString(addr[:])], reference[addr]
+			if got != want {
+				defects = append(defects, Defect{Height: end, Check: "balances", Severity: SeverityError, Block: user(addr[:]),
+					Problem: fmt.Sprintf("the balance is %d, the reference has %d", got, want),
+					Repair:  "Find the first height the balances differ at with the balance hashes of BalanceFinder -h, roll back below it with Rollback and sync again with DatabasePorter"})
+			}
+		}
+	}
+	compare(l.FCT, c.ReferenceFCT, func(addr []byte) string {
+		return primitives.ConvertFctAddressToUserStr(factoid.NewAddress(addr))
+	})
+	compare(l.EC, c.ReferenceEC, func(addr []byte) string {
+		return primitives.ConvertECAddressToUserStr(factoid.NewAddress(addr))
+	})
+	sort.SliceStable(defects, func(i, j int) bool { return defects[i].Block < defects[j].Block })
+	return defects
+}
+
+// checkSignatures verifies the directory block signatures of the admin block of a height, which sign the
+// header of the directory block before it, against the authority set, then applies the changes the admin
+// block makes to the set.  factomd accepts blocks with a majority of the signatures, so missing and invalid
+// ones are warnings.
+func (c *Checker) checkSignatures(l *ledger, dblock interfaces.IDirectoryBlock, prev interfaces.IDirectoryBlock) ([]Defect, error) {
+	h := dblock.GetDatabaseHeight()
+	var defects []Defect
+	add := func(severity string, block string, problem string, args ...interface{}) {
+		defects = append(defects, Defect{Height: h, Check: "signatures", Severity: severity, Block: block,
+			Problem: fmt.Sprintf(problem, args...), Repair: resync(h)})
+	}
+
+	ablock, err := c.DB.FetchABlockByHeight(h)
+	if err != nil {
+		return nil, err
+	}
+	if ablock == nil {
+		add(SeverityError, "", "the admin block is missing, the authority set above it is recomputed without it")
+		return defects, nil
+	}
+
+	if h > 0 {
+		if prev == nil {
+			if prev, err = c.DB.FetchDBlockByHeight(h - 1); err != nil {
+				return nil, err
+			}
+		}
+		var header []byte
+		if prev != nil {
+			if header, err = prev.GetHeader().MarshalBinary(); err != nil {
+				return nil, err
+			}
+		}
+
+		valid := map[string]bool{}
+		for _, e := range ablock.GetABEntries() {
+			if e.Type() != constants.TYPE_DB_SIGNATURE {
+				continue
+			}
+			sig := e.(*adminBlock.DBSignatureEntry)
+			identity := sig.IdentityAdminChainID.String()
+			key := hex.EncodeToString(sig.PrevDBSig.GetKey())
+			if header != nil && !sig.PrevDBSig.Verify(header) {
+				add(SeverityWarning, identity, "the signature of %s does not sign the directory block before it", key)
+				continue
+			}
+			if c.isAuthority(l, identity, key) {
+				valid[identity] = true
+			} else {
+				add(SeverityWarning, identity, "signed with %s, which is not a key of a federated server", key)
+			}
+		}
+		needed := len(l.Federated)/2 + 1
+		if len(l.Federated) == 0 {
+			needed = 1
+		}
+		if len(valid) < needed {
+			add(SeverityWarning, ablock.DatabasePrimaryIndex().String(), "signed by %d of the %d federated servers, %d are needed",
+				len(valid), len(l.Federated), needed)
+		}
+	}
+
+	for _, e := range ablock.GetABEntries() {
+		switch e.Type() {
+		case constants.TYPE_ADD_FED_SERVER:
+			l.Federated[e.(*adminBlock.AddFederatedServer).IdentityChainID.String()] = true
+		case constants.TYPE_ADD_AUDIT_SERVER:
+			delete(l.Federated, e.(*adminBlock.AddAuditServer).IdentityChainID.String())
+		case constants.TYPE_REMOVE_FED_SERVER:
+			delete(l.Federated, e.(*adminBlock.RemoveFederatedServer).IdentityChainID.String())
+		case constants.TYPE_ADD_FED_SERVER_KEY:
+			k := e.(*adminBlock.AddFederatedServerSigningKey)
+			identity := k.IdentityChainID.String()
+			// Blocks signed before the key changed are still checked against the keys before it
+			l.SigningKeys[identity] = append(l.SigningKeys[identity], hex.EncodeToString(k.PublicKey[:]))
+		}
+	}
+	return defects, nil
+}
+
+// isAuthority is true for the bootstrap key signing as the bootstrap identity, and for the keys of the
+// federated servers
+func (c *Checker) isAuthority(l *ledger, identity string, key string) bool {
+	if c.BootstrapKey != nil && key == hex.EncodeToString(c.BootstrapKey.Bytes()) &&
+		(c.BootstrapIdentity == nil || c.BootstrapIdentity.IsZero() || identity == c.BootstrapIdentity.String()) {
+		return true
+	}
+	if !l.Federated[identity] {
+		return false
+	}
+	for _, k := range l.SigningKeys[identity] {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadBalances reads the addresses and balances BalanceFinder writes, FCT ones in factoshis
+func ReadBalances(filename string) (fct map[[32]byte]int64, ec map[[32]byte]int64, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fct = make(map[[32]byte]int64)
+	ec = make(map[[32]byte]int64)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("%s:%d: expected address: balance", filename, line)
+		}
+		user, amount := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		var addr [32]byte
+		switch {
+		case primitives.ValidateFUserStr(user):
+			copy(addr[:], primitives.ConvertUserStrToAddress(user))
+			v, err := parseFactoshis(amount)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+			}
+			fct[addr] = v
+		case primitives.ValidateECUserStr(user):
+			copy(addr[:], primitives.ConvertUserStrToAddress(user))
+			v, err := strconv.ParseInt(amount, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+			}
+			ec[addr] = v
+		default:
+			return nil, nil, fmt.Errorf("%s:%d: %q is not an FCT or EC address", filename, line, user)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return fct, ec, nil
+}
+
+// parseFactoshis reads an amount of FCT with up to 8 decimals as factoshis
+func parseFactoshis(amount string) (int64, error) {
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+	parts := strings.SplitN(amount, ".", 2)
+	whole, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad amount %q", amount)
+	}
+	var fraction int64
+	if len(parts) == 2 {
+		if len(parts[1]) == 0 || len(parts[1]) > 8 {
+			return 0, fmt.Errorf("bad amount %q", amount)
+		}
+		fraction, err = strconv.ParseInt(parts[1]+strings.Repeat("0", 8-len(parts[1])), 10, 64)
+		if err != nil || fraction < 0 {
+			return 0, fmt.Errorf("bad amount %q", amount)
+		}
+	}
+	v := whole*1e8 + fraction
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
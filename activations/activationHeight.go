@@ -67,6 +67,13 @@ func init() {
 		ActivationMap[a.Id] = a
 		ActivationNameMap[a.Id] = a.Name
 	}
+	for _, a := range activations {
+		for network := range a.ActivationHeight {
+			if err := Validate(network); err != nil {
+				panic(err)
+			}
+		}
+	}
 }
 
 // String converts an Activation ID to a name
@@ -0,0 +1,203 @@
+package activations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The heights compiled in above can be overridden, and heights added for other networks, from the
+// ActivationHeights setting of factomd.conf (for the network the node is on) and from an ActivationFile
+// (for any network).  Either way the schedule must be monotonic: an activation may not come before one
+// with a lower ActivationType on the same network.
+
+// Never is the height of an activation that is not active on a network
+const Never = math.MaxInt32
+
+// ScheduledActivation is an activation as scheduled for one network
+type ScheduledActivation struct {
+	Name        string `json:"name"`
+	Id          int    `json:"id"`
+	Description string `json:"description"`
+	Height      int    `json:"height"` // Never if it doesn't activate
+	Never       bool   `json:"never"`
+}
+
+// ByName returns the ActivationType of an activation name
+func ByName(name string) (ActivationType, bool) {
+	for id, n := range ActivationNameMap {
+		if strings.EqualFold(n, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// height of an activation on a network
+func (a Activation) height(network string) int {
+	if h, ok := a.ActivationHeight[network]; ok {
+		return h
+	}
+	return a.DefaultHeight
+}
+
+// Validate checks the activations of a network come in the order of their ActivationTypes
+func Validate(network string) error {
+	return validate(ActivationMap, network)
+}
+
+func validate(activations map[ActivationType]Activation, network string) error {
+	last, lastName := 0, ""
+	for id := ActivationType(1); id <= ACTIVATION_TYPE_COUNT; id++ {
+		a, ok := activations[id]
+		if !ok {
+			return fmt.Errorf("activation %d is missing", id)
+		}
+		h := a.height(network)
+		if h < 0 {
+			return fmt.Errorf("activation %s has a negative height %d on %s", a.Name, h, network)
+		}
+		if h == Never {
+			continue
+		}
+		if h < last {
+			return fmt.Errorf("activation %s at %d on %s comes before %s at %d", a.Name, h, network, lastName, last)
+		}
+		last, lastName = h, a.Name
+	}
+	return nil
+}
+
+// activationHeight is a height in a schedule, a number or "never"
+type activationHeight int
+
+func (h *activationHeight) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := parseHeight(s)
+		*h = activationHeight(v)
+		return err
+	}
+	var v int
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("activation height %s is not a number or \"never\"", string(data))
+	}
+	*h = activationHeight(v)
+	return nil
+}
+
+func parseHeight(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "never") {
+		return Never, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("activation height %q is not a number or \"never\"", s)
+	}
+	return v, nil
+}
+
+// ParseHeights parses the ActivationHeights setting, Name=height pairs separated by commas
+func ParseHeights(heights string) (map[string]int, error) {
+	rval := make(map[string]int)
+	for _, pair := range strings.Split(heights, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("activation %q is not Name=height", pair)
+		}
+		h, err := parseHeight(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		rval[strings.TrimSpace(parts[0])] = h
+	}
+	return rval, nil
+}
+
+// LoadSchedule applies the activation heights of the file (JSON, network name to activation name to
+// height) and then those of the ActivationHeights setting for the network the node is on.  Nothing is
+// changed if any of them is unknown or leaves a network's schedule out of order.
+func LoadSchedule(heights string, file string) error {
+	overrides := make(map[string]map[string]int)
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var schedule map[string]map[string]activationHeight
+		if err := json.Unmarshal(data, &schedule); err != nil {
+			return fmt.Errorf("activation file %s: %v", file, err)
+		}
+		for network, list := range schedule {
+			overrides[network] = make(map[string]int)
+			for name, h := range list {
+				overrides[network][name] = int(h)
+			}
+		}
+	}
+	if heights != "" {
+		list, err := ParseHeights(heights)
+		if err != nil {
+			return err
+		}
+		network := networkname()
+		if overrides[network] == nil {
+			overrides[network] = make(map[string]int)
+		}
+		for name, h := range list {
+			overrides[network][name] = h
+		}
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	// Apply to a copy, so a bad schedule changes nothing
+	updated := make(map[ActivationType]Activation, len(ActivationMap))
+	for id, a := range ActivationMap {
+		a.ActivationHeight = copyHeights(a.ActivationHeight)
+		updated[id] = a
+	}
+	for network, list := range overrides {
+		for name, h := range list {
+			id, ok := ByName(name)
+			if !ok {
+				return fmt.Errorf("unknown activation %q for %s", name, network)
+			}
+			updated[id].ActivationHeight[network] = h
+		}
+		if err := validate(updated, network); err != nil {
+			return err
+		}
+	}
+	ActivationMap = updated
+	return nil
+}
+
+func copyHeights(heights map[string]int) map[string]int {
+	rval := make(map[string]int, len(heights))
+	for n, h := range heights {
+		rval[n] = h
+	}
+	return rval
+}
+
+// Schedule returns the activations of the network the node is on, in ActivationType order
+func Schedule() []ScheduledActivation {
+	network := networkname()
+	var rval []ScheduledActivation
+	for id, a := range ActivationMap {
+		h := a.height(network)
+		rval = append(rval, ScheduledActivation{a.Name, int(id), a.Description, h, h == Never})
+	}
+	sort.Slice(rval, func(i, j int) bool { return rval[i].Id < rval[j].Id })
+	return rval
+}
@@ -0,0 +1,84 @@
+package activations_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/activations"
+)
+
+func writeSchedule(t *testing.T, schedule string) string {
+	f, err := ioutil.TempFile("", "activations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(schedule); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestParseHeights(t *testing.T) {
+	heights, err := ParseHeights("AuthorityMaxDelta=100, TestNetCoinBasePeriod=never")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if heights["AuthorityMaxDelta"] != 100 || heights["TestNetCoinBasePeriod"] != Never {
+		t.Errorf("Wrong heights %v", heights)
+	}
+	for _, bad := range []string{"AuthorityMaxDelta", "AuthorityMaxDelta=soon"} {
+		if _, err := ParseHeights(bad); err == nil {
+			t.Errorf("Accepted %q", bad)
+		}
+	}
+}
+
+func TestLoadSchedule(t *testing.T) {
+	saved := ActivationMap
+	defer func() { ActivationMap = saved }()
+
+	file := writeSchedule(t, `{"CUSTOM:unittest": {"TestNetCoinBasePeriod": 10, "AuthorityMaxDelta": "20"}}`)
+	defer os.Remove(file)
+	if err := LoadSchedule("", file); err != nil {
+		t.Fatal(err)
+	}
+	if h := ActivationMap[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["CUSTOM:unittest"]; h != 20 {
+		t.Errorf("AuthorityMaxDelta at %d, expected 20", h)
+	}
+	if h := saved[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["MAIN"]; h != 222874 {
+		t.Errorf("The compiled in schedule changed, MAIN AuthorityMaxDelta at %d", h)
+	}
+
+	for _, bad := range []string{
+		`{"CUSTOM:unittest": {"NoSuchActivation": 10}}`,
+		`{"CUSTOM:unittest": {"TestNetCoinBasePeriod": 30, "AuthorityMaxDelta": 20}}`, // Out of order
+		`{"CUSTOM:unittest": {"AuthorityMaxDelta": -1}}`,
+	} {
+		before := ActivationMap
+		file := writeSchedule(t, bad)
+		if err := LoadSchedule("", file); err == nil {
+			t.Errorf("Accepted %s", bad)
+		}
+		os.Remove(file)
+		if h := ActivationMap[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["CUSTOM:unittest"]; h != before[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["CUSTOM:unittest"] {
+			t.Errorf("A bad schedule changed AuthorityMaxDelta to %d", h)
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	schedule := Schedule()
+	if len(schedule) != ACTIVATION_TYPE_COUNT {
+		t.Fatalf("Expected %d activations, got %d", ACTIVATION_TYPE_COUNT, len(schedule))
+	}
+	for i, a := range schedule {
+		if a.Id != i+1 {
+			t.Errorf("Activation %s is out of order", a.Name)
+		}
+		if a.Never != (a.Height == Never) {
+			t.Errorf("Activation %s never is %v at height %d", a.Name, a.Never, a.Height)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The anchor chains are created with a single external ID, their name.
+const (
+	BitcoinAnchorChainName  = "FactomAnchorChain"
+	EthereumAnchorChainName = "FactomEthereumAnchorChain"
+)
+
+// Ledgers we can write anchor records for.  The ledger decides which anchor chain the records go into, and
+// which part (Bitcoin or Ethereum) of the AnchorRecord a backend fills in.
+const (
+	LedgerBitcoin  = "bitcoin"
+	LedgerEthereum = "ethereum"
+)
+
+// Backend writes a directory block KeyMR into some external ledger.  Anchor is called once per directory
+// block, in height order, and returns an AnchorRecord with the Bitcoin or Ethereum details filled in.
+type Backend interface {
+	Name() string   // Name used to select the backend in the config file
+	Ledger() string // LedgerBitcoin or LedgerEthereum
+	Anchor(dbheight uint32, keyMR interfaces.IHash) (*AnchorRecord, error)
+	Close() error
+}
+
+// BackendConfig is what a backend is handed when it is created
+type BackendConfig struct {
+	Ledger string // LedgerBitcoin or LedgerEthereum
+	Path   string // Backend specific location, i.e. a file or a URL
+}
+
+// BackendFactory creates a Backend
+type BackendFactory func(cfg BackendConfig) (Backend, error)
+
+var backendsMutex sync.Mutex
+var backends = map[string]BackendFactory{}
+
+// RegisterBackend makes an anchor backend available by name.  Backends usually register themselves in init()
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("anchor backend %s registered twice", name))
+	}
+	backends[name] = factory
+}
+
+// NewBackend creates the named anchor backend
+func NewBackend(name string, cfg BackendConfig) (Backend, error) {
+	if cfg.Ledger != LedgerBitcoin && cfg.Ledger != LedgerEthereum {
+		return nil, fmt.Errorf("unknown anchor ledger %q", cfg.Ledger)
+	}
+	backendsMutex.Lock()
+	factory, ok := backends[name]
+	backendsMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown anchor backend %q, have %v", name, BackendNames())
+	}
+	return factory(cfg)
+}
+
+// BackendNames returns the names of all the registered backends
+func BackendNames() []string {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+	var names []string
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AnchorChainName returns the external ID used to create the anchor chain of a ledger
+func AnchorChainName(ledger string) string {
+	if ledger == LedgerEthereum {
+		return EthereumAnchorChainName
+	}
+	return BitcoinAnchorChainName
+}
+
+// AnchorChainID returns the chain ID of the anchor chain of a ledger
+func AnchorChainID(ledger string) interfaces.IHash {
+	name := primitives.Sha([]byte(AnchorChainName(ledger)))
+	return primitives.Sha(name.Bytes())
+}
+
+// NewAnchorRecordV2 creates a version 2 AnchorRecord for a single directory block
+func NewAnchorRecordV2(dbheight uint32, keyMR interfaces.IHash) *AnchorRecord {
+	ar := new(AnchorRecord)
+	ar.AnchorRecordVer = 2
+	ar.DBHeight = dbheight
+	ar.KeyMR = keyMR.String()
+	ar.RecordHeight = dbheight + 1
+	return ar
+}
+
+// SignedEntryParts returns the content and external IDs of a version 2 anchor record entry
+func (ar *AnchorRecord) SignedEntryParts(priv interfaces.Signer) (content []byte, extIDs [][]byte, err error) {
+	content, sig, err := ar.MarshalAndSignV2(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, [][]byte{sig}, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package anchor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// FileBackend is a "mock chain" anchor backend.  Each anchor is a block appended to a local file, one JSON
+// object per line, with every block linked to the one before it.  It lets private networks and tests run
+// the whole anchoring path without a Bitcoin or Ethereum node.
+type FileBackend struct {
+	mutex    sync.Mutex
+	ledger   string
+	path     string
+	file     *os.File
+	height   int64
+	prevHash interfaces.IHash
+}
+
+// FileBlock is one block of the mock chain
+type FileBlock struct {
+	Height    int64
+	PrevHash  string
+	Hash      string
+	TxID      string
+	DBHeight  uint32
+	KeyMR     string
+	Timestamp int64
+}
+
+var _ Backend = (*FileBackend)(nil)
+
+func init() {
+	RegisterBackend("file", NewFileBackend)
+}
+
+// NewFileBackend opens (or creates) the mock chain at cfg.Path
+func NewFileBackend(cfg BackendConfig) (Backend, error) {
+	if len(cfg.Path) == 0 {
+		return nil, fmt.Errorf("the file anchor backend needs a path")
+	}
+
+	fb := new(FileBackend)
+	fb.ledger = cfg.Ledger
+	fb.path = cfg.Path
+	fb.height = -1
+	fb.prevHash = primitives.NewZeroHash()
+
+	// Find the tip of an existing mock chain
+	if f, err := os.Open(cfg.Path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			block := new(FileBlock)
+			if err := json.Unmarshal(scanner.Bytes(), block); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("corrupt anchor file %s at block %d: %v", cfg.Path, fb.height+1, err)
+			}
+			fb.height = block.Height
+			fb.prevHash, err = primitives.HexToHash(block.Hash)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(cfg.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fb.file = f
+	return fb, nil
+}
+
+func (fb *FileBackend) Name() string {
+	return "file"
+}
+
+func (fb *FileBackend) Ledger() string {
+	return fb.ledger
+}
+
+// Anchor appends a block holding the KeyMR to the mock chain
+func (fb *FileBackend) Anchor(dbheight uint32, keyMR interfaces.IHash) (*AnchorRecord, error) {
+	fb.mutex.Lock()
+	defer fb.mutex.Unlock()
+
+	block := new(FileBlock)
+	block.Height = fb.height + 1
+	block.PrevHash = fb.prevHash.String()
+	block.DBHeight = dbheight
+	block.KeyMR = keyMR.String()
+	block.Timestamp = primitives.NewTimestampNow().GetTimeSeconds()
+	block.TxID = primitives.Sha(append(keyMR.Bytes(), []byte(fmt.Sprintf("%d", dbheight))...)).String()
+	hash := primitives.Sha(append(fb.prevHash.Bytes(), []byte(block.TxID)...))
+	block.Hash = hash.String()
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fb.file.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+	if err := fb.file.Sync(); err != nil {
+		return nil, err
+	}
+	fb.height = block.Height
+	fb.prevHash = hash
+
+	ar := NewAnchorRecordV2(dbheight, keyMR)
+	switch fb.ledger {
+	case LedgerEthereum:
+		ar.Ethereum = new(EthereumStruct)
+		ar.Ethereum.ContractAddress = fb.path
+		ar.Ethereum.TxID = block.TxID
+		ar.Ethereum.BlockHeight = block.Height
+		ar.Ethereum.BlockHash = block.Hash
+	default:
+		ar.Bitcoin = new(BitcoinStruct)
+		ar.Bitcoin.Address = fb.path
+		ar.Bitcoin.TXID = block.TxID
+		ar.Bitcoin.BlockHeight = int32(block.Height)
+		ar.Bitcoin.BlockHash = block.Hash
+	}
+	return ar, nil
+}
+
+func (fb *FileBackend) Close() error {
+	fb.mutex.Lock()
+	defer fb.mutex.Unlock()
+	return fb.file.Close()
+}
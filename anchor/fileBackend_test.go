@@ -0,0 +1,74 @@
+package anchor_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestAnchorChainIDs(t *testing.T) {
+	if AnchorChainID(LedgerBitcoin).String() != "df3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604" {
+		t.Errorf("Wrong Bitcoin anchor chain %s", AnchorChainID(LedgerBitcoin).String())
+	}
+	if AnchorChainID(LedgerEthereum).String() != "6e4540d08d5ac6a1a394e982fb6a2ab8b516ee751c37420055141b94fe070bfe" {
+		t.Errorf("Wrong Ethereum anchor chain %s", AnchorChainID(LedgerEthereum).String())
+	}
+}
+
+func TestFileBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mockchain.json")
+
+	if _, err := NewBackend("nosuchbackend", BackendConfig{Ledger: LedgerBitcoin, Path: path}); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+	if _, err := NewBackend("file", BackendConfig{Ledger: "dogecoin", Path: path}); err == nil {
+		t.Error("Expected an error for an unknown ledger")
+	}
+
+	priv := primitives.RandomPrivateKey()
+	var lastHash string
+	for i := uint32(0); i < 5; i++ {
+		// Reopen the mock chain every time, to make sure we pick up where we left off
+		backend, err := NewBackend("file", BackendConfig{Ledger: LedgerBitcoin, Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyMR := primitives.RandomHash()
+		ar, err := backend.Anchor(i, keyMR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backend.Close()
+
+		if ar.Bitcoin == nil || ar.Bitcoin.BlockHeight != int32(i) {
+			t.Fatalf("Bad anchor record %s", ar.String())
+		}
+		if ar.Bitcoin.BlockHash == lastHash {
+			t.Error("Mock chain did not move forward")
+		}
+		lastHash = ar.Bitcoin.BlockHash
+
+		content, extIDs, err := ar.SignedEntryParts(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ar2, valid, err := UnmarshalAndValidateAnchorRecordV2(content, extIDs, []interfaces.Verifier{priv.Pub})
+		if err != nil || !valid {
+			t.Fatalf("Anchor record did not validate %v", err)
+		}
+		if !ar.IsSame(ar2) || ar2.KeyMR != keyMR.String() || ar2.DBHeight != i {
+			t.Errorf("Anchor records differ\n%s\n%s", ar.String(), ar2.String())
+		}
+	}
+}
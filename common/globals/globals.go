@@ -44,6 +44,7 @@ type FactomParams struct {
 	Prefix                   string
 	Rotate                   bool
 	TimeOffset               int
+	SimClock                 bool
 	KeepMismatch             bool
 	StartDelay               int64
 	Deadline                 int
@@ -67,6 +68,7 @@ type FactomParams struct {
 	ExposeProfiling          bool
 	UseLogstash              bool
 	LogstashURL              string
+	LogSinks                 string
 	Sync2                    int
 	DebugConsole             string
 	StdoutLog                string
@@ -50,6 +50,9 @@ type DBOverlaySimple interface {
 	FetchKeyValueStore(key []byte, dst BinaryMarshallable) (BinaryMarshallable, error)
 	SaveDatabaseEntryHeight(height uint32) error
 	FetchDatabaseEntryHeight() (uint32, error)
+	DeleteEntry(hash IHash) error
+	SaveDatabasePruneHeight(height uint32) error
+	FetchDatabasePruneHeight() (uint32, error)
 }
 
 // Db defines a generic interface that is used to request and insert data into db
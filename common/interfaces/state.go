@@ -39,7 +39,7 @@ type IState interface {
 	Clone(number int) IState
 	GetCfg() IFactomConfig
 	GetConfigPath() string
-	LoadConfig(filename string, networkFlag string)
+	LoadConfig(filename string, networkFlag string) error
 	Init()
 	String() string
 	GetIdentityChainID() IHash
@@ -86,6 +86,7 @@ type IState interface {
 	GetTlsInfo() (bool, string, string)
 	GetFactomdLocations() string
 	GetCorsDomains() []string
+	GetRpcMaxBatchSize() int
 
 	// Routine for handling the syncroniztion of the leader and follower processes
 	// and how they process messages.
@@ -315,6 +316,12 @@ type IState interface {
 	GetMissingEntryCount() uint32
 	GetEntryBlockDBHeightProcessing() uint32
 	GetEntryBlockDBHeightComplete() uint32
+	IsChainKept(chainID IHash) bool      // False if this node does not store the entries of the chain
+	GetPruneStatus() interface{}         // Progress of the background database compaction
+	GetAnchorStatus() interface{}        // Latest anchored heights per ledger
+	DryRunValidate(msg IMsg) interface{} // Checks a transaction or commit as if it were submitted, without keeping it
+	// Why a factoid transaction, commit or reveal was last dropped, code is "" if it wasn't
+	GetMsgRejection(hash IHash, msgType byte) (code string, reason string, when Timestamp)
 	GetCurrentBlockStartTime() int64
 	GetCurrentMinute() int
 	GetCurrentMinuteStartTime() int64
@@ -347,6 +354,8 @@ type IState interface {
 
 	LogMessage(logName string, comment string, msg IMsg)
 	LogPrintf(logName string, format string, more ...interface{})
+	// Moves a message on to the next stage of its trace, if this node's messages are traced
+	TraceMessage(msg IMsg, stage string)
 
 	GetHighestAck() uint32
 	SetHighestAck(uint32)
@@ -83,10 +83,11 @@ func (m *EomSigInternal) GetMsgHash() (rval interfaces.IHash) {
 }
 func Fault(e *elections.Elections, dbheight int, minute int, timeOutId int, currentTimeoutId *atomic.AtomicInt, sigtype bool, timeoutDuration time.Duration) {
 	//	e.LogPrintf("election", "Start Timeout %d", timeOutId)
+	clock := e.State.(*state.State).GetClock()
 	for !e.State.(*state.State).DBFinished || e.State.(*state.State).IgnoreMissing {
-		time.Sleep(timeoutDuration)
+		clock.Sleep(timeoutDuration)
 	}
-	time.Sleep(timeoutDuration)
+	clock.Sleep(timeoutDuration)
 
 	if currentTimeoutId.Load() == timeOutId {
 		//		e.LogPrintf("election", "Timeout %d", timeOutId)
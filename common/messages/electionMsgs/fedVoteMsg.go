@@ -41,7 +41,7 @@ type FedVoteMsg struct {
 
 func (m *FedVoteMsg) InitFields(elect interfaces.IElections) {
 	election := elect.(*elections.Elections)
-	m.TS = primitives.NewTimestampNow()
+	m.TS = election.State.GetTimestamp()
 	m.DBHeight = uint32(election.DBHeight)
 	m.Minute = byte(election.Minute)
 	// You need to init the type
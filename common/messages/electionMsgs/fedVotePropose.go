@@ -43,7 +43,7 @@ func NewFedProposalMsg(signer interfaces.IHash, vol FedVoteVolunteerMsg) *FedVot
 	p.SetFullBroadcast(true)
 	p.Volunteer = vol
 	p.Signer = signer
-	p.FedVoteMsg.TS = primitives.NewTimestampNow()
+	p.FedVoteMsg.TS = vol.TS // InitFields moves it on to the node clock
 	p.VMIndex = vol.VMIndex
 	p.SigType = vol.SigType
 
@@ -184,7 +184,7 @@ func (m *SyncMsg) FollowerExecute(is interfaces.IState) {
 	va.ServerName = m.ServerName
 
 	va.VMIndex = m.VMIndex
-	va.TS = primitives.NewTimestampNow()
+	va.TS = s.GetTimestamp()
 	va.Name = m.Name
 	va.Weight = m.Weight
 	va.DBHeight = m.DBHeight
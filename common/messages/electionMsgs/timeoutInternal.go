@@ -213,7 +213,7 @@ func (m *TimeoutInternal) ElectionProcess(is interfaces.IState, elect interfaces
 			Sync := new(SyncMsg)
 			Sync.SetLocal(true)
 			Sync.VMIndex = vm
-			Sync.TS = primitives.NewTimestampNow()
+			Sync.TS = is.GetTimestamp()
 			Sync.Name = e.Name
 
 			Sync.FedIdx = uint32(e.Electing)
@@ -0,0 +1,492 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package chainExporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Tables of the export, indexes of Tables
+const (
+	DirectoryBlocks = iota
+	EntryBlocks
+	Entries
+	EntryExtIDs
+	FactoidTransactions
+	FactoidIO
+	ECCommits
+	AdminEntries
+)
+
+// Table is a set of CSV files of the export, with the columns of its header.  The columns are described
+// in the README of Utilities/ChainExporter, which is to be kept up to date with them.
+type Table struct {
+	Name    string
+	Columns []string
+}
+
+var Tables = []Table{
+	DirectoryBlocks:     {"directory_blocks", []string{"height", "keymr", "full_hash", "prev_keymr", "prev_full_hash", "body_mr", "timestamp", "network_id", "admin_block", "ec_block", "factoid_block", "entry_block_count"}},
+	EntryBlocks:         {"entry_blocks", []string{"height", "keymr", "chain_id", "sequence", "prev_keymr", "prev_full_hash", "body_mr", "entry_count"}},
+	Entries:             {"entries", []string{"height", "entry_hash", "chain_id", "entry_block", "position", "minute", "ext_id_count", "content_size", "content"}},
+	EntryExtIDs:         {"entry_ext_ids", []string{"height", "entry_hash", "entry_block", "position", "ext_id"}},
+	FactoidTransactions: {"factoid_transactions", []string{"height", "tx_id", "factoid_block", "position", "timestamp", "input_count", "output_count", "ec_output_count", "total_inputs", "total_outputs", "total_ec_outputs"}},
+	FactoidIO:           {"factoid_io", []string{"height", "tx_id", "kind", "position", "address", "amount"}},
+	ECCommits:           {"ec_commits", []string{"height", "ec_block", "minute", "kind", "commit_hash", "entry_hash", "chain_id_hash", "ec_address", "credits", "timestamp"}},
+	AdminEntries:        {"admin_entries", []string{"height", "admin_block", "position", "type", "name", "data"}},
+}
+
+var adminEntryNames = map[byte]string{
+	constants.TYPE_MINUTE_NUM:                 "minute_number",
+	constants.TYPE_DB_SIGNATURE:               "db_signature",
+	constants.TYPE_REVEAL_MATRYOSHKA:          "reveal_matryoshka",
+	constants.TYPE_ADD_MATRYOSHKA:             "add_matryoshka",
+	constants.TYPE_ADD_SERVER_COUNT:           "add_server_count",
+	constants.TYPE_ADD_FED_SERVER:             "add_federated_server",
+	constants.TYPE_ADD_AUDIT_SERVER:           "add_audit_server",
+	constants.TYPE_REMOVE_FED_SERVER:          "remove_federated_server",
+	constants.TYPE_ADD_FED_SERVER_KEY:         "add_federated_server_key",
+	constants.TYPE_ADD_BTC_ANCHOR_KEY:         "add_btc_anchor_key",
+	constants.TYPE_SERVER_FAULT:               "server_fault",
+	constants.TYPE_COINBASE_DESCRIPTOR:        "coinbase_descriptor",
+	constants.TYPE_COINBASE_DESCRIPTOR_CANCEL: "coinbase_descriptor_cancel",
+	constants.TYPE_ADD_FACTOID_ADDRESS:        "add_factoid_address",
+	constants.TYPE_ADD_FACTOID_EFFICIENCY:     "add_factoid_efficiency",
+}
+
+// StateFile is kept in the export directory by ExportIncremental, recording the last height exported
+const StateFile = "export.json"
+
+// ExportState is the last directory block an incremental export wrote
+type ExportState struct {
+	Height uint32 `json:"height"`
+	KeyMR  string `json:"keymr"`
+}
+
+// Exporter writes the blocks of a database to CSV files, for loading into analytics databases.  Each export
+// writes a file per table, named by the heights it holds, to a directory per table:
+// <Dir>/<table>/<start>-<end>.csv.
+type Exporter struct {
+	DB  interfaces.DBOverlay
+	Dir string
+
+	files   []*os.File
+	writers []*csv.Writer
+}
+
+// Export writes the heights from start to end, or to the head if end is above it, returning the last
+// height written
+func (e *Exporter) Export(start uint32, end uint32) (uint32, error) {
+	head, err := e.DB.FetchDBlockHead()
+	if err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, fmt.Errorf("no directory block head")
+	}
+	if end > head.GetDatabaseHeight() {
+		end = head.GetDatabaseHeight()
+	}
+	if start > end {
+		return 0, fmt.Errorf("nothing to export from %d, the head is at %d", start, head.GetDatabaseHeight())
+	}
+
+	// Written to temporary files, renamed once whole so a failed export leaves no part of a file
+	part := fmt.Sprintf("%09d-%09d.csv", start, end)
+	err = e.create(part + ".tmp")
+	if err != nil {
+		return 0, err
+	}
+	for h := start; h <= end && h >= start; h++ {
+		err = e.exportHeight(h)
+		if err != nil {
+			e.close()
+			return 0, fmt.Errorf("exporting %d: %v", h, err)
+		}
+	}
+	err = e.close()
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range Tables {
+		name := filepath.Join(e.Dir, t.Name, part)
+		err = os.Rename(name+".tmp", name)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return end, nil
+}
+
+// ExportIncremental exports the heights above the ones the last incremental export of the directory
+// wrote, up to the head, returning the first and last heights written.  Nothing is written if there are
+// no new heights.  It fails if the last block exported is no longer in the database, as the database
+// was rolled back or resynced below it.
+func (e *Exporter) ExportIncremental() (uint32, uint32, error) {
+	state, err := e.ReadState()
+	if err != nil {
+		return 0, 0, err
+	}
+	start := uint32(0)
+	if state != nil {
+		keyMR, err := e.DB.FetchDBKeyMRByHeight(state.Height)
+		if err != nil {
+			return 0, 0, err
+		}
+		if keyMR == nil || keyMR.String() != state.KeyMR {
+			return 0, 0, fmt.Errorf("the block %s exported at %d is no longer in the database, remove the files above the height it changed at and set the height of %s below it",
+				state.KeyMR, state.Height, filepath.Join(e.Dir, StateFile))
+		}
+		start = state.Height + 1
+	}
+
+	head, err := e.DB.FetchDBlockHead()
+	if err != nil {
+		return 0, 0, err
+	}
+	if head == nil {
+		return 0, 0, fmt.Errorf("no directory block head")
+	}
+	if start > head.GetDatabaseHeight() {
+		return start, start - 1, nil
+	}
+
+	end, err := e.Export(start, head.GetDatabaseHeight())
+	if err != nil {
+		return 0, 0, err
+	}
+	keyMR, err := e.DB.FetchDBKeyMRByHeight(end)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, e.writeState(&ExportState{Height: end, KeyMR: keyMR.String()})
+}
+
+// ReadState reads the state of the incremental exports of the directory, nil if there were none
+func (e *Exporter) ReadState() (*ExportState, error) {
+	b, err := ioutil.ReadFile(filepath.Join(e.Dir, StateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(ExportState)
+	err = json.Unmarshal(b, state)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", StateFile, err)
+	}
+	return state, nil
+}
+
+func (e *Exporter) writeState(state *ExportState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(e.Dir, StateFile)
+	err = ioutil.WriteFile(name+".tmp", b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(name+".tmp", name)
+}
+
+// create opens a file of each table and writes its header
+func (e *Exporter) create(part string) error {
+	e.files = make([]*os.File, len(Tables))
+	e.writers = make([]*csv.Writer, len(Tables))
+	for i, t := range Tables {
+		dir := filepath.Join(e.Dir, t.Name)
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			e.close()
+			return err
+		}
+		e.files[i], err = os.Create(filepath.Join(dir, part))
+		if err != nil {
+			e.close()
+			return err
+		}
+		e.writers[i] = csv.NewWriter(e.files[i])
+		err = e.writers[i].Write(t.Columns)
+		if err != nil {
+			e.close()
+			return err
+		}
+	}
+	return nil
+}
+
+// close flushes and closes the files, returning the first error
+func (e *Exporter) close() error {
+	var first error
+	for i, f := range e.files {
+		if f == nil {
+			continue
+		}
+		e.writers[i].Flush()
+		if err := e.writers[i].Error(); err != nil && first == nil {
+			first = err
+		}
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	e.files, e.writers = nil, nil
+	return first
+}
+
+func (e *Exporter) write(table int, row ...string) error {
+	return e.writers[table].Write(row)
+}
+
+func hash(h interfaces.IHash) string {
+	if h == nil {
+		return ""
+	}
+	return h.String()
+}
+
+func number(n interface{}) string {
+	return fmt.Sprint(n)
+}
+
+// exportHeight writes the rows of the blocks of a height
+func (e *Exporter) exportHeight(h uint32) error {
+	dblock, err := e.DB.FetchDBlockByHeight(h)
+	if err != nil {
+		return err
+	}
+	if dblock == nil {
+		return fmt.Errorf("no directory block")
+	}
+
+	var aKeyMR, ecKeyMR, fKeyMR interfaces.IHash
+	for _, entry := range dblock.GetDBEntries() {
+		switch entry.GetChainID().String() {
+		case "000000000000000000000000000000000000000000000000000000000000000a":
+			aKeyMR = entry.GetKeyMR()
+		case "000000000000000000000000000000000000000000000000000000000000000c":
+			ecKeyMR = entry.GetKeyMR()
+		case "000000000000000000000000000000000000000000000000000000000000000f":
+			fKeyMR = entry.GetKeyMR()
+		}
+	}
+	header := dblock.GetHeader()
+	err = e.write(DirectoryBlocks, number(h), hash(dblock.GetKeyMR()), hash(dblock.GetFullHash()), hash(header.GetPrevKeyMR()),
+		hash(header.GetPrevFullHash()), hash(header.GetBodyMR()), number(header.GetTimestamp().GetTimeSeconds()),
+		number(header.GetNetworkID()), hash(aKeyMR), hash(ecKeyMR), hash(fKeyMR), number(len(dblock.GetEBlockDBEntries())))
+	if err != nil {
+		return err
+	}
+
+	if aKeyMR != nil {
+		err = e.exportABlock(h, aKeyMR)
+		if err != nil {
+			return err
+		}
+	}
+	if fKeyMR != nil {
+		err = e.exportFBlock(h, fKeyMR)
+		if err != nil {
+			return err
+		}
+	}
+	if ecKeyMR != nil {
+		err = e.exportECBlock(h, ecKeyMR)
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range dblock.GetEBlockDBEntries() {
+		err = e.exportEBlock(h, entry.GetKeyMR())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) exportABlock(h uint32, keyMR interfaces.IHash) error {
+	ablock, err := e.DB.FetchABlock(keyMR)
+	if err != nil {
+		return err
+	}
+	if ablock == nil {
+		return fmt.Errorf("no admin block %v", keyMR)
+	}
+	for i, entry := range ablock.GetABEntries() {
+		data, err := entry.JSONString()
+		if err != nil {
+			return err
+		}
+		err = e.write(AdminEntries, number(h), hash(keyMR), number(i), number(entry.Type()), adminEntryNames[entry.Type()], data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) exportFBlock(h uint32, keyMR interfaces.IHash) error {
+	fblock, err := e.DB.FetchFBlock(keyMR)
+	if err != nil {
+		return err
+	}
+	if fblock == nil {
+		return fmt.Errorf("no factoid block %v", keyMR)
+	}
+	for i, t := range fblock.GetTransactions() {
+		txID := hash(t.GetSigHash())
+		inputs, err := t.TotalInputs()
+		if err != nil {
+			return err
+		}
+		outputs, err := t.TotalOutputs()
+		if err != nil {
+			return err
+		}
+		ecs, err := t.TotalECs()
+		if err != nil {
+			return err
+		}
+		err = e.write(FactoidTransactions, number(h), txID, hash(keyMR), number(i), number(t.GetTimestamp().GetTimeMilli()),
+			number(len(t.GetInputs())), number(len(t.GetOutputs())), number(len(t.GetECOutputs())),
+			number(inputs), number(outputs), number(ecs))
+		if err != nil {
+			return err
+		}
+
+		for j, input := range t.GetInputs() {
+			err = e.write(FactoidIO, number(h), txID, "input", number(j), primitives.ConvertFctAddressToUserStr(input.GetAddress()), number(input.GetAmount()))
+			if err != nil {
+				return err
+			}
+		}
+		for j, output := range t.GetOutputs() {
+			err = e.write(FactoidIO, number(h), txID, "output", number(j), primitives.ConvertFctAddressToUserStr(output.GetAddress()), number(output.GetAmount()))
+			if err != nil {
+				return err
+			}
+		}
+		for j, output := range t.GetECOutputs() {
+			err = e.write(FactoidIO, number(h), txID, "ec_output", number(j), primitives.ConvertECAddressToUserStr(output.GetAddress()), number(output.GetAmount()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) exportECBlock(h uint32, keyMR interfaces.IHash) error {
+	ecblock, err := e.DB.FetchECBlock(keyMR)
+	if err != nil {
+		return err
+	}
+	if ecblock == nil {
+		// Mainnet never had the entry credit blocks of 70386 to 70410
+		return nil
+	}
+
+	// The minute number follows the commits of the minute
+	var pending [][]string
+	for _, entry := range ecblock.GetBody().GetEntries() {
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			c := entry.(*entryCreditBlock.CommitChain)
+			pending = append(pending, []string{number(h), hash(keyMR), "", "chain", hash(c.Hash()), hash(c.EntryHash), hash(c.ChainIDHash),
+				primitives.ConvertECAddressToUserStr(factoid.NewAddress(c.ECPubKey[:])), number(c.Credits), number(c.GetTimestamp().GetTimeMilli())})
+		case constants.ECIDEntryCommit:
+			c := entry.(*entryCreditBlock.CommitEntry)
+			pending = append(pending, []string{number(h), hash(keyMR), "", "entry", hash(c.Hash()), hash(c.EntryHash), "",
+				primitives.ConvertECAddressToUserStr(factoid.NewAddress(c.ECPubKey[:])), number(c.Credits), number(c.GetTimestamp().GetTimeMilli())})
+		case constants.ECIDMinuteNumber:
+			minute := strconv.Itoa(int(entry.(*entryCreditBlock.MinuteNumber).Number))
+			for _, row := range pending {
+				row[2] = minute
+				if err := e.write(ECCommits, row...); err != nil {
+					return err
+				}
+			}
+			pending = nil
+		}
+	}
+	for _, row := range pending {
+		if err := e.write(ECCommits, row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) exportEBlock(h uint32, keyMR interfaces.IHash) error {
+	eblock, err := e.DB.FetchEBlock(keyMR)
+	if err != nil {
+		return err
+	}
+	if eblock == nil {
+		return fmt.Errorf("no entry block %v", keyMR)
+	}
+	header := eblock.GetHeader()
+	chainID := hash(header.GetChainID())
+
+	// The end of minute marker follows the entries of the minute
+	var pending []interfaces.IEBEntry
+	position := 0
+	writeEntries := func(minute string) error {
+		for _, entry := range pending {
+			err := e.write(Entries, number(h), hash(entry.GetHash()), chainID, hash(keyMR), number(position), minute,
+				number(len(entry.ExternalIDs())), number(len(entry.GetContent())), fmt.Sprintf("%x", entry.GetContent()))
+			if err != nil {
+				return err
+			}
+			for i, extID := range entry.ExternalIDs() {
+				err = e.write(EntryExtIDs, number(h), hash(entry.GetHash()), hash(keyMR), number(i), fmt.Sprintf("%x", extID))
+				if err != nil {
+					return err
+				}
+			}
+			position++
+		}
+		pending = nil
+		return nil
+	}
+	for _, entryHash := range eblock.GetEntryHashes() {
+		if entryHash.IsMinuteMarker() {
+			if err := writeEntries(number(entryHash.ToMinute())); err != nil {
+				return err
+			}
+			continue
+		}
+		entry, err := e.DB.FetchEntry(entryHash)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return fmt.Errorf("no entry %v of entry block %v", entryHash, keyMR)
+		}
+		pending = append(pending, entry)
+	}
+	if err := writeEntries(""); err != nil {
+		return err
+	}
+
+	return e.write(EntryBlocks, number(h), hash(keyMR), chainID, number(header.GetEBSequence()), hash(header.GetPrevKeyMR()),
+		hash(header.GetPrevFullHash()), hash(header.GetBodyMR()), number(position))
+}
@@ -0,0 +1,131 @@
+package chainExporter_test
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/chainExporter"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// readTable reads the rows of all the files of a table, checking each starts with the header
+func readTable(t *testing.T, dir string, table Table) [][]string {
+	files, err := filepath.Glob(filepath.Join(dir, table.Name, "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows [][]string
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(f).ReadAll()
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(table.Columns, ",") {
+			t.Fatalf("%s has no header", name)
+		}
+		rows = append(rows, records[1:]...)
+	}
+	return rows
+}
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	e := &Exporter{DB: dbo, Dir: dir}
+	end, err := e.Export(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != uint32(testHelper.BlockCount-1) {
+		t.Errorf("Exported to %d, expected the head at %d", end, testHelper.BlockCount-1)
+	}
+
+	dblocks := readTable(t, dir, Tables[DirectoryBlocks])
+	if len(dblocks) != testHelper.BlockCount {
+		t.Errorf("Exported %d directory blocks", len(dblocks))
+	}
+	for _, table := range []int{EntryBlocks, Entries, FactoidTransactions, FactoidIO, ECCommits, AdminEntries} {
+		rows := readTable(t, dir, Tables[table])
+		if len(rows) == 0 {
+			t.Errorf("Exported no %s", Tables[table].Name)
+		}
+		for _, row := range rows {
+			if len(row) != len(Tables[table].Columns) {
+				t.Fatalf("%s row %v doesn't match the header", Tables[table].Name, row)
+			}
+		}
+	}
+
+	ranged := &Exporter{DB: dbo, Dir: filepath.Join(dir, "range")}
+	if end, err = ranged.Export(2, 3); err != nil || end != 3 {
+		t.Fatalf("Exported to %d, %v", end, err)
+	}
+	dblocks = readTable(t, ranged.Dir, Tables[DirectoryBlocks])
+	if len(dblocks) != 2 || dblocks[0][0] != "2" || dblocks[1][0] != "3" {
+		t.Errorf("Exported %v", dblocks)
+	}
+
+	if _, err := e.Export(uint32(testHelper.BlockCount), 1000); err == nil {
+		t.Error("Exported above the head")
+	}
+	if state, err := e.ReadState(); err != nil || state != nil {
+		t.Errorf("Exporting a range changed the incremental state to %v, %v", state, err)
+	}
+}
+
+func TestExportIncremental(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The first export is of a database that is behind
+	height := uint32(testHelper.BlockCount / 2)
+	behind := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	if _, err := behind.RollBackTo(height); err != nil {
+		t.Fatal(err)
+	}
+	start, end, err := (&Exporter{DB: behind, Dir: dir}).ExportIncremental()
+	if err != nil || start != 0 || end != height {
+		t.Fatalf("Exported %d to %d, %v", start, end, err)
+	}
+
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	e := &Exporter{DB: dbo, Dir: dir}
+	start, end, err = e.ExportIncremental()
+	if err != nil || start != height+1 || end != uint32(testHelper.BlockCount-1) {
+		t.Fatalf("Exported %d to %d, %v", start, end, err)
+	}
+	if dblocks := readTable(t, dir, Tables[DirectoryBlocks]); len(dblocks) != testHelper.BlockCount {
+		t.Errorf("Exported %d directory blocks, expected %d", len(dblocks), testHelper.BlockCount)
+	}
+
+	// Nothing new
+	start, end, err = e.ExportIncremental()
+	if err != nil || end >= start {
+		t.Errorf("Exported %d to %d again, %v", start, end, err)
+	}
+
+	// The blocks exported were rolled back
+	if _, err := dbo.RollBackTo(height); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = e.ExportIncremental(); err == nil {
+		t.Error("Exported from blocks that were rolled back")
+	}
+}
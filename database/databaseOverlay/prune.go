@@ -0,0 +1,49 @@
+package databaseOverlay
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// DeleteEntry removes an entry and its index from the database.  The INCLUDED_IN record is left alone so
+// we can still tell which entry block (and so which chain) held the entry.
+func (db *Overlay) DeleteEntry(hash interfaces.IHash) error {
+	chainID, err := db.FetchPrimaryIndexBySecondaryIndex(ENTRY, hash)
+	if err != nil {
+		return err
+	}
+	if chainID == nil {
+		return nil
+	}
+	err = db.Delete(chainID.Bytes(), hash.Bytes())
+	if err != nil {
+		return err
+	}
+	return db.Delete(ENTRY, hash.Bytes())
+}
+
+var DatabasePruneHeightKey = []byte("DatabasePruneHeight")
+
+// SaveDatabasePruneHeight records the highest directory block the pruner has finished with
+func (db *Overlay) SaveDatabasePruneHeight(height uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(height)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+
+	return db.SaveKeyValueStore(bs, DatabasePruneHeightKey)
+}
+
+func (db *Overlay) FetchDatabasePruneHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	_, err := db.FetchKeyValueStore(DatabasePruneHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	height, err := buf.PopUInt32()
+	if err != nil {
+		return 0, err
+	}
+	return height, nil
+}
@@ -0,0 +1,68 @@
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives/random"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestDeleteEntry(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	kept := testHelper.CreateTestEntry(1)
+	pruned := testHelper.CreateTestEntry(2)
+	if err := dbo.InsertEntry(kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbo.InsertEntry(pruned); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dbo.DeleteEntry(pruned.GetHash()); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := dbo.FetchEntry(pruned.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if e != nil {
+		t.Error("Deleted entry still in the database")
+	}
+	e, err = dbo.FetchEntry(kept.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if e == nil {
+		t.Error("Deleted the wrong entry")
+	}
+
+	// Deleting something we don't have is not an error
+	if err := dbo.DeleteEntry(pruned.GetHash()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSaveLoadDatabasePruneHeight(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	for i := 0; i < 10; i++ {
+		height := random.RandUInt32()
+		err := dbo.SaveDatabasePruneHeight(height)
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		height2, err := dbo.FetchDatabasePruneHeight()
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		if height != height2 {
+			t.Errorf("%v != %v", height, height2)
+		}
+	}
+}
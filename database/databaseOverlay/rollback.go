@@ -0,0 +1,283 @@
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// RollBackTo removes every block above a directory block height, with the entries first included above it,
+// their INCLUDED_IN and PAID_FOR records and the dirblock info of the removed directory blocks, and moves the
+// chain heads back to the blocks at or below it.  Heights are removed from the top down, the chain heads
+// first and the directory block height record last, so an interrupted rollback picks up where it stopped
+// when run again.  Returns the number of directory blocks removed.
+//
+// Dirblock info of the blocks that are kept, confirmed by anchors that were removed, is left alone;
+// ReparseAnchorChains rebuilds it from the anchors that remain.
+func (db *Overlay) RollBackTo(height uint32) (int, error) {
+	keep, err := db.FetchDBlockByHeight(height)
+	if err != nil {
+		return 0, err
+	}
+	if keep == nil {
+		return 0, fmt.Errorf("no directory block at %d to roll back to", height)
+	}
+
+	// The height records are removed last, so they run unbroken from the one kept to the top
+	top := height
+	for {
+		keyMR, err := db.FetchDBKeyMRByHeight(top + 1)
+		if err != nil {
+			return 0, err
+		}
+		if keyMR == nil {
+			break
+		}
+		top++
+	}
+
+	removed := 0
+	for h := top; h > height; h-- {
+		prev, err := db.FetchDBlockByHeight(h - 1)
+		if err != nil {
+			return removed, err
+		}
+		if prev == nil {
+			return removed, fmt.Errorf("no directory block at %d", h-1)
+		}
+		err = db.rollBackHeight(h, prev)
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// rollBackHeight removes the blocks of one height, moving the chain heads to prev and the entry blocks
+// before the removed ones
+func (db *Overlay) rollBackHeight(height uint32, prev interfaces.IDirectoryBlock) error {
+	set, err := db.FetchBlockSetByHeight(height)
+	if err != nil {
+		return err
+	}
+
+	heads := []interfaces.IHash{prev.GetKeyMR()}
+	chains := []interfaces.IHash{prev.GetChainID()}
+	for _, e := range prev.GetDBEntries() {
+		switch e.GetChainID().String() {
+		case "000000000000000000000000000000000000000000000000000000000000000a",
+			"000000000000000000000000000000000000000000000000000000000000000c",
+			"000000000000000000000000000000000000000000000000000000000000000f":
+		default:
+			continue
+		}
+		heads = append(heads, e.GetKeyMR())
+		chains = append(chains, e.GetChainID())
+	}
+	if set != nil {
+		for _, eblock := range set.EBlocks {
+			if eblock == nil {
+				continue
+			}
+			prevKeyMR := eblock.GetHeader().GetPrevKeyMR()
+			if prevKeyMR.IsZero() {
+				// The chain started above the height kept
+				err = db.Delete(CHAIN_HEAD, eblock.GetChainID().Bytes())
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			heads = append(heads, prevKeyMR)
+			chains = append(chains, eblock.GetChainID())
+		}
+	}
+	err = db.SetChainHeads(heads, chains)
+	if err != nil {
+		return err
+	}
+
+	heightKey := make([]byte, 4)
+	binary.BigEndian.PutUint32(heightKey, height)
+	if set == nil {
+		// Only the height record was left by an interrupted rollback
+		return db.Delete(DIRECTORYBLOCK_NUMBER, heightKey)
+	}
+
+	for _, eblock := range set.EBlocks {
+		if eblock == nil {
+			continue
+		}
+		keyMR := eblock.DatabasePrimaryIndex()
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			// Only the first entry block to hold an entry is recorded, so the entry is new above the
+			// height kept if it is this one
+			included, err := db.FetchIncludedIn(hash)
+			if err != nil {
+				return err
+			}
+			if included == nil || !included.IsSameAs(keyMR) {
+				continue
+			}
+			err = db.DeleteEntry(hash)
+			if err != nil {
+				return err
+			}
+			err = db.Delete(INCLUDED_IN, hash.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		err = db.deleteBlock(ENTRYBLOCK, append(ENTRYBLOCK_CHAIN_NUMBER, eblock.GetChainID().Bytes()...), ENTRYBLOCK_SECONDARYINDEX, eblock)
+		if err != nil {
+			return err
+		}
+	}
+
+	if set.ECBlock != nil {
+		err = db.deletePaidFor(set.ECBlock)
+		if err != nil {
+			return err
+		}
+		err = db.deleteIncludedIn(set.ECBlock)
+		if err != nil {
+			return err
+		}
+		err = db.deleteBlock(ENTRYCREDITBLOCK, ENTRYCREDITBLOCK_NUMBER, ENTRYCREDITBLOCK_SECONDARYINDEX, set.ECBlock)
+		if err != nil {
+			return err
+		}
+	}
+	if set.FBlock != nil {
+		err = db.deleteIncludedIn(set.FBlock)
+		if err != nil {
+			return err
+		}
+		err = db.deleteBlock(FACTOIDBLOCK, FACTOIDBLOCK_NUMBER, FACTOIDBLOCK_SECONDARYINDEX, set.FBlock)
+		if err != nil {
+			return err
+		}
+	}
+	if set.ABlock != nil {
+		err = db.deleteBlock(ADMINBLOCK, ADMINBLOCK_NUMBER, ADMINBLOCK_SECONDARYINDEX, set.ABlock)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyMR := set.DBlock.GetKeyMR()
+	dbi, err := db.FetchDirBlockInfoByKeyMR(keyMR)
+	if err != nil {
+		return err
+	}
+	if dbi != nil {
+		err = db.Delete(DIRBLOCKINFO_SECONDARYINDEX, dbi.DatabaseSecondaryIndex().Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	for _, bucket := range [][]byte{DIRBLOCKINFO, DIRBLOCKINFO_UNCONFIRMED} {
+		err = db.Delete(bucket, keyMR.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	err = db.Delete(DIRBLOCKINFO_NUMBER, heightKey)
+	if err != nil {
+		return err
+	}
+
+	err = db.deleteIncludedIn(set.DBlock)
+	if err != nil {
+		return err
+	}
+	err = db.Delete(DIRECTORYBLOCK, keyMR.Bytes())
+	if err != nil {
+		return err
+	}
+	err = db.Delete(DIRECTORYBLOCK_SECONDARYINDEX, set.DBlock.DatabaseSecondaryIndex().Bytes())
+	if err != nil {
+		return err
+	}
+	return db.Delete(DIRECTORYBLOCK_NUMBER, heightKey)
+}
+
+// deleteBlock removes a block and its height and secondary index records, the records
+// ProcessBlockMultiBatch saves besides the chain head
+func (db *Overlay) deleteBlock(blockBucket, numberBucket, secondaryIndexBucket []byte, block interfaces.DatabaseBatchable) error {
+	heightKey := make([]byte, 4)
+	binary.BigEndian.PutUint32(heightKey, block.GetDatabaseHeight())
+	err := db.Delete(numberBucket, heightKey)
+	if err != nil {
+		return err
+	}
+	err = db.Delete(secondaryIndexBucket, block.DatabaseSecondaryIndex().Bytes())
+	if err != nil {
+		return err
+	}
+	return db.Delete(blockBucket, block.DatabasePrimaryIndex().Bytes())
+}
+
+// deleteIncludedIn removes the INCLUDED_IN records that point at a block
+func (db *Overlay) deleteIncludedIn(block interfaces.DatabaseBlockWithEntries) error {
+	hashes := append(block.GetEntryHashes(), block.GetEntrySigHashes()...)
+	keyMR := block.DatabasePrimaryIndex()
+	for _, hash := range hashes {
+		if hash == nil || hash.IsMinuteMarker() {
+			continue
+		}
+		included, err := db.FetchIncludedIn(hash)
+		if err != nil {
+			return err
+		}
+		if included == nil || !included.IsSameAs(keyMR) {
+			continue
+		}
+		err = db.Delete(INCLUDED_IN, hash.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePaidFor removes the PAID_FOR records of the commits of an entry credit block
+func (db *Overlay) deletePaidFor(block interfaces.IEntryCreditBlock) error {
+	for _, entry := range block.GetBody().GetEntries() {
+		var entryHash interfaces.IHash
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			entryHash = entry.(*entryCreditBlock.CommitChain).EntryHash
+		case constants.ECIDEntryCommit:
+			entryHash = entry.(*entryCreditBlock.CommitEntry).EntryHash
+		default:
+			continue
+		}
+
+		// Depending on how it was saved, the record holds the hash or the signature hash of the commit
+		paid, err := db.Get(PAID_FOR, entryHash.Bytes(), primitives.NewZeroHash())
+		if err != nil {
+			return err
+		}
+		if paid == nil {
+			continue
+		}
+		commit := paid.(interfaces.IHash)
+		if !commit.IsSameAs(entry.Hash()) && !commit.IsSameAs(entry.GetSigHash()) {
+			continue
+		}
+		err = db.Delete(PAID_FOR, entryHash.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package databaseOverlay_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func populate(t *testing.T, dbo *Overlay, sets []*testHelper.BlockSet) {
+	for _, set := range sets {
+		dbo.StartMultiBatch()
+		if err := dbo.ProcessABlockMultiBatch(set.ABlock); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbo.ProcessEBlockMultiBatch(set.EBlock, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbo.ProcessEBlockMultiBatch(set.AnchorEBlock, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbo.ProcessECBlockMultiBatch(set.ECBlock, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbo.ProcessFBlockMultiBatch(set.FBlock); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbo.ProcessDBlockMultiBatch(set.DBlock); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range set.Entries {
+			if err := dbo.InsertEntryMultiBatch(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := dbo.ExecuteMultiBatch(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// compareMapDBs reports the records one database holds and the other does not, or holds differently
+func compareMapDBs(t *testing.T, got, want *mapdb.MapDB) {
+	for bucket, records := range want.Cache {
+		for key, value := range records {
+			if v, ok := got.Cache[bucket][key]; !ok || !bytes.Equal(v, value) {
+				t.Errorf("Record %x of bucket %x is missing or different", key, bucket)
+			}
+		}
+	}
+	for bucket, records := range got.Cache {
+		for key := range records {
+			if _, ok := want.Cache[bucket][key]; !ok {
+				t.Errorf("Record %x of bucket %x was left behind", key, bucket)
+			}
+		}
+	}
+}
+
+func TestRollBackTo(t *testing.T) {
+	sets := testHelper.CreateFullTestBlockSet()
+	height := len(sets) / 2
+
+	rolled := new(mapdb.MapDB)
+	dbo := NewOverlay(rolled)
+	populate(t, dbo, sets)
+	kept := new(mapdb.MapDB)
+	want := NewOverlay(kept)
+	populate(t, want, sets[:height+1])
+
+	removed, err := dbo.RollBackTo(uint32(height))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != len(sets)-height-1 {
+		t.Errorf("Removed %d heights, expected %d", removed, len(sets)-height-1)
+	}
+
+	head, err := dbo.FetchDBlockHead()
+	if err != nil || head == nil {
+		t.Fatalf("No head after the rollback %v", err)
+	}
+	if head.GetDatabaseHeight() != uint32(height) {
+		t.Errorf("Head at %d, expected %d", head.GetDatabaseHeight(), height)
+	}
+
+	// The anchors of the kept blocks that were removed are rebuilt from the ones left
+	if err := dbo.ReparseAnchorChains(); err != nil {
+		t.Fatal(err)
+	}
+	if err := want.ReparseAnchorChains(); err != nil {
+		t.Fatal(err)
+	}
+	compareMapDBs(t, rolled, kept)
+
+	// Nothing is left to remove
+	removed, err = dbo.RollBackTo(uint32(height))
+	if err != nil || removed != 0 {
+		t.Errorf("Rolled back %d heights again, %v", removed, err)
+	}
+	if _, err := dbo.RollBackTo(uint32(len(sets))); err == nil {
+		t.Error("Rolled back to a height the database does not have")
+	}
+}
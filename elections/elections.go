@@ -485,9 +485,19 @@ func Run(s *state.State) {
 	e.RoundTimeout = time.Duration(RoundTimeout) * time.Second
 	e.Waiting = make(chan interfaces.IElectionMsg, 500)
 
+	clock := s.GetClock()
+	clock.Join() // A simulated clock waits for the elections to run
+	defer clock.Leave()
+
 	// Actually run the elections
 	for {
-		msg := e.Input.BlockingDequeue().(interfaces.IElectionMsg)
+		next := e.Input.Dequeue()
+		if next == nil {
+			clock.Idle()
+			next = e.Input.BlockingDequeue()
+			clock.Busy()
+		}
+		msg := next.(interfaces.IElectionMsg)
 		e.LogMessage("election", fmt.Sprintf("exec %d", e.Electing), msg.(interfaces.IMsg))
 
 		valid := msg.ElectionValidate(e)
@@ -9,12 +9,12 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"io/ioutil"
 	"math"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/constants/runstate"
 	. "github.com/FactomProject/factomd/common/globals"
@@ -27,9 +27,13 @@ import (
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/elections"
+	"github.com/FactomProject/factomd/grpcapi"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/tracing"
 	"github.com/FactomProject/factomd/util"
+	"github.com/FactomProject/factomd/util/clock"
 	"github.com/FactomProject/factomd/wsapi"
 	log "github.com/sirupsen/logrus"
 )
@@ -75,30 +79,31 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		FactomConfigFilename = p.ConfigPath
 	}
 	fmt.Println(fmt.Sprintf("factom config: %s", FactomConfigFilename))
-	s.LoadConfig(FactomConfigFilename, p.NetworkName)
+	if err := s.LoadConfig(FactomConfigFilename, p.NetworkName); err != nil {
+		panic(fmt.Sprintf("Bad config %s: %v", FactomConfigFilename, err))
+	}
+	if err := activations.LoadSchedule(s.ActivationHeights, s.ActivationFile); err != nil {
+		panic(fmt.Sprintf("Bad activation schedule: %v", err))
+	}
 	s.OneLeader = p.Rotate
 	s.TimeOffset = primitives.NewTimestampFromMilliseconds(uint64(p.TimeOffset))
+	if p.SimClock {
+		// All the nodes of the simulation are clones of this one, and share its clock
+		s.Clock = clock.NewSimulated(time.Now())
+	}
 	s.StartDelayLimit = p.StartDelay * 1000
 	s.Journaling = p.Journaling
 	s.FactomdVersion = FactomdVersion
 	s.EFactory = new(electionMsgs.ElectionsFactory)
 
-	log.SetOutput(os.Stdout)
-	switch strings.ToLower(p.Loglvl) {
-	case "none":
-		log.SetOutput(ioutil.Discard)
-	case "debug":
-		log.SetLevel(log.DebugLevel)
-	case "info":
-		log.SetLevel(log.InfoLevel)
-	case "warning", "warn":
-		log.SetLevel(log.WarnLevel)
-	case "error":
-		log.SetLevel(log.ErrorLevel)
-	case "fatal":
-		log.SetLevel(log.FatalLevel)
-	case "panic":
-		log.SetLevel(log.PanicLevel)
+	// The level and sinks can be changed later through the set-logging debug API
+	err := llog.Configure(llog.Settings{
+		Level:       strings.ToLower(p.Loglvl),
+		Sinks:       llog.ParseSinks(p.LogSinks),
+		LogstashURL: p.LogstashURL,
+	})
+	if err != nil {
+		log.Fatalf("Bad logging flags: %v", err)
 	}
 
 	// Command line override if provided
@@ -299,6 +304,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "runtimeLog", p.RuntimeLog))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "rotate", p.Rotate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "timeOffset", p.TimeOffset))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "simClock", p.SimClock))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "keepMismatch", p.KeepMismatch))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "startDelay", p.StartDelay))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "Network", s.Network))
@@ -332,6 +338,10 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 
 	addFnodeName(0) // bootstrap id doesn't change
 
+	if len(fnodes) == 1 {
+		llog.SetNode(fnodes[0].State.LogFields)
+	}
+
 	// Modify Identities of new nodes
 	if len(fnodes) > 1 && len(s.Prefix) == 0 {
 		modifyLoadIdentities() // We clone s to make all of our servers
@@ -407,6 +417,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			ConfigPeers:              configPeers,
 			CmdLinePeers:             p.Peers,
 			ConnectionMetricsChannel: connectionMetricsChannel,
+			Clock:                    s.GetClock(),
 		}
 		p2pNetwork = new(p2p.Controller).Init(ci)
 		fnodes[0].State.NetworkController = p2pNetwork
@@ -585,9 +596,24 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			panic("Encountered an error while trying to re-parse anchor chains: " + err.Error())
 		}
 	}
+	if err := fnodes[0].State.StartAnchoring(); err != nil {
+		panic("Encountered an error while trying to start anchoring: " + err.Error())
+	}
 
 	// Start the webserver
 	wsapi.Start(fnodes[0].State)
+	grpcapi.Start(fnodes[0].State, fnodes[0].State.GrpcPort)
+	if fnodes[0].State.TraceEndpoint != "" {
+		if r, err := tracing.NewOTLPRecorder(fnodes[0].State.TraceEndpoint, fnodes[0].State.FactomNodeName); err != nil {
+			fmt.Println("Message tracing is off:", err)
+		} else {
+			tracing.Start(r)
+			fnodes[0].State.TraceMessages = true
+		}
+	} else if fnodes[0].State.TraceLog {
+		tracing.Start(&tracing.LogRecorder{Node: fnodes[0].State.FactomNodeName})
+		fnodes[0].State.TraceMessages = true
+	}
 	if fnodes[0].State.DebugExec() && messages.CheckFileName("graphData.txt") {
 		go printGraphData("graphData.txt", 30)
 	}
@@ -659,6 +685,12 @@ func startServer(i int, fnode *FactomNode, load bool) {
 		go state.LoadDatabase(fnode.State)
 	}
 	go fnode.State.GoSyncEntries()
+	go fnode.State.GoPrune()
+	go fnode.State.GoAnchorMonitor()
+	if i == 0 {
+		// The consensus gauges are global, so they follow the first node of a simulation
+		go fnode.State.GoConsensusHealth()
+	}
 	go Timer(fnode.State)
 	go elections.Run(fnode.State)
 	go fnode.State.ValidatorLoop()
@@ -15,6 +15,8 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/tracing"
 )
 
 var _ = fmt.Print
@@ -27,6 +29,8 @@ func NetworkProcessorNet(fnode *FactomNode) {
 }
 
 func Peers(fnode *FactomNode) {
+	fnode.State.GetClock().Join() // A simulated clock waits for the node to run out of messages
+	defer fnode.State.GetClock().Leave()
 
 	// ackHeight is used in ignoreMsg to determine if we should ignore an acknowledgment
 	ackHeight := uint32(0)
@@ -129,6 +133,7 @@ func Peers(fnode *FactomNode) {
 			_, BRValid := fnode.State.FReplay.Valid(constants.BLOCK_REPLAY, repeatHashFixed, timestamp, now)
 			if !BRValid {
 				fnode.State.LogMessage("NetworkInputs", "API Drop, BLOCK_REPLAY", msg)
+				fnode.State.RecordRejection(msg, "BLOCK_REPLAY")
 				RepeatMsgs.Inc()
 				continue
 			}
@@ -137,6 +142,7 @@ func Peers(fnode *FactomNode) {
 			NRValid := fnode.State.Replay.IsTSValidAndUpdateState(constants.NETWORK_REPLAY, repeatHashFixed, timestamp, now)
 			if !NRValid {
 				fnode.State.LogMessage("NetworkInputs", "API Drop, NETWORK_REPLAY", msg)
+				fnode.State.RecordRejection(msg, "NETWORK_REPLAY")
 				RepeatMsgs.Inc()
 				continue
 			}
@@ -298,7 +304,7 @@ func Peers(fnode *FactomNode) {
 			} // For a peer read up to 100 messages {...}
 		} // for each peer {...}
 		if cnt == 0 {
-			time.Sleep(50 * time.Millisecond) // handled no message, sleep a bit
+			fnode.State.GetClock().Sleep(50 * time.Millisecond) // handled no message, sleep a bit
 		}
 	} // forever {...}
 }
@@ -308,6 +314,7 @@ func sendToExecute(msg interfaces.IMsg, fnode *FactomNode, source string) {
 	switch t {
 	case constants.MISSING_MSG:
 		fnode.State.LogMessage("mmr_response", fmt.Sprintf("%s, enqueue %d", source, len(fnode.State.MissingMessageResponseHandler.MissingMsgRequests)), msg)
+		fnode.State.JournalMessageTo(msg, state.JournalMissingMsg)
 		fnode.State.MissingMessageResponseHandler.NotifyPeerMissingMsg(msg)
 
 	case constants.COMMIT_CHAIN_MSG:
@@ -349,28 +356,42 @@ func sendToExecute(msg interfaces.IMsg, fnode *FactomNode, source string) {
 func Q1(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue", msg)
 	fnode.State.LogMessage("InMsgQueue", source+", enqueue", msg)
+	fnode.State.TraceMessage(msg, tracing.StageInMsgQueue)
+	fnode.State.JournalMessageTo(msg, state.JournalInMsgQueue)
 	fnode.State.InMsgQueue().Enqueue(msg)
 }
 
 func Q2(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue2", msg)
 	fnode.State.LogMessage("InMsgQueue2", source+", enqueue2", msg)
+	fnode.State.TraceMessage(msg, tracing.StageInMsgQueue)
+	fnode.State.JournalMessageTo(msg, state.JournalInMsgQueue2)
 	fnode.State.InMsgQueue2().Enqueue(msg)
 }
 
 func DataQ(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	q := fnode.State.DataMsgQueue()
 	fnode.State.LogMessage("DataQueue", fmt.Sprintf(source+", enqueue %v", len(q)), msg)
+	fnode.State.JournalMessageTo(msg, state.JournalDataQueue)
 	q <- msg
 }
 
 func NetworkOutputs(fnode *FactomNode) {
+	clock := fnode.State.GetClock()
+	clock.Join() // A simulated clock waits for the node to send out its messages
+	defer clock.Leave()
+
 	for {
 		// if len(fnode.State.NetworkOutMsgQueue()) > 500 {
 		// 	fmt.Print(fnode.State.GetFactomNodeName(), "-", len(fnode.State.NetworkOutMsgQueue()), " ")
 		// }
 		//msg := <-fnode.State.NetworkOutMsgQueue()
-		msg := fnode.State.NetworkOutMsgQueue().BlockingDequeue()
+		msg := fnode.State.NetworkOutMsgQueue().Dequeue()
+		if msg == nil {
+			clock.Idle()
+			msg = fnode.State.NetworkOutMsgQueue().BlockingDequeue()
+			clock.Busy()
+		}
 
 		NetworkOutTotalDequeue.Inc()
 		fnode.State.LogMessage("NetworkOutputs", "Dequeue", msg)
@@ -496,9 +517,15 @@ func NetworkOutputs(fnode *FactomNode) {
 
 // Just throw away the trash
 func InvalidOutputs(fnode *FactomNode) {
+	clock := fnode.State.GetClock()
+	clock.Join()
+	defer clock.Leave()
+
 	for {
-		time.Sleep(1 * time.Millisecond)
+		clock.Sleep(1 * time.Millisecond)
+		clock.Idle()
 		_ = <-fnode.State.NetworkInvalidMsgQueue()
+		clock.Busy()
 		//fmt.Println(invalidMsg)
 
 		// The following code was giving a demerit for each instance of a message in the NetworkInvalidMsgQueue.
@@ -512,10 +539,16 @@ func InvalidOutputs(fnode *FactomNode) {
 
 // Handle requests for missing data
 func MissingData(fnode *FactomNode) {
+	clock := fnode.State.GetClock()
+	clock.Join()
+	defer clock.Leave()
+
 	q := fnode.State.DataMsgQueue()
 	for {
+		clock.Idle()
 		select {
 		case msg := <-q:
+			clock.Busy()
 			fnode.State.LogMessage("DataQueue", fmt.Sprintf("dequeue %v", len(q)), msg)
 			msg.(*messages.MissingData).SendResponse(fnode.State)
 		}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/util/clock"
 )
 
 var _ = fmt.Print
@@ -42,6 +43,8 @@ type SimPeer struct {
 
 	Last int64 // Last time reset (nano seconds)
 
+	Clock clock.Clock // Clock the delays are timed on, the clock of the nodes
+
 	RateOut int // Rate of Bytes output per ms
 	RateIn  int // Rate of Bytes input per ms
 }
@@ -126,15 +129,22 @@ func (f *SimPeer) Send(msg interfaces.IMsg) error {
 	go func() {
 		if f.Delay > 0 {
 			// Sleep some random number of milliseconds, then send the packet
-			time.Sleep(time.Duration(rand.Intn(int(f.Delay))) * time.Millisecond)
+			f.clock().Sleep(time.Duration(rand.Intn(int(f.Delay))) * time.Millisecond)
 		}
-		packet := SimPacket{data: data, sent: time.Now().UnixNano() / 1000000}
+		packet := SimPacket{data: data, sent: f.clock().Now().UnixNano() / 1000000}
 		f.BroadcastOut <- &packet
 	}()
 
 	return nil
 }
 
+func (f *SimPeer) clock() clock.Clock {
+	if f.Clock == nil {
+		return clock.Real
+	}
+	return f.Clock
+}
+
 // Non-blocking return value from channel.
 func (f *SimPeer) Receive() (interfaces.IMsg, error) {
 
@@ -195,6 +205,8 @@ func AddSimPeer(fnodes []*FactomNode, i1 int, i2 int) {
 	peer21 := new(SimPeer).Init(f2.State.FactomNodeName, f1.State.FactomNodeName).(*SimPeer)
 	peer12.BroadcastIn = peer21.BroadcastOut
 	peer21.BroadcastIn = peer12.BroadcastOut
+	peer12.Clock = f1.State.GetClock()
+	peer21.Clock = f2.State.GetClock()
 
 	f1.Peers = append(f1.Peers, peer12)
 	f2.Peers = append(f2.Peers, peer21)
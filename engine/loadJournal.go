@@ -6,7 +6,6 @@ package engine
 
 import (
 	"bufio"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
@@ -14,7 +13,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
-	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/state"
 )
 
 func LoadJournal(s interfaces.IState, journal string) {
@@ -54,36 +53,29 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 			break
 		}
 
-		// Get the next word.  If not MsgHex:, then go to next line.
-		adv, word, err := bufio.ScanWords(line, true)
-		if string(word) != "MsgHex:" {
-			continue // Go to next line.
-		}
-		line = line[adv:] // Remove "MsgHex:" from the line.
-
-		// Remove spaces.
-		adv, data, err := bufio.ScanWords(line, true)
+		entry, err := state.ParseJournalLine(line)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
-
-		// Decode the hex
-		binary, err := hex.DecodeString(string(data))
-		if err != nil {
-			fmt.Println(err)
-			return
+		if entry == nil {
+			continue // Go to next line.
 		}
 
-		// Unmarshal the message.
-		msg, err := msgsupport.UnmarshalMessage(binary)
-		if err != nil {
-			fmt.Println(err)
-			return
+		// Queue the message where it was, with the node clock it was journalled at.
+		if st, ok := s.(*state.State); ok {
+			if err := st.ReplayJournalEntry(entry); err != nil {
+				fmt.Println(err)
+				return
+			}
+		} else {
+			msg, err := entry.Msg()
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			s.InMsgQueue().Enqueue(msg)
 		}
-
-		// Process the message.
-		s.InMsgQueue().Enqueue(msg)
 		p++
 		if s.InMsgQueue().Length() > constants.INMSGQUEUE_MED {
 			for s.InMsgQueue().Length() > constants.INMSGQUEUE_LOW {
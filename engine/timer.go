@@ -16,20 +16,21 @@ import (
 // leaders.
 func Timer(stateI interfaces.IState) {
 	s := stateI.(*state.State)
+	clock := s.GetClock()
 
 	var last int64
 	for {
 		tenthPeriod := s.GetMinuteDuration().Nanoseconds() // The length of the minute can change, so do this each time
-		now := time.Now().UnixNano()                       // Get the current time
+		now := clock.Now().UnixNano()                      // Get the current time
 		sleep := tenthPeriod - now%tenthPeriod
-		time.Sleep(time.Duration(sleep)) // Sleep the length of time from now to the next minute
+		clock.Sleep(time.Duration(sleep)) // Sleep the length of time from now to the next minute
 
 		// Delay some number of milliseconds.  This is a debugging tool for testing how well we handle
 		// Leaders running with slightly different minutes in test environments.
-		time.Sleep(time.Duration(s.GetTimeOffset().GetTimeMilli()) * time.Millisecond)
+		clock.Sleep(time.Duration(s.GetTimeOffset().GetTimeMilli()) * time.Millisecond)
 
 		if s.Leader {
-			now = time.Now().UnixNano()
+			now = clock.Now().UnixNano()
 			issueTime := last
 			if s.EOMSyncEnd > s.EOMIssueTime {
 				issueTime = s.EOMIssueTime
@@ -18,6 +18,7 @@ type EventService interface {
 	ConfigSender(state StateEventServices, sender eventservices.EventSender)
 	EmitRegistrationEvent(msg interfaces.IMsg)
 	EmitStateChangeEvent(msg interfaces.IMsg, entityState eventmessages.EntityState)
+	EmitRejectedEvent(msg interfaces.IMsg, reason string)
 	EmitDirectoryBlockCommitEvent(dbState interfaces.IDBState)
 	EmitDirectoryBlockAnchorEvent(dirBlockInfo interfaces.IDirBlockInfo)
 	EmitReplayDirectoryBlockCommit(msg interfaces.IMsg)
@@ -101,6 +102,16 @@ func (eventEmitter *eventEmitter) EmitStateChangeEvent(msg interfaces.IMsg, enti
 	}
 }
 
+func (eventEmitter *eventEmitter) EmitRejectedEvent(msg interfaces.IMsg, reason string) {
+	if eventEmitter.eventSender != nil {
+		switch msg.(type) {
+		case *messages.CommitChainMsg, *messages.CommitEntryMsg, *messages.RevealEntryMsg:
+			event := eventinput.NewRejectedEvent(eventEmitter.GetStreamSource(), msg, reason)
+			eventEmitter.Send(event)
+		}
+	}
+}
+
 func (eventEmitter *eventEmitter) EmitDirectoryBlockCommitEvent(dbState interfaces.IDBState) {
 	if eventEmitter.eventSender != nil {
 		event := eventinput.NewDirectoryBlockEvent(eventEmitter.GetStreamSource(), dbState)
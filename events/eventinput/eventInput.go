@@ -21,6 +21,7 @@ type StateChangeEvent struct {
 	EventSource eventmessages.EventSource
 	EntityState eventmessages.EntityState
 	Payload     interfaces.IMsg
+	Reason      string // Why the message was rejected
 }
 
 type DirectoryBlockEvent struct {
@@ -68,6 +69,10 @@ func (event StateChangeEvent) GetPayload() interfaces.IMsg {
 	return event.Payload
 }
 
+func (event StateChangeEvent) GetReason() string {
+	return event.Reason
+}
+
 func (event DirectoryBlockEvent) GetStreamSource() eventmessages.EventSource {
 	return event.EventSource
 }
@@ -123,6 +128,15 @@ func NewStateChangeEvent(streamSource eventmessages.EventSource, entityState eve
 	}
 }
 
+func NewRejectedEvent(streamSource eventmessages.EventSource, msg interfaces.IMsg, reason string) *StateChangeEvent {
+	return &StateChangeEvent{
+		EventSource: streamSource,
+		EntityState: eventmessages.EntityState_REJECTED,
+		Payload:     msg,
+		Reason:      reason,
+	}
+}
+
 func NewDirectoryBlockEvent(streamSource eventmessages.EventSource, dbState interfaces.IDBState) *DirectoryBlockEvent {
 	return &DirectoryBlockEvent{
 		EventSource: streamSource,
@@ -605,6 +605,7 @@ type StateChange struct {
 	EntityHash           []byte      `protobuf:"bytes,1,opt,name=entityHash,proto3" json:"entityHash,omitempty"`
 	EntityState          EntityState `protobuf:"varint,2,opt,name=entityState,proto3,enum=eventmessages.EntityState" json:"entityState,omitempty"`
 	BlockHeight          uint32      `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	Reason               string      `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -664,6 +665,13 @@ func (m *StateChange) GetBlockHeight() uint32 {
 	return 0
 }
 
+func (m *StateChange) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 type DirectoryBlockCommit struct {
 	DirectoryBlock       *DirectoryBlock    `protobuf:"bytes,1,opt,name=directoryBlock,proto3" json:"directoryBlock,omitempty"`
 	AdminBlock           *AdminBlock        `protobuf:"bytes,2,opt,name=adminBlock,proto3" json:"adminBlock,omitempty"`
@@ -1458,95 +1466,94 @@ func init() {
 func init() { proto.RegisterFile("eventmessages/factomEvents.proto", fileDescriptor_d6566f2e3579336b) }
 
 var fileDescriptor_d6566f2e3579336b = []byte{
-	// 1399 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcf, 0x73, 0xdb, 0xc4,
-	0x17, 0xb7, 0xfc, 0x23, 0x89, 0x9f, 0xec, 0xc4, 0xdd, 0x49, 0xfb, 0xf5, 0x37, 0x14, 0xd7, 0x23,
-	0x0a, 0x13, 0x32, 0x8c, 0x3b, 0x13, 0x98, 0x01, 0x06, 0x28, 0xd8, 0xb2, 0x52, 0xbb, 0x75, 0xec,
-	0xb0, 0x71, 0xe9, 0xa4, 0x97, 0x8c, 0x2c, 0x6d, 0x13, 0x81, 0x2d, 0x65, 0x24, 0x39, 0x6d, 0xff,
-	0x08, 0x0e, 0xbd, 0xc1, 0x81, 0x3f, 0x80, 0x23, 0x07, 0x4e, 0xfc, 0x03, 0x1c, 0x39, 0x70, 0x87,
-	0x29, 0x47, 0xfe, 0x09, 0x66, 0x77, 0x65, 0x6b, 0xb5, 0x52, 0xda, 0xb4, 0x3d, 0xc5, 0xfb, 0xf6,
-	0xf3, 0xd9, 0x7d, 0xef, 0xed, 0x67, 0xdf, 0x3e, 0x05, 0x9a, 0xe4, 0x9c, 0xb8, 0xe1, 0x8c, 0x04,
-	0x81, 0x79, 0x42, 0x82, 0x5b, 0x8f, 0x4c, 0x2b, 0xf4, 0x66, 0x06, 0xb5, 0x05, 0xad, 0x33, 0xdf,
-	0x0b, 0x3d, 0x54, 0x4d, 0x20, 0xb6, 0x6e, 0x9c, 0x78, 0xde, 0xc9, 0x94, 0xdc, 0x62, 0x93, 0x93,
-	0xf9, 0xa3, 0x5b, 0xa1, 0x33, 0x23, 0x41, 0x68, 0xce, 0xce, 0x38, 0x7e, 0xab, 0x91, 0x5c, 0xd1,
-	0xb4, 0x67, 0x8e, 0xdb, 0x99, 0x7a, 0xd6, 0x77, 0xd1, 0xbc, 0x96, 0x9c, 0xb7, 0x1d, 0x9f, 0x58,
-	0xa1, 0xe7, 0x3f, 0x15, 0x31, 0xd2, 0x1a, 0xc4, 0x0d, 0x93, 0xf3, 0x59, 0x5e, 0x3b, 0xb6, 0x80,
-	0xd0, 0xfe, 0x2d, 0x81, 0xba, 0x17, 0x07, 0x83, 0x3e, 0x07, 0x95, 0x71, 0x0e, 0xbd, 0xb9, 0x6f,
-	0x91, 0xba, 0xd2, 0x54, 0xb6, 0xd7, 0x77, 0xb7, 0x5a, 0x89, 0x75, 0x5a, 0x46, 0x8c, 0xc0, 0x22,
-	0x1c, 0xbd, 0x07, 0xeb, 0x3c, 0x33, 0x43, 0xcf, 0x26, 0x43, 0x73, 0x46, 0xea, 0xf9, 0xa6, 0xb2,
-	0x5d, 0xc6, 0x92, 0x15, 0x6d, 0xc3, 0x86, 0x63, 0x13, 0x37, 0x74, 0xc2, 0xa7, 0xfa, 0xa9, 0xe9,
-	0xb8, 0xfd, 0x6e, 0xbd, 0xd0, 0x54, 0xb6, 0x2b, 0x58, 0x36, 0xa3, 0xdb, 0xa0, 0x5a, 0xf4, 0xa7,
-	0xee, 0xcd, 0x66, 0x4e, 0x58, 0x2f, 0x36, 0x95, 0x6d, 0x35, 0xe5, 0x8f, 0x1e, 0x23, 0x7a, 0x39,
-	0x2c, 0x12, 0x28, 0x9f, 0x65, 0x25, 0xe2, 0x97, 0x32, 0xf9, 0x46, 0x8c, 0xa0, 0x7c, 0x81, 0xb0,
-	0xe4, 0x63, 0x72, 0x4e, 0xcc, 0x69, 0x7d, 0xe5, 0x62, 0x3e, 0x47, 0x2c, 0xf9, 0x7c, 0x48, 0xf9,
-	0x41, 0x68, 0x86, 0x44, 0x3f, 0x35, 0xdd, 0x13, 0x52, 0x5f, 0xcd, 0xe4, 0x1f, 0xc6, 0x08, 0xca,
-	0x17, 0x08, 0xe8, 0x08, 0x36, 0x93, 0x27, 0x1f, 0x05, 0xb2, 0xc6, 0x16, 0x7a, 0x47, 0x5a, 0xa8,
-	0x9b, 0x01, 0xed, 0xe5, 0x70, 0xe6, 0x12, 0x68, 0x1f, 0x6a, 0x67, 0xbe, 0x67, 0x91, 0x20, 0x18,
-	0x38, 0x41, 0xc8, 0xce, 0xb4, 0x5e, 0x66, 0xcb, 0xde, 0x90, 0x96, 0x3d, 0x90, 0x60, 0xbd, 0x1c,
-	0x4e, 0x51, 0x69, 0xa4, 0xae, 0x67, 0x93, 0x7d, 0x4e, 0xaa, 0x43, 0x66, 0xa4, 0xc3, 0x18, 0x41,
-	0x23, 0x15, 0x08, 0xe9, 0x48, 0xdb, 0xae, 0x75, 0xea, 0xf9, 0x75, 0xf5, 0x12, 0x91, 0x72, 0x68,
-	0x3a, 0x52, 0x6e, 0xef, 0xac, 0x42, 0x89, 0xb1, 0xb5, 0xbf, 0xf2, 0xa0, 0x0a, 0x62, 0x61, 0x6a,
-	0x67, 0x72, 0x63, 0x27, 0x70, 0x91, 0xda, 0x63, 0x04, 0x16, 0xe1, 0xa8, 0x19, 0x69, 0xb3, 0xdf,
-	0xed, 0x99, 0xc1, 0x29, 0x93, 0x7a, 0x05, 0x8b, 0x26, 0x74, 0x1d, 0xca, 0x4c, 0x0c, 0x6c, 0x9e,
-	0x2b, 0x3c, 0x36, 0x20, 0x04, 0xc5, 0xc7, 0x64, 0x6a, 0x33, 0x51, 0x57, 0x30, 0xfb, 0x8d, 0x3e,
-	0x81, 0xf2, 0xb2, 0x50, 0x2c, 0xd5, 0xca, 0x4b, 0x49, 0x6b, 0x51, 0x4a, 0x5a, 0xe3, 0x05, 0x02,
-	0xc7, 0x60, 0x54, 0x87, 0x55, 0xcb, 0x27, 0xb6, 0x13, 0x06, 0x4c, 0xa5, 0x55, 0xbc, 0x18, 0xa2,
-	0x5d, 0xd8, 0xe4, 0x92, 0x66, 0xe3, 0x83, 0xf9, 0x64, 0xea, 0x58, 0xf7, 0xc8, 0x53, 0x26, 0xc6,
-	0x0a, 0xce, 0x9c, 0xa3, 0x9e, 0x07, 0xce, 0x89, 0x6b, 0x86, 0x73, 0x9f, 0x30, 0xb1, 0x55, 0x70,
-	0x6c, 0xa0, 0x7b, 0x9d, 0x13, 0x3f, 0x70, 0x3c, 0x97, 0x29, 0xa6, 0x8a, 0x17, 0x43, 0xed, 0xe7,
-	0x3c, 0xa8, 0xc2, 0x75, 0x7a, 0xc3, 0x0c, 0x27, 0xf2, 0x97, 0x97, 0xf3, 0x97, 0xc8, 0x55, 0xe1,
-	0x35, 0x73, 0x55, 0xbc, 0x5c, 0xae, 0x4a, 0x97, 0xcd, 0xd5, 0xca, 0x0b, 0x72, 0xb5, 0x9a, 0xcc,
-	0xd5, 0x6f, 0x4a, 0x94, 0xab, 0xa8, 0x56, 0xbc, 0x59, 0xae, 0x3e, 0x82, 0x12, 0xf3, 0x8e, 0xe5,
-	0x49, 0xdd, 0x6d, 0x64, 0xd5, 0x28, 0x76, 0x29, 0xf8, 0x96, 0x1c, 0xfc, 0xfa, 0x39, 0xd4, 0xbe,
-	0x57, 0x40, 0x15, 0x0a, 0x17, 0x6a, 0x00, 0x70, 0x77, 0xd8, 0x61, 0x29, 0x2c, 0x0d, 0x82, 0x45,
-	0x8e, 0x2e, 0xff, 0xca, 0x77, 0x6d, 0x42, 0x9d, 0xef, 0x11, 0xe7, 0xe4, 0x34, 0x64, 0x9e, 0x56,
-	0xb1, 0x68, 0xd2, 0x7e, 0x29, 0xc0, 0x66, 0x56, 0xfd, 0x43, 0x06, 0xac, 0x27, 0xab, 0x02, 0x73,
-	0x4e, 0xdd, 0x7d, 0xfb, 0x85, 0x25, 0x05, 0x4b, 0x24, 0xf4, 0x29, 0x40, 0xfc, 0x46, 0x47, 0x49,
-	0xfe, 0xbf, 0xb4, 0x44, 0x7b, 0x09, 0xc0, 0x02, 0x18, 0x7d, 0x09, 0x15, 0xf1, 0xe9, 0x8d, 0xf2,
-	0xfc, 0x96, 0x44, 0xde, 0x13, 0x20, 0x38, 0x41, 0x40, 0xf7, 0xa0, 0x26, 0x28, 0x8f, 0x2f, 0x52,
-	0xcc, 0x2c, 0xd5, 0x86, 0x04, 0xc3, 0x29, 0x22, 0xfa, 0x2c, 0x7a, 0xd2, 0xd8, 0x28, 0xa8, 0x97,
-	0x9a, 0x85, 0x8c, 0x48, 0x62, 0xb9, 0x60, 0x11, 0x8d, 0x06, 0x70, 0x85, 0x24, 0x94, 0xe4, 0x10,
-	0x5a, 0x6f, 0x0a, 0x97, 0x50, 0x5c, 0x9a, 0xa8, 0x3d, 0x53, 0xa0, 0x26, 0x7b, 0x8c, 0xbe, 0x80,
-	0x95, 0x53, 0x62, 0xda, 0xc4, 0x8f, 0xce, 0xe9, 0xdd, 0x97, 0x84, 0xd8, 0x63, 0x60, 0x1c, 0x91,
-	0xd0, 0x6d, 0x58, 0x25, 0x91, 0x5f, 0x79, 0xe6, 0xd7, 0xcd, 0x97, 0xf0, 0xb9, 0x77, 0x0b, 0x92,
-	0xf6, 0xa7, 0x02, 0xd7, 0xb2, 0xb7, 0x40, 0x5b, 0xb0, 0x36, 0xf1, 0x6c, 0x51, 0xe0, 0xcb, 0x31,
-	0x6a, 0x01, 0x3a, 0xf3, 0xc9, 0xb9, 0xe3, 0xcd, 0x03, 0x8e, 0x16, 0x6a, 0x56, 0xc6, 0x0c, 0xda,
-	0xa1, 0xaf, 0x2f, 0xb7, 0xee, 0xcd, 0xa7, 0x53, 0xe1, 0x85, 0x48, 0xd9, 0x65, 0xf1, 0x17, 0x53,
-	0xe2, 0xa7, 0x08, 0x6f, 0xf2, 0x2d, 0xb1, 0x42, 0xdd, 0x9b, 0xbb, 0xbc, 0xcd, 0x29, 0x62, 0xd1,
-	0xa4, 0x3d, 0x2b, 0xc0, 0xd5, 0xcc, 0xc8, 0xe5, 0x16, 0x4b, 0x79, 0xc3, 0x16, 0x2b, 0xff, 0xaa,
-	0x2d, 0xd6, 0x5d, 0xd8, 0x70, 0x5c, 0xcb, 0x27, 0x66, 0x40, 0x3a, 0xe6, 0xd4, 0x74, 0x2d, 0x12,
-	0x5d, 0x10, 0x59, 0x50, 0xfd, 0x24, 0xaa, 0x97, 0xc3, 0x32, 0x11, 0xb5, 0xa1, 0x32, 0x73, 0xdc,
-	0x79, 0x48, 0x86, 0xf3, 0xd9, 0x84, 0xf8, 0xd1, 0x25, 0x91, 0x6f, 0xda, 0xbe, 0x00, 0xe9, 0xe5,
-	0x70, 0x82, 0x82, 0x0e, 0xe0, 0x4a, 0x40, 0xfc, 0x73, 0xe2, 0xf7, 0x5d, 0x9b, 0x3c, 0x89, 0xd6,
-	0xe1, 0x2f, 0x71, 0x53, 0xee, 0xdb, 0x64, 0x5c, 0x2f, 0x87, 0xd3, 0xe4, 0xce, 0xff, 0xe0, 0x2a,
-	0xc9, 0xca, 0xbc, 0xf6, 0xa3, 0x02, 0x1b, 0x52, 0x50, 0x17, 0x3e, 0x40, 0xca, 0x0b, 0x1e, 0xa0,
-	0x9b, 0x50, 0x0d, 0x7d, 0xd3, 0x0d, 0x4c, 0x2b, 0x74, 0x3c, 0xda, 0x4c, 0x73, 0xd9, 0x25, 0x8d,
-	0x68, 0x13, 0x4a, 0x0e, 0xf5, 0x8a, 0x65, 0xb7, 0x88, 0xf9, 0x00, 0x5d, 0x83, 0x15, 0x73, 0xc6,
-	0x44, 0x53, 0x64, 0xe6, 0x68, 0xa4, 0xed, 0x42, 0x45, 0x4c, 0x13, 0xd2, 0xa4, 0xcc, 0x2a, 0x4c,
-	0x84, 0x09, 0x9b, 0xd6, 0x86, 0x2b, 0xa9, 0x94, 0xa0, 0x0f, 0xb2, 0xf2, 0xc9, 0xd9, 0xe9, 0x09,
-	0xed, 0x27, 0x05, 0x54, 0xa1, 0x49, 0x44, 0x5f, 0x81, 0x1a, 0xa5, 0x5b, 0xf7, 0xec, 0xc5, 0x9b,
-	0xd8, 0xb8, 0xb8, 0xab, 0xa4, 0x28, 0x2c, 0x52, 0xd0, 0x0e, 0x94, 0xa6, 0xe4, 0x9c, 0x4c, 0xa3,
-	0x17, 0x67, 0x53, 0xe2, 0x0e, 0xe8, 0x1c, 0xe6, 0x10, 0x7a, 0x8d, 0xa2, 0x89, 0x31, 0x79, 0xc2,
-	0x5f, 0x99, 0x32, 0x16, 0x4d, 0xda, 0xaf, 0x0a, 0xd4, 0xe4, 0x76, 0x18, 0x75, 0xa1, 0xea, 0x92,
-	0xc7, 0xfc, 0x60, 0x59, 0x1b, 0xcd, 0xef, 0xd0, 0x75, 0xd9, 0x4d, 0x11, 0xd3, 0xcb, 0xe1, 0x24,
-	0x09, 0xdd, 0x81, 0x75, 0x97, 0x3c, 0xe6, 0x49, 0xe7, 0xcb, 0xe4, 0x33, 0xdf, 0xa9, 0x61, 0x02,
-	0xd4, 0xcb, 0x61, 0x89, 0xd6, 0x41, 0xe9, 0xc6, 0x5e, 0xfb, 0x18, 0xaa, 0x89, 0xed, 0xe9, 0xa7,
-	0xda, 0x62, 0xfb, 0xa8, 0xac, 0xf0, 0x33, 0x91, 0xac, 0xda, 0x01, 0xac, 0x27, 0x37, 0xa4, 0xed,
-	0xce, 0x72, 0xc3, 0x88, 0x14, 0x1b, 0xe4, 0x5a, 0x95, 0x4f, 0xd5, 0xaa, 0x9d, 0x6d, 0x50, 0x85,
-	0x0f, 0x48, 0xb4, 0x06, 0xc5, 0x41, 0xff, 0x1b, 0xa3, 0x96, 0x43, 0x1b, 0xa0, 0x62, 0xe3, 0x60,
-	0xd0, 0x3e, 0x3a, 0xee, 0x8c, 0x46, 0xe3, 0x9a, 0xb2, 0xf3, 0x90, 0xf5, 0x47, 0xcb, 0x1e, 0xa0,
-	0x0a, 0x65, 0x6c, 0x7c, 0x7d, 0xdf, 0x38, 0x1c, 0x1b, 0xdd, 0x5a, 0x0e, 0x55, 0x60, 0xad, 0xad,
-	0xeb, 0xc6, 0x01, 0x1d, 0x29, 0x74, 0x84, 0x8d, 0xbb, 0x86, 0x4e, 0x47, 0x79, 0xd4, 0x84, 0xeb,
-	0xfa, 0x68, 0x7f, 0xbf, 0x3f, 0x1e, 0x1b, 0xdd, 0xe3, 0xf1, 0xe8, 0xb8, 0xdb, 0xc7, 0x86, 0x3e,
-	0x1e, 0xe1, 0xa3, 0xe3, 0xce, 0x60, 0xa4, 0xdf, 0xab, 0x15, 0x76, 0xde, 0x87, 0x12, 0x3b, 0x7a,
-	0xba, 0x7f, 0x7f, 0xb8, 0x37, 0xaa, 0xe5, 0x90, 0x0a, 0xab, 0x0f, 0xda, 0x78, 0xd8, 0x1f, 0xde,
-	0xa9, 0x29, 0xa8, 0x0c, 0x25, 0x03, 0xe3, 0x11, 0xae, 0xe5, 0x77, 0x0c, 0xd8, 0x90, 0x14, 0x46,
-	0xa1, 0x77, 0x8c, 0xa1, 0x81, 0xdb, 0x03, 0xce, 0x3b, 0x1c, 0xb7, 0x31, 0xf7, 0x03, 0x60, 0xe5,
-	0xf0, 0x68, 0xa8, 0x33, 0x2f, 0x2a, 0xb0, 0x76, 0xd8, 0xbb, 0x3f, 0xee, 0x8e, 0x1e, 0x0c, 0x6b,
-	0x85, 0x4e, 0xfb, 0xf7, 0xe7, 0x0d, 0xe5, 0x8f, 0xe7, 0x0d, 0xe5, 0xef, 0xe7, 0x0d, 0xe5, 0x87,
-	0x7f, 0x1a, 0x39, 0x68, 0x5a, 0xde, 0xac, 0xc5, 0x3f, 0x8d, 0xa3, 0x3f, 0x76, 0xf2, 0xac, 0x1f,
-	0x26, 0xff, 0xa9, 0x30, 0x59, 0x61, 0x2d, 0xd9, 0x87, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x60,
-	0x04, 0x7d, 0x07, 0x8e, 0x10, 0x00, 0x00,
+	// 1388 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa5, 0x58, 0x4b, 0x73, 0x1b, 0x45,
+	0x10, 0xce, 0xea, 0x61, 0x5b, 0x2d, 0xc9, 0x56, 0xa6, 0x9c, 0x20, 0x4c, 0x70, 0x5c, 0x9b, 0x40,
+	0x05, 0x17, 0x25, 0x57, 0x19, 0xaa, 0x80, 0xe2, 0xa9, 0xc7, 0x3a, 0x56, 0x22, 0x4b, 0x66, 0xac,
+	0x90, 0x4a, 0x2e, 0xae, 0x95, 0x34, 0xb1, 0x17, 0xa4, 0x5d, 0xd7, 0xee, 0xca, 0x49, 0x7e, 0x46,
+	0x6e, 0x70, 0xe0, 0xc8, 0x81, 0x23, 0x07, 0x4e, 0xfc, 0x01, 0x8e, 0x1c, 0xb8, 0x43, 0xc1, 0x91,
+	0x3f, 0x41, 0xcf, 0xcc, 0x5a, 0x9a, 0x9d, 0x5d, 0x27, 0x4e, 0x72, 0x50, 0x59, 0xd3, 0xf3, 0x7d,
+	0x3d, 0x3d, 0x3d, 0xdf, 0xf4, 0xb4, 0x0c, 0x1b, 0xec, 0x94, 0xb9, 0xe1, 0x84, 0x05, 0x81, 0x7d,
+	0xc4, 0x82, 0xad, 0x47, 0xf6, 0x30, 0xf4, 0x26, 0x16, 0xb7, 0x05, 0xb5, 0x13, 0xdf, 0x0b, 0x3d,
+	0x52, 0x8e, 0x21, 0xd6, 0xae, 0x1f, 0x79, 0xde, 0xd1, 0x98, 0x6d, 0x89, 0xc9, 0xc1, 0xf4, 0xd1,
+	0x56, 0xe8, 0xe0, 0x5c, 0x68, 0x4f, 0x4e, 0x24, 0x7e, 0x6d, 0x3d, 0xee, 0xd1, 0x1e, 0x4d, 0x1c,
+	0xb7, 0x31, 0xf6, 0x86, 0xdf, 0x45, 0xf3, 0x66, 0x7c, 0x7e, 0xe4, 0xf8, 0x0c, 0xd7, 0xf4, 0x9f,
+	0xaa, 0x18, 0xcd, 0x07, 0x7e, 0x8f, 0xcf, 0xa7, 0x45, 0xed, 0x8c, 0x14, 0x84, 0xf9, 0x5f, 0x1e,
+	0x8a, 0x3b, 0xf3, 0xcd, 0x90, 0xcf, 0xa0, 0x28, 0x38, 0x07, 0xde, 0xd4, 0x1f, 0xb2, 0xaa, 0xb1,
+	0x61, 0xdc, 0x5a, 0xde, 0x5e, 0xab, 0xc5, 0xfc, 0xd4, 0xac, 0x39, 0x82, 0xaa, 0x70, 0xf2, 0x2e,
+	0x2c, 0xcb, 0xcc, 0x74, 0xbd, 0x11, 0xeb, 0xda, 0x13, 0x56, 0xcd, 0xa0, 0x83, 0x02, 0xd5, 0xac,
+	0xe4, 0x16, 0xac, 0x38, 0x23, 0xa4, 0x39, 0xe1, 0xd3, 0xe6, 0xb1, 0xed, 0xb8, 0xed, 0x56, 0x35,
+	0x8b, 0xc0, 0x12, 0xd5, 0xcd, 0xe4, 0x0b, 0x28, 0x0e, 0xf9, 0xd7, 0xa6, 0x37, 0x99, 0x38, 0x61,
+	0x35, 0x87, 0xa8, 0x62, 0x22, 0x9e, 0xe6, 0x1c, 0xb1, 0x7b, 0x89, 0xaa, 0x04, 0xce, 0x17, 0x59,
+	0x89, 0xf8, 0xf9, 0x54, 0xbe, 0x35, 0x47, 0x70, 0xbe, 0x42, 0x98, 0xf1, 0x29, 0x32, 0xec, 0x71,
+	0x75, 0xe1, 0x7c, 0xbe, 0x44, 0xcc, 0xf8, 0x72, 0xc8, 0xf9, 0x78, 0xe8, 0x21, 0xc3, 0x10, 0xdd,
+	0x23, 0x56, 0x5d, 0x4c, 0xe5, 0x1f, 0xcc, 0x11, 0x9c, 0xaf, 0x10, 0xc8, 0x03, 0x58, 0x8d, 0x9f,
+	0x7c, 0xb4, 0x91, 0x25, 0xe1, 0xe8, 0x86, 0xe6, 0xa8, 0x95, 0x02, 0x45, 0x8f, 0xa9, 0x2e, 0xc8,
+	0x1e, 0x54, 0x50, 0x03, 0x43, 0xe4, 0x76, 0x9c, 0x20, 0x14, 0x67, 0x5a, 0x2d, 0x08, 0xb7, 0xd7,
+	0x35, 0xb7, 0xfb, 0x1a, 0x0c, 0x5d, 0x26, 0xa8, 0x7c, 0xa7, 0x2e, 0x9e, 0xef, 0x9e, 0x24, 0x55,
+	0x21, 0x75, 0xa7, 0xdd, 0x39, 0x82, 0xef, 0x54, 0x21, 0x24, 0x77, 0x5a, 0x77, 0x87, 0xc7, 0x9e,
+	0x5f, 0x2d, 0x5e, 0x60, 0xa7, 0x12, 0x9a, 0xdc, 0xa9, 0xb4, 0x37, 0x16, 0x21, 0x2f, 0xd8, 0xe6,
+	0x5f, 0x19, 0x28, 0x2a, 0x62, 0x11, 0x6a, 0x17, 0x72, 0x13, 0x27, 0x70, 0x9e, 0xda, 0xe7, 0x08,
+	0xaa, 0xc2, 0xc9, 0x46, 0xa4, 0xcd, 0x76, 0x6b, 0xd7, 0x0e, 0x8e, 0x85, 0xd4, 0x4b, 0x54, 0x35,
+	0x91, 0x6b, 0x50, 0x10, 0x62, 0x10, 0xf3, 0x52, 0xe1, 0x73, 0x03, 0x21, 0x90, 0x7b, 0xcc, 0xc6,
+	0x23, 0x21, 0xea, 0x12, 0x15, 0xdf, 0xc9, 0xc7, 0x50, 0x98, 0x15, 0x8a, 0x99, 0x5a, 0x65, 0x29,
+	0xa9, 0x9d, 0x95, 0x92, 0x5a, 0xff, 0x0c, 0x41, 0xe7, 0x60, 0x52, 0x85, 0xc5, 0xa1, 0xcf, 0x46,
+	0x4e, 0x18, 0x08, 0x95, 0x96, 0xe9, 0xd9, 0x90, 0x6c, 0xc3, 0xaa, 0x94, 0xb4, 0x18, 0xef, 0x4f,
+	0x07, 0x63, 0x67, 0x78, 0x97, 0x3d, 0x15, 0x62, 0x2c, 0xd1, 0xd4, 0x39, 0x1e, 0x79, 0xe0, 0x1c,
+	0xb9, 0x76, 0x38, 0xf5, 0x99, 0x10, 0x1b, 0x46, 0x3e, 0x33, 0xf0, 0xb5, 0x4e, 0x99, 0x1f, 0x38,
+	0x9e, 0x2b, 0x14, 0x83, 0x6b, 0x45, 0x43, 0xf3, 0x67, 0xcc, 0xb0, 0x72, 0x9d, 0x5e, 0x33, 0xc3,
+	0xb1, 0xfc, 0x65, 0xf4, 0xfc, 0xc5, 0x72, 0x95, 0x7d, 0xc5, 0x5c, 0xe5, 0x2e, 0x96, 0xab, 0xfc,
+	0x45, 0x73, 0xb5, 0xf0, 0x9c, 0x5c, 0x2d, 0xc6, 0x73, 0xf5, 0x9b, 0x11, 0xe5, 0x2a, 0xaa, 0x15,
+	0xaf, 0x97, 0xab, 0x0f, 0x51, 0xe4, 0xdc, 0x99, 0xc8, 0x53, 0x71, 0x7b, 0x3d, 0xad, 0x46, 0x89,
+	0x4b, 0x21, 0x97, 0x94, 0xe0, 0x57, 0xcf, 0xa1, 0xf9, 0x13, 0x46, 0xaf, 0x14, 0x2e, 0xb2, 0x0e,
+	0x20, 0xc3, 0x11, 0x87, 0x65, 0x88, 0x34, 0x28, 0x16, 0x7d, 0x77, 0x99, 0x97, 0xbe, 0x6b, 0x03,
+	0x1e, 0xfc, 0x2e, 0x73, 0x8e, 0x8e, 0x43, 0x11, 0x69, 0x99, 0xaa, 0x26, 0x72, 0x15, 0x16, 0x7c,
+	0x66, 0x07, 0x98, 0xe6, 0x9c, 0x78, 0x73, 0xa2, 0x91, 0xf9, 0x4b, 0x16, 0x56, 0xd3, 0xea, 0x22,
+	0xb1, 0x60, 0x39, 0x5e, 0x2d, 0x44, 0xd0, 0xc5, 0xed, 0xb7, 0x9f, 0x5b, 0x6a, 0xa8, 0x46, 0x22,
+	0x9f, 0x00, 0xcc, 0xdf, 0xee, 0x28, 0xf9, 0x6f, 0x6a, 0x2e, 0xea, 0x33, 0x00, 0x55, 0xc0, 0xe4,
+	0x4b, 0x28, 0xa9, 0x4f, 0x72, 0x94, 0xff, 0xb7, 0x34, 0xf2, 0x8e, 0x02, 0xa1, 0x31, 0x02, 0xb9,
+	0x0b, 0x15, 0x45, 0x91, 0xd2, 0x49, 0x2e, 0xb5, 0x84, 0x5b, 0x1a, 0x8c, 0x26, 0x88, 0xe4, 0xd3,
+	0xe8, 0xa9, 0x13, 0xa3, 0x00, 0x15, 0x9f, 0x4d, 0xd9, 0xc9, 0x5c, 0x46, 0x54, 0x45, 0x93, 0x0e,
+	0x5c, 0x66, 0x31, 0x85, 0x39, 0x8c, 0xd7, 0xa1, 0xec, 0x05, 0x94, 0x98, 0x24, 0x9a, 0xcf, 0x0c,
+	0xa8, 0xe8, 0x11, 0x93, 0xcf, 0x61, 0xe1, 0x98, 0xd9, 0x23, 0xe6, 0x47, 0xe7, 0xf4, 0xce, 0x0b,
+	0xb6, 0xb8, 0x2b, 0xc0, 0x34, 0x22, 0xe1, 0xfb, 0xb4, 0xc8, 0xa2, 0xb8, 0x32, 0x22, 0xae, 0x9b,
+	0x2f, 0xe0, 0xcb, 0xe8, 0xce, 0x48, 0xe6, 0x9f, 0x06, 0x5c, 0x4d, 0x5f, 0x82, 0xac, 0xc1, 0xd2,
+	0xc0, 0x1b, 0xa9, 0xc2, 0x9f, 0x8d, 0x49, 0x0d, 0xc8, 0x89, 0xcf, 0x4e, 0x1d, 0x6f, 0x1a, 0x48,
+	0xb4, 0x52, 0xcb, 0x52, 0x66, 0xc8, 0x26, 0x7f, 0x95, 0xa5, 0x75, 0x67, 0x3a, 0x1e, 0x2b, 0x2f,
+	0x47, 0xc2, 0xae, 0x5f, 0x8a, 0x5c, 0xf2, 0x52, 0x20, 0xc2, 0x1b, 0x7c, 0x8b, 0x72, 0x6d, 0x7a,
+	0x53, 0x57, 0xb6, 0x3f, 0x39, 0xaa, 0x9a, 0xcc, 0x67, 0x59, 0xb8, 0x92, 0xba, 0x73, 0xbd, 0xf5,
+	0x32, 0x5e, 0xb3, 0xf5, 0xca, 0xbc, 0x6c, 0xeb, 0x75, 0x07, 0x9b, 0x44, 0x77, 0xc8, 0x6f, 0x31,
+	0x6b, 0xd8, 0x63, 0xdb, 0xc5, 0x76, 0x34, 0x9b, 0x5a, 0xda, 0xda, 0x71, 0x14, 0xfa, 0xd1, 0x89,
+	0xa4, 0x0e, 0x25, 0xbc, 0x75, 0xd3, 0x90, 0x75, 0xa7, 0x93, 0x01, 0x2a, 0x28, 0x97, 0x7a, 0xd3,
+	0xf6, 0x14, 0x08, 0x7a, 0x89, 0x51, 0xc8, 0x3e, 0x5c, 0x0e, 0x98, 0x8f, 0xb5, 0xbb, 0xed, 0x8e,
+	0xd8, 0x93, 0xc8, 0x8f, 0x7c, 0xa1, 0x37, 0xf4, 0x7e, 0x4e, 0xc7, 0xa1, 0xb3, 0x24, 0xb9, 0xf1,
+	0x06, 0x5c, 0x61, 0x69, 0x99, 0x37, 0x7f, 0x30, 0x60, 0x45, 0xdb, 0xd4, 0xb9, 0x0f, 0x93, 0xf1,
+	0x9c, 0x87, 0xe9, 0x26, 0x94, 0x43, 0xdf, 0x76, 0x03, 0x2c, 0x19, 0xf8, 0xde, 0x60, 0x93, 0x2d,
+	0x65, 0x17, 0x37, 0x92, 0x55, 0xc8, 0x3b, 0x3c, 0x2a, 0x91, 0xdd, 0x1c, 0x95, 0x03, 0x5e, 0x4e,
+	0xed, 0x89, 0x10, 0x4d, 0x4e, 0x98, 0xa3, 0x91, 0xb9, 0x0d, 0x25, 0x35, 0x4d, 0xc4, 0xd4, 0x32,
+	0x6b, 0x08, 0x11, 0xc6, 0x6c, 0x66, 0x1d, 0x2e, 0x27, 0x52, 0x42, 0xde, 0x4f, 0xcb, 0xa7, 0x64,
+	0x27, 0x27, 0xcc, 0x1f, 0xf1, 0xb5, 0x51, 0x9a, 0x47, 0xf2, 0x15, 0x14, 0xa3, 0x74, 0x37, 0xd1,
+	0x1a, 0xbd, 0x95, 0xeb, 0xe7, 0x77, 0x9b, 0x1c, 0x45, 0x55, 0x0a, 0x5e, 0xb4, 0xfc, 0x18, 0xe1,
+	0xe3, 0xe8, 0x25, 0x5a, 0xd5, 0xb8, 0x1d, 0x3e, 0x47, 0x25, 0x84, 0x5f, 0xa3, 0x68, 0xa2, 0xcf,
+	0x9e, 0xc8, 0xd7, 0xa7, 0x40, 0x55, 0x93, 0xf9, 0x2b, 0x56, 0x2c, 0xbd, 0x4d, 0x26, 0x2d, 0x28,
+	0xbb, 0xec, 0xb1, 0x3c, 0x58, 0xd1, 0x5e, 0xcb, 0x3b, 0x74, 0x4d, 0x0f, 0x53, 0xc5, 0xa0, 0x54,
+	0xe2, 0x24, 0x72, 0x1b, 0x96, 0xd1, 0x20, 0x93, 0x2e, 0xdd, 0x64, 0x52, 0xdf, 0xa9, 0x6e, 0x0c,
+	0x84, 0x7e, 0x34, 0x5a, 0x83, 0x24, 0x1b, 0x7e, 0xf3, 0x23, 0x28, 0xc7, 0x96, 0xe7, 0x3f, 0xe1,
+	0xce, 0x96, 0x8f, 0xca, 0x8a, 0x3c, 0x13, 0xcd, 0x6a, 0xee, 0xc3, 0x72, 0x7c, 0x41, 0xde, 0x06,
+	0xcd, 0x16, 0x8c, 0x48, 0x73, 0x83, 0x5e, 0xab, 0x32, 0x89, 0x5a, 0xb5, 0x79, 0x0b, 0xbb, 0x21,
+	0xe5, 0xb7, 0xe4, 0x12, 0xe4, 0x3a, 0xed, 0x6f, 0xac, 0xca, 0x25, 0xb2, 0x02, 0x45, 0x6a, 0xed,
+	0x77, 0xea, 0x0f, 0x0e, 0x1b, 0xbd, 0x5e, 0xbf, 0x62, 0x6c, 0x3e, 0x14, 0x7d, 0xd3, 0xac, 0x37,
+	0x28, 0x43, 0x81, 0x5a, 0x5f, 0xdf, 0xb3, 0x0e, 0xfa, 0x56, 0x0b, 0xe1, 0x25, 0x58, 0xaa, 0x37,
+	0x9b, 0xd6, 0x3e, 0x1f, 0x19, 0x7c, 0x44, 0xad, 0x3b, 0x56, 0x93, 0x8f, 0x32, 0x18, 0xc5, 0xb5,
+	0x66, 0x6f, 0x6f, 0xaf, 0xdd, 0xc7, 0xe1, 0x61, 0xbf, 0x77, 0xd8, 0x6a, 0x53, 0x9c, 0xea, 0x51,
+	0x74, 0xdd, 0xe9, 0x35, 0xef, 0x56, 0xb2, 0x9b, 0xef, 0x41, 0x5e, 0x1c, 0x3d, 0x5f, 0xbf, 0xdd,
+	0xdd, 0xe9, 0xa1, 0xc3, 0x22, 0x2c, 0xde, 0xaf, 0xd3, 0x6e, 0xbb, 0x7b, 0x1b, 0xfd, 0x15, 0x20,
+	0x6f, 0x51, 0xda, 0xa3, 0x95, 0xcc, 0xa6, 0x05, 0x2b, 0x9a, 0xc2, 0x38, 0xf4, 0xb6, 0xd5, 0xb5,
+	0x68, 0xbd, 0x23, 0x79, 0x07, 0xfd, 0x3a, 0x95, 0x71, 0x00, 0x2c, 0x1c, 0x3c, 0xe8, 0x36, 0x45,
+	0x14, 0x18, 0xd3, 0xc1, 0xee, 0xbd, 0x7e, 0xab, 0x77, 0xbf, 0x5b, 0xc9, 0x36, 0xea, 0xbf, 0xff,
+	0xb3, 0x6e, 0xfc, 0x81, 0x9f, 0xbf, 0xf1, 0xf3, 0xfd, 0xbf, 0xeb, 0x97, 0x60, 0x63, 0xe8, 0x4d,
+	0x6a, 0xf2, 0x27, 0x73, 0xf4, 0x67, 0x14, 0x3f, 0xeb, 0x87, 0xf1, 0x7f, 0x36, 0x0c, 0x16, 0x44,
+	0xab, 0xf6, 0xc1, 0xff, 0x9b, 0x6e, 0xc5, 0x44, 0xa6, 0x10, 0x00, 0x00,
 }
 
 func (m *FactomEvent) Marshal() (dAtA []byte, err error) {
@@ -2016,6 +2023,13 @@ func (m *StateChange) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.BlockHeight != 0 {
 		i = encodeVarintFactomEvents(dAtA, i, uint64(m.BlockHeight))
 		i--
@@ -2951,6 +2965,10 @@ func (m *StateChange) Size() (n int) {
 	if m.BlockHeight != 0 {
 		n += 1 + sovFactomEvents(uint64(m.BlockHeight))
 	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -4511,6 +4529,38 @@ func (m *StateChange) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFactomEvents(dAtA[iNdEx:])
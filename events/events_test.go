@@ -109,6 +109,35 @@ func TestAddToAndDeleteFromHolding(t *testing.T) {
 	}
 }
 
+func TestDeleteFromHoldingWithRejectionCode(t *testing.T) {
+	eventQueue := make(chan *eventmessages.FactomEvent, p2p.StandardChannelSize)
+	mockSender := &mockEventSender{
+		eventsOutQueue:      eventQueue,
+		replayDuringStartup: true,
+		sendStateChange:     true,
+	}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, mockSender)
+
+	msg := &messages.CommitChainMsg{CommitChain: entryCreditBlock.NewCommitChain()}
+	msg.CommitChain.MilliTime = createByteSlice6Timestamp(-2 * 1e3)
+
+	// A reason with a code sends one REJECTED state change, and is kept for the ack APIs
+	s.AddToHolding(msg.GetMsgHash().Fixed(), msg)
+	s.DeleteFromHolding(msg.GetMsgHash().Fixed(), msg, "InvalidMsg")
+
+	if assert.Equal(t, 2, len(eventQueue)) {
+		<-eventQueue // added to holding
+		stateChangeEvent := (<-eventQueue).GetStateChange()
+		if assert.NotNil(t, stateChangeEvent) {
+			assert.Equal(t, eventmessages.EntityState_REJECTED, stateChangeEvent.GetEntityState())
+		}
+	}
+	code, _, _ := s.GetMsgRejection(msg.CommitChain.EntryHash, msg.Type())
+	assert.NotEqual(t, "", code)
+}
+
 func TestAddToProcessList(t *testing.T) {
 	eventQueue := make(chan *eventmessages.FactomEvent, p2p.StandardChannelSize)
 	mockSender := &mockEventSender{
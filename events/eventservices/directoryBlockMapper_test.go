@@ -12,7 +12,7 @@ import (
 
 func TestMapDirectoryBlock(t *testing.T) {
 	block := newDirectoryBlock()
-	directoryBlock := mapDirectoryBlock(block)
+	directoryBlock := MapDirectoryBlock(block)
 
 	assert.NotNil(t, directoryBlock.Header)
 	assert.NotNil(t, directoryBlock.Entries)
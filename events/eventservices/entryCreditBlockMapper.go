@@ -6,7 +6,7 @@ import (
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 )
 
-func mapEntryCreditBlock(block interfaces.IEntryCreditBlock) *eventmessages.EntryCreditBlock {
+func MapEntryCreditBlock(block interfaces.IEntryCreditBlock) *eventmessages.EntryCreditBlock {
 	return &eventmessages.EntryCreditBlock{
 		Header:  mapEntryCreditBlockHeader(block.GetHeader()),
 		Entries: mapEntryCreditBlockEntries(block.GetEntries()),
@@ -24,7 +24,7 @@ func TestMapEntryCreditBlock(t *testing.T) {
 	block.GetBody().AddEntry(commitChain)
 	block.GetBody().AddEntry(commitEntry)
 
-	mappedBlock := mapEntryCreditBlock(block)
+	mappedBlock := MapEntryCreditBlock(block)
 
 	assert.NotNil(t, mappedBlock)
 	if assert.NotNil(t, mappedBlock.Header) {
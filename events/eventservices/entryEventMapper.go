@@ -40,7 +40,7 @@ func mapRevealEntryEvent(entityState eventmessages.EntityState, revealEntry *mes
 	return &eventmessages.FactomEvent_EntryReveal{
 		EntryReveal: &eventmessages.EntryReveal{
 			EntityState: entityState,
-			Entry:       mapEntryBlockEntry(revealEntry.Entry, true),
+			Entry:       MapEntryBlockEntry(revealEntry.Entry, true),
 			Timestamp:   ConvertTimeToTimestamp(revealEntry.Timestamp.GetTime()),
 		},
 	}
@@ -56,7 +56,7 @@ func mapRevealEntryEventState(state eventmessages.EntityState, revealEntry *mess
 	return result
 }
 
-func mapEntryBlocks(blocks []interfaces.IEntryBlock) []*eventmessages.EntryBlock {
+func MapEntryBlocks(blocks []interfaces.IEntryBlock) []*eventmessages.EntryBlock {
 	result := make([]*eventmessages.EntryBlock, len(blocks))
 	for i, block := range blocks {
 		result[i] = &eventmessages.EntryBlock{
@@ -87,15 +87,15 @@ func mapEntryBlockHeader(header interfaces.IEntryBlockHeader) *eventmessages.Ent
 	}
 }
 
-func mapEntryBlockEntries(entries []interfaces.IEBEntry, shouldIncludeContent bool) []*eventmessages.EntryBlockEntry {
+func MapEntryBlockEntries(entries []interfaces.IEBEntry, shouldIncludeContent bool) []*eventmessages.EntryBlockEntry {
 	result := make([]*eventmessages.EntryBlockEntry, len(entries))
 	for i, entry := range entries {
-		result[i] = mapEntryBlockEntry(entry, shouldIncludeContent)
+		result[i] = MapEntryBlockEntry(entry, shouldIncludeContent)
 	}
 	return result
 }
 
-func mapEntryBlockEntry(entry interfaces.IEBEntry, shouldIncludeContent bool) *eventmessages.EntryBlockEntry {
+func MapEntryBlockEntry(entry interfaces.IEBEntry, shouldIncludeContent bool) *eventmessages.EntryBlockEntry {
 	blockEntry := &eventmessages.EntryBlockEntry{
 		Hash:    entry.GetHash().Bytes(),
 		ChainID: entry.GetChainIDHash().Bytes(),
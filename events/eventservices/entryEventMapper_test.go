@@ -62,7 +62,7 @@ func TestMapRevealEntryEventState(t *testing.T) {
 
 func TestMapEntryBlocks(t *testing.T) {
 	blocks := newTestEntryBlocks()
-	entryBlocks := mapEntryBlocks(blocks)
+	entryBlocks := MapEntryBlocks(blocks)
 
 	assert.NotNil(t, entryBlocks)
 	assert.NotNil(t, 1, len(entryBlocks))
@@ -100,7 +100,7 @@ func TestMapEntryBlockHeader(t *testing.T) {
 
 func TestMapEntryBlockEntries(t *testing.T) {
 	entries := []interfaces.IEBEntry{new(entryBlock.Entry)}
-	entryBlockEntries := mapEntryBlockEntries(entries, false)
+	entryBlockEntries := MapEntryBlockEntries(entries, false)
 
 	assert.NotNil(t, entryBlockEntries)
 	assert.Equal(t, 1, len(entryBlockEntries))
@@ -115,7 +115,7 @@ func TestMapEntryBlockEntries(t *testing.T) {
 func TestMapEntryBlockEntriesWithContent(t *testing.T) {
 	entry := entryBlock.RandomEntry()
 	entries := []interfaces.IEBEntry{entry}
-	entryBlockEntries := mapEntryBlockEntries(entries, true)
+	entryBlockEntries := MapEntryBlockEntries(entries, true)
 
 	assert.NotNil(t, entryBlockEntries)
 	assert.Equal(t, 1, len(entryBlockEntries))
@@ -130,7 +130,7 @@ func TestMapEntryBlockEntriesWithContent(t *testing.T) {
 func TestMapEntryBlockEntry(t *testing.T) {
 	entry := new(entryBlock.Entry)
 
-	entryBlockEntry := mapEntryBlockEntry(entry, false)
+	entryBlockEntry := MapEntryBlockEntry(entry, false)
 
 	assert.NotNil(t, entryBlockEntry)
 	assert.NotNil(t, entryBlockEntry.Version)
@@ -141,7 +141,7 @@ func TestMapEntryBlockEntry(t *testing.T) {
 
 func TestMapEntryBlockEntryWithContent(t *testing.T) {
 	entry := entryBlock.RandomEntry()
-	entryBlockEntry := mapEntryBlockEntry(entry, true)
+	entryBlockEntry := MapEntryBlockEntry(entry, true)
 
 	assert.NotNil(t, entryBlockEntry)
 	assert.NotNil(t, entryBlockEntry.Version)
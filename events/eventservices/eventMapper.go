@@ -103,6 +103,9 @@ func mapStateChangeEvent(stateChangeEvent *eventinput.StateChangeEvent, broadcas
 		default:
 			return nil, errors.New("unknown message type")
 		}
+		if stateChange, ok := event.Event.(*eventmessages.FactomEvent_StateChange); ok {
+			stateChange.StateChange.Reason = stateChangeEvent.GetReason()
+		}
 	}
 	return event, nil
 }
@@ -165,24 +168,24 @@ func mapNodeMessageEvent(nodeMessageEvent *eventinput.NodeMessageEvent) (*eventm
 
 func mapDBStateFromMsg(dbStateMessage *messages.DBStateMsg, shouldIncludeContent bool) *eventmessages.FactomEvent_DirectoryBlockCommit {
 	event := &eventmessages.FactomEvent_DirectoryBlockCommit{DirectoryBlockCommit: &eventmessages.DirectoryBlockCommit{
-		DirectoryBlock:    mapDirectoryBlock(dbStateMessage.DirectoryBlock),
+		DirectoryBlock:    MapDirectoryBlock(dbStateMessage.DirectoryBlock),
 		AdminBlock:        MapAdminBlock(dbStateMessage.AdminBlock),
-		FactoidBlock:      mapFactoidBlock(dbStateMessage.FactoidBlock),
-		EntryCreditBlock:  mapEntryCreditBlock(dbStateMessage.EntryCreditBlock),
-		EntryBlocks:       mapEntryBlocks(dbStateMessage.EBlocks),
-		EntryBlockEntries: mapEntryBlockEntries(dbStateMessage.Entries, shouldIncludeContent),
+		FactoidBlock:      MapFactoidBlock(dbStateMessage.FactoidBlock),
+		EntryCreditBlock:  MapEntryCreditBlock(dbStateMessage.EntryCreditBlock),
+		EntryBlocks:       MapEntryBlocks(dbStateMessage.EBlocks),
+		EntryBlockEntries: MapEntryBlockEntries(dbStateMessage.Entries, shouldIncludeContent),
 	}}
 	return event
 }
 
 func mapDirectoryBlockState(dbState interfaces.IDBState, shouldIncludeContent bool) *eventmessages.FactomEvent_DirectoryBlockCommit {
 	event := &eventmessages.FactomEvent_DirectoryBlockCommit{DirectoryBlockCommit: &eventmessages.DirectoryBlockCommit{
-		DirectoryBlock:    mapDirectoryBlock(dbState.GetDirectoryBlock()),
+		DirectoryBlock:    MapDirectoryBlock(dbState.GetDirectoryBlock()),
 		AdminBlock:        MapAdminBlock(dbState.GetAdminBlock()),
-		FactoidBlock:      mapFactoidBlock(dbState.GetFactoidBlock()),
-		EntryCreditBlock:  mapEntryCreditBlock(dbState.GetEntryCreditBlock()),
-		EntryBlocks:       mapEntryBlocks(dbState.GetEntryBlocks()),
-		EntryBlockEntries: mapEntryBlockEntries(dbState.GetEntries(), shouldIncludeContent),
+		FactoidBlock:      MapFactoidBlock(dbState.GetFactoidBlock()),
+		EntryCreditBlock:  MapEntryCreditBlock(dbState.GetEntryCreditBlock()),
+		EntryBlocks:       MapEntryBlocks(dbState.GetEntryBlocks()),
+		EntryBlockEntries: MapEntryBlockEntries(dbState.GetEntries(), shouldIncludeContent),
 	}}
 	return event
 }
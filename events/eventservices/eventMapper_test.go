@@ -228,6 +228,28 @@ func TestStateChangeMapping(t *testing.T) {
 	assert.EqualValues(t, entityHash, stateChangedEvent.EntityHash)
 }
 
+func TestRejectedStateChangeMapping(t *testing.T) {
+	msg := newCommitEntryMsg()
+	inputEvent := eventinput.NewRejectedEvent(eventmessages.EventSource_LIVE, msg, "replay")
+	event, err := eventservices.MapToFactomEvent(inputEvent, eventconfig.BroadcastAlways, true)
+	if err != nil {
+		t.Error(err)
+	}
+
+	stateChangedEvent := event.GetStateChange()
+	if assert.NotNil(t, stateChangedEvent) {
+		assert.EqualValues(t, eventmessages.EntityState_REJECTED, stateChangedEvent.EntityState)
+		assert.Equal(t, "replay", stateChangedEvent.Reason)
+	}
+
+	// The reason survives the wire format
+	data, err := event.Marshal()
+	assert.Nil(t, err)
+	decoded := new(eventmessages.FactomEvent)
+	assert.Nil(t, decoded.Unmarshal(data))
+	assert.Equal(t, "replay", decoded.GetStateChange().GetReason())
+}
+
 func msgChangedMessage(msgName string) string {
 	return fmt.Sprintf("%s changed, please reevalate properties used by this event and adjust the expected message length.", msgName)
 }
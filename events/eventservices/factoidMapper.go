@@ -5,7 +5,7 @@ import (
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 )
 
-func mapFactoidBlock(block interfaces.IFBlock) *eventmessages.FactoidBlock {
+func MapFactoidBlock(block interfaces.IFBlock) *eventmessages.FactoidBlock {
 	result := &eventmessages.FactoidBlock{
 		BodyMerkleRoot:              block.GetBodyMR().Bytes(),
 		KeyMerkleRoot:               block.GetKeyMR().Bytes(),
@@ -12,7 +12,7 @@ import (
 
 func TestMapFactoidBlock(t *testing.T) {
 	block := factoid.NewFBlock(nil)
-	factoidBlock := mapFactoidBlock(block)
+	factoidBlock := MapFactoidBlock(block)
 
 	assert.NotNil(t, factoidBlock)
 	assert.NotNil(t, factoidBlock.BlockHeight)
@@ -0,0 +1,157 @@
+package grpcapi_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	. "github.com/FactomProject/factomd/grpcapi"
+)
+
+// The Go types of the messages of factomd.proto, generated in factomd.pb.go
+var protoTypes = map[string]reflect.Type{
+	"HeightRequest":     reflect.TypeOf(HeightRequest{}),
+	"HashRequest":       reflect.TypeOf(HashRequest{}),
+	"ChainHeadRequest":  reflect.TypeOf(ChainHeadRequest{}),
+	"ChainHeadResponse": reflect.TypeOf(ChainHeadResponse{}),
+	"BalanceRequest":    reflect.TypeOf(BalanceRequest{}),
+	"BalanceResponse":   reflect.TypeOf(BalanceResponse{}),
+	"SubmitRequest":     reflect.TypeOf(SubmitRequest{}),
+	"SubmitResponse":    reflect.TypeOf(SubmitResponse{}),
+	"StreamRequest":     reflect.TypeOf(StreamRequest{}),
+
+	"eventmessages.DirectoryBlock":       reflect.TypeOf(eventmessages.DirectoryBlock{}),
+	"eventmessages.AdminBlock":           reflect.TypeOf(eventmessages.AdminBlock{}),
+	"eventmessages.FactoidBlock":         reflect.TypeOf(eventmessages.FactoidBlock{}),
+	"eventmessages.EntryCreditBlock":     reflect.TypeOf(eventmessages.EntryCreditBlock{}),
+	"eventmessages.EntryBlockEntry":      reflect.TypeOf(eventmessages.EntryBlockEntry{}),
+	"eventmessages.DirectoryBlockCommit": reflect.TypeOf(eventmessages.DirectoryBlockCommit{}),
+}
+
+// The Go type and wire type of the scalar proto types used in factomd.proto
+var protoScalars = map[string]struct {
+	goType reflect.Type
+	wire   string
+}{
+	"uint32": {reflect.TypeOf(uint32(0)), "varint"},
+	"int64":  {reflect.TypeOf(int64(0)), "varint"},
+	"bool":   {reflect.TypeOf(false), "varint"},
+	"string": {reflect.TypeOf(""), "bytes"},
+	"bytes":  {reflect.TypeOf([]byte(nil)), "bytes"},
+}
+
+var (
+	protoRPC     = regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(\w+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	protoMessage = regexp.MustCompile(`(?s)message\s+(\w+)\s*\{(.*?)\}`)
+	protoField   = regexp.MustCompile(`(\w+)\s+(\w+)\s*=\s*(\d+)\s*;`)
+)
+
+func readProto(t *testing.T) string {
+	b, err := ioutil.ReadFile("factomd.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop the comments, they may hold anything
+	return regexp.MustCompile(`//[^\n]*`).ReplaceAllString(string(b), "")
+}
+
+// protoFieldCount counts the fields of a message, leaving out the XXX_ fields protoc adds
+func protoFieldCount(typ reflect.Type) (n int) {
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("protobuf") != "" {
+			n++
+		}
+	}
+	return
+}
+
+// TestProtoMessages checks the generated messages have the fields of factomd.proto, so factomd.pb.go is
+// regenerated when the proto changes
+func TestProtoMessages(t *testing.T) {
+	messages := protoMessage.FindAllStringSubmatch(readProto(t), -1)
+	if len(messages) == 0 {
+		t.Fatal("No messages in factomd.proto")
+	}
+	for _, m := range messages {
+		typ, ok := protoTypes[m[1]]
+		if !ok {
+			t.Errorf("Message %s has no Go type", m[1])
+			continue
+		}
+		fields := protoField.FindAllStringSubmatch(m[2], -1)
+		if n := protoFieldCount(typ); len(fields) != n {
+			t.Errorf("%s has %d fields in factomd.proto and %d in Go", m[1], len(fields), n)
+		}
+		for _, f := range fields {
+			scalar, ok := protoScalars[f[1]]
+			if !ok {
+				t.Errorf("%s.%s is of type %s, add it to the test", m[1], f[2], f[1])
+				continue
+			}
+			tag := fmt.Sprintf("%s,%s,opt,name=%s,proto3", scalar.wire, f[3], f[2])
+			found := false
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				if field.Tag.Get("protobuf") != tag {
+					continue
+				}
+				found = true
+				if field.Type != scalar.goType {
+					t.Errorf("%s.%s is a %s, expected a %s", m[1], field.Name, field.Type, scalar.goType)
+				}
+			}
+			if !found {
+				t.Errorf("%s has no field tagged %q", m[1], tag)
+			}
+		}
+	}
+}
+
+// TestProtoService checks the methods of the Factomd service match the rpcs of factomd.proto
+func TestProtoService(t *testing.T) {
+	rpcs := protoRPC.FindAllStringSubmatch(readProto(t), -1)
+	server := reflect.TypeOf((*FactomdServer)(nil)).Elem()
+	if len(rpcs) != server.NumMethod() {
+		t.Errorf("factomd.proto has %d rpcs and FactomdServer %d methods", len(rpcs), server.NumMethod())
+	}
+	for _, rpc := range rpcs {
+		name, request, stream, response := rpc[1], rpc[2], rpc[3] != "", rpc[4]
+		method, ok := server.MethodByName(name)
+		if !ok {
+			t.Errorf("FactomdServer has no %s", name)
+			continue
+		}
+		var in, out reflect.Type
+		if stream {
+			// StreamX(*Request, Factomd_StreamXServer) error, the stream sending the responses
+			if method.Type.NumIn() != 2 || method.Type.In(1).Kind() != reflect.Interface {
+				t.Errorf("%s is not a stream", name)
+				continue
+			}
+			in = method.Type.In(0)
+			send, ok := method.Type.In(1).MethodByName("Send")
+			if !ok {
+				t.Errorf("The stream of %s has no Send", name)
+				continue
+			}
+			out = send.Type.In(0)
+		} else {
+			// X(context.Context, *Request) (*Response, error)
+			if method.Type.NumIn() != 2 || method.Type.NumOut() != 2 || method.Type.In(1).Kind() == reflect.Interface {
+				t.Errorf("%s is not a unary call", name)
+				continue
+			}
+			in = method.Type.In(1)
+			out = method.Type.Out(0)
+		}
+		if typ, ok := protoTypes[request]; !ok || in != reflect.PtrTo(typ) {
+			t.Errorf("%s takes a %s, expected a %s", name, in, request)
+		}
+		if typ, ok := protoTypes[response]; !ok || out != reflect.PtrTo(typ) {
+			t.Errorf("%s returns a %s, expected a %s", name, out, response)
+		}
+	}
+}
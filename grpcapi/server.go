@@ -0,0 +1,390 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/FactomProject/factomd/events/eventservices"
+	"github.com/FactomProject/factomd/wsapi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// An optional gRPC API next to the JSON-RPC one.  Blocks and entries are returned as the protobuf messages
+// of the live feed, built by the mappers of events/eventservices.  The submit calls and chain head and
+// balance queries run the V2 handlers, so they behave exactly as the JSON-RPC methods.  Calls are checked
+// against the rpc user and password or the API keys, sent in the "authorization" metadata, and count
+// against the rate and concurrency limits of the keys as JSON-RPC requests do.
+
+var packageLogger = log.WithFields(log.Fields{"package": "grpcapi"})
+
+// How often a stream that follows the chain looks for new blocks
+var pollInterval = time.Second
+
+// The V2 method each call is run as, for the permissions of the API keys
+var v2Methods = map[string]string{
+	"GetDirectoryBlockByHeight":   "dblock-by-height",
+	"GetAdminBlockByHeight":       "ablock-by-height",
+	"GetFactoidBlockByHeight":     "fblock-by-height",
+	"GetEntryCreditBlockByHeight": "ecblock-by-height",
+	"GetEntry":                    "entry",
+	"GetChainHead":                "chain-head",
+	"GetFactoidBalance":           "factoid-balance",
+	"GetEntryCreditBalance":       "entry-credit-balance",
+	"SubmitTransaction":           "factoid-submit",
+	"CommitChain":                 "commit-chain",
+	"CommitEntry":                 "commit-entry",
+	"RevealEntry":                 "reveal-entry",
+	"StreamDirectoryBlocks":       "dblock-by-height",
+	"StreamEntries":               "entry",
+}
+
+// Start serves the gRPC API on the port, unless it is 0
+func Start(state interfaces.IState, port int) {
+	if port == 0 {
+		return
+	}
+
+	var opts []grpc.ServerOption
+	if tlsEnabled, keyFile, certFile := state.GetTlsInfo(); tlsEnabled {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			packageLogger.Errorf("could not start encrypted gRPC server: %v", err)
+			return
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	address := fmt.Sprintf(":%d", port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		packageLogger.Errorf("could not listen for gRPC at %s: %v", address, err)
+		return
+	}
+
+	packageLogger.Infof("Starting gRPC server at: %s", address)
+	server := NewGRPCServer(state, opts...)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			packageLogger.Errorf("gRPC server stopped: %v", err)
+		}
+	}()
+}
+
+// NewGRPCServer returns a gRPC server with the Factomd service registered, and the checks of the API keys
+func NewGRPCServer(state interfaces.IState, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			release, err := authorize(state, ctx, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			release, err := authorize(state, stream.Context(), info.FullMethod)
+			if err != nil {
+				return err
+			}
+			defer release()
+			return handler(srv, stream)
+		}),
+	)
+	server := grpc.NewServer(opts...)
+	RegisterFactomdServer(server, &Server{State: state})
+	return server
+}
+
+// authorize checks a call against the API keys, as the V2 method it is run as.  The returned release ends
+// the call, for the concurrency limit of the key.
+func authorize(state interfaces.IState, ctx context.Context, fullMethod string) (release func(), err error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	method := v2Methods[fullMethod[strings.LastIndex(fullMethod, "/")+1:]]
+	release, jsonError := wsapi.AuthorizeCall(state, authorization, method)
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	return release, nil
+}
+
+// statusError turns the error of a V2 handler into a gRPC status
+func statusError(jsonError *primitives.JSONError) error {
+	code := codes.Internal
+	switch jsonError.Code {
+	case -32600, -32602:
+		code = codes.InvalidArgument
+	case -32008, -32009:
+		code = codes.NotFound
+	case -32011:
+		code = codes.AlreadyExists
+	case -32012:
+		code = codes.FailedPrecondition
+	case -32013:
+		code = codes.PermissionDenied
+	case -32014:
+		code = codes.ResourceExhausted
+	case -32015:
+		code = codes.Unauthenticated
+	}
+	if jsonError.Data != nil {
+		return status.Errorf(code, "%s: %v", jsonError.Message, jsonError.Data)
+	}
+	return status.Error(code, jsonError.Message)
+}
+
+// Server implements the Factomd service
+type Server struct {
+	State interfaces.IState
+}
+
+var _ FactomdServer = (*Server)(nil)
+
+func (s *Server) GetDirectoryBlockByHeight(ctx context.Context, req *HeightRequest) (*eventmessages.DirectoryBlock, error) {
+	block, err := s.State.GetDB().FetchDBlockByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	return eventservices.MapDirectoryBlock(block), nil
+}
+
+func (s *Server) GetAdminBlockByHeight(ctx context.Context, req *HeightRequest) (*eventmessages.AdminBlock, error) {
+	block, err := s.State.GetDB().FetchABlockByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	return eventservices.MapAdminBlock(block), nil
+}
+
+func (s *Server) GetFactoidBlockByHeight(ctx context.Context, req *HeightRequest) (*eventmessages.FactoidBlock, error) {
+	block, err := s.State.GetDB().FetchFBlockByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	return eventservices.MapFactoidBlock(block), nil
+}
+
+func (s *Server) GetEntryCreditBlockByHeight(ctx context.Context, req *HeightRequest) (*eventmessages.EntryCreditBlock, error) {
+	block, err := s.State.GetDB().FetchECBlockByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalDatabaseError())
+	}
+	if block == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	return eventservices.MapEntryCreditBlock(block), nil
+}
+
+func (s *Server) GetEntry(ctx context.Context, req *HashRequest) (*eventmessages.EntryBlockEntry, error) {
+	hash, err := primitives.NewShaHash(req.Hash)
+	if err != nil {
+		return nil, statusError(wsapi.NewInvalidHashError())
+	}
+	entry, err := s.State.FetchEntryByHash(hash)
+	if err != nil {
+		return nil, statusError(wsapi.NewInternalError())
+	}
+	if entry == nil {
+		if chainID := wsapi.EntryChainNotKept(s.State, hash); chainID != nil {
+			return nil, statusError(wsapi.NewChainNotKeptError(chainID.String()))
+		}
+		return nil, statusError(wsapi.NewEntryNotFoundError())
+	}
+	return eventservices.MapEntryBlockEntry(entry, true), nil
+}
+
+func (s *Server) GetChainHead(ctx context.Context, req *ChainHeadRequest) (*ChainHeadResponse, error) {
+	result, jsonError := wsapi.HandleV2ChainHead(s.State, wsapi.ChainIDRequest{ChainID: hex.EncodeToString(req.ChainID)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	head := result.(*wsapi.ChainHeadResponse)
+	resp := &ChainHeadResponse{ChainInProcessList: head.ChainInProcessList}
+	resp.KeyMerkleRoot, _ = hex.DecodeString(head.ChainHead)
+	return resp, nil
+}
+
+func (s *Server) GetFactoidBalance(ctx context.Context, req *BalanceRequest) (*BalanceResponse, error) {
+	result, jsonError := wsapi.HandleV2FactoidBalance(s.State, wsapi.AddressRequest{Address: req.Address})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	return &BalanceResponse{Balance: result.(*wsapi.FactoidBalanceResponse).Balance}, nil
+}
+
+func (s *Server) GetEntryCreditBalance(ctx context.Context, req *BalanceRequest) (*BalanceResponse, error) {
+	result, jsonError := wsapi.HandleV2EntryCreditBalance(s.State, wsapi.AddressRequest{Address: req.Address})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	return &BalanceResponse{Balance: result.(*wsapi.EntryCreditBalanceResponse).Balance}, nil
+}
+
+func (s *Server) SubmitTransaction(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	result, jsonError := wsapi.HandleV2FactoidSubmit(s.State, wsapi.TransactionRequest{Transaction: hex.EncodeToString(req.Data)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := result.(*wsapi.FactoidSubmitResponse)
+	return &SubmitResponse{Message: r.Message, TxID: hexToBytes(r.TxID)}, nil
+}
+
+func (s *Server) CommitChain(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	result, jsonError := wsapi.HandleV2CommitChain(s.State, wsapi.MessageRequest{Message: hex.EncodeToString(req.Data)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := result.(*wsapi.CommitChainResponse)
+	return &SubmitResponse{Message: r.Message, TxID: hexToBytes(r.TxID), EntryHash: hexToBytes(r.EntryHash),
+		ChainID: hexToBytes(r.ChainIDHash)}, nil
+}
+
+func (s *Server) CommitEntry(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	result, jsonError := wsapi.HandleV2CommitEntry(s.State, wsapi.MessageRequest{Message: hex.EncodeToString(req.Data)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := result.(*wsapi.CommitEntryResponse)
+	return &SubmitResponse{Message: r.Message, TxID: hexToBytes(r.TxID), EntryHash: hexToBytes(r.EntryHash)}, nil
+}
+
+func (s *Server) RevealEntry(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	result, jsonError := wsapi.HandleV2RevealEntry(s.State, wsapi.EntryRequest{Entry: hex.EncodeToString(req.Data)})
+	if jsonError != nil {
+		return nil, statusError(jsonError)
+	}
+	r := result.(*wsapi.RevealEntryResponse)
+	return &SubmitResponse{Message: r.Message, EntryHash: hexToBytes(r.EntryHash), ChainID: hexToBytes(r.ChainID)}, nil
+}
+
+func hexToBytes(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+func (s *Server) StreamDirectoryBlocks(req *StreamRequest, stream Factomd_StreamDirectoryBlocksServer) error {
+	return s.follow(stream.Context(), req.StartHeight, req.Follow, req.IncludeEntryContent, stream.Send)
+}
+
+func (s *Server) StreamEntries(req *StreamRequest, stream Factomd_StreamEntriesServer) error {
+	return s.follow(stream.Context(), req.StartHeight, req.Follow, true, func(commit *eventmessages.DirectoryBlockCommit) error {
+		for _, entry := range commit.EntryBlockEntries {
+			if len(req.ChainID) > 0 && !bytes.Equal(entry.ChainID, req.ChainID) {
+				continue
+			}
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// follow calls send with the block set of every saved height from the start height on.  Returns once the
+// saved blocks are sent, or when following the chain, once the client goes away.
+func (s *Server) follow(ctx context.Context, height uint32, follow bool, includeContent bool,
+	send func(*eventmessages.DirectoryBlockCommit) error) error {
+	for {
+		if ctx.Err() != nil {
+			return status.Error(codes.Canceled, ctx.Err().Error())
+		}
+		if height > s.State.GetHighestSavedBlk() {
+			if !follow {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		commit, err := s.directoryBlockCommit(height, includeContent)
+		if err != nil {
+			return err
+		}
+		if err := send(commit); err != nil {
+			return err
+		}
+		height++
+	}
+}
+
+// directoryBlockCommit reads the blocks saved at a height into the message the live feed sends for them
+func (s *Server) directoryBlockCommit(height uint32, includeContent bool) (*eventmessages.DirectoryBlockCommit, error) {
+	dbase := s.State.GetDB()
+	d, err := dbase.FetchDBlockByHeight(height)
+	if err != nil || d == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	a, err := dbase.FetchABlockByHeight(height)
+	if err != nil || a == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	f, err := dbase.FetchFBlockByHeight(height)
+	if err != nil || f == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+	ec, err := dbase.FetchECBlockByHeight(height)
+	if err != nil || ec == nil {
+		return nil, statusError(wsapi.NewBlockNotFoundError())
+	}
+
+	var eblocks []interfaces.IEntryBlock
+	var entries []interfaces.IEBEntry
+	for _, eb := range d.GetEBlockDBEntries() {
+		eblock, _ := dbase.FetchEBlock(eb.GetKeyMR())
+		if eblock == nil {
+			continue
+		}
+		eblocks = append(eblocks, eblock)
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			entry, _ := dbase.FetchEntry(hash)
+			if entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return &eventmessages.DirectoryBlockCommit{
+		DirectoryBlock:    eventservices.MapDirectoryBlock(d),
+		AdminBlock:        eventservices.MapAdminBlock(a),
+		FactoidBlock:      eventservices.MapFactoidBlock(f),
+		EntryCreditBlock:  eventservices.MapEntryCreditBlock(ec),
+		EntryBlocks:       eventservices.MapEntryBlocks(eblocks),
+		EntryBlockEntries: eventservices.MapEntryBlockEntries(entries, includeContent),
+	}, nil
+}
@@ -0,0 +1,189 @@
+package grpcapi_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/grpcapi"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/FactomProject/factomd/util"
+	"github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerBlocksByHeight(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	server := &Server{State: state}
+	ctx := context.Background()
+
+	dblock, err := server.GetDirectoryBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, dblock.Header.BlockHeight)
+
+	fblock, err := server.GetFactoidBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, fblock.BlockHeight)
+
+	_, err = server.GetAdminBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Nil(t, err)
+	_, err = server.GetEntryCreditBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Nil(t, err)
+
+	_, err = server.GetDirectoryBlockByHeight(ctx, &HeightRequest{Height: 100000})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerEntryAndBalances(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	server := &Server{State: state}
+	ctx := context.Background()
+
+	dblock, err := state.GetDB().FetchDBlockByHeight(1)
+	assert.Nil(t, err)
+	eblock, err := state.GetDB().FetchEBlock(dblock.GetEBlockDBEntries()[0].GetKeyMR())
+	assert.Nil(t, err)
+	hash := eblock.GetEntryHashes()[0]
+
+	entry, err := server.GetEntry(ctx, &HashRequest{Hash: hash.Bytes()})
+	assert.Nil(t, err)
+	assert.Equal(t, hash.Bytes(), entry.Hash)
+
+	_, err = server.GetEntry(ctx, &HashRequest{Hash: []byte{1, 2, 3}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	head, err := server.GetChainHead(ctx, &ChainHeadRequest{ChainID: eblock.GetChainID().Bytes()})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, head.KeyMerkleRoot)
+
+	address := primitives.ConvertFctAddressToUserStr(testHelper.NewFactoidAddress(9))
+	balance, err := server.GetFactoidBalance(ctx, &BalanceRequest{Address: address})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, balance.Balance)
+
+	_, err = server.GetEntryCreditBalance(ctx, &BalanceRequest{Address: "not an address"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.CommitEntry(ctx, &SubmitRequest{Data: []byte{0}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServerEntryOfChainNotKept(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	server := &Server{State: state}
+	ctx := context.Background()
+
+	if err := state.SetKeepEntryChains(primitives.Sha([]byte("kept")).String()); err != nil {
+		t.Fatal(err)
+	}
+	var hash interfaces.IHash
+	dblock, err := state.GetDB().FetchDBlockByHeight(1)
+	assert.Nil(t, err)
+	for _, eb := range dblock.GetEBlockDBEntries() {
+		if hash == nil && !state.IsChainKept(eb.GetChainID()) {
+			eblock, err := state.GetDB().FetchEBlock(eb.GetKeyMR())
+			assert.Nil(t, err)
+			hash = eblock.GetEntryHashes()[0]
+		}
+	}
+	if hash == nil {
+		t.Fatal("No entry of a chain that isn't kept")
+	}
+	assert.Nil(t, state.GetDB().DeleteEntry(hash))
+
+	// As the entry V2 method, a pruned entry is of a chain not kept rather than not found
+	_, err = server.GetEntry(ctx, &HashRequest{Hash: hash.Bytes()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerStreamsAndAPIKeys(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := NewGRPCServer(state)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := NewFactomdClient(conn)
+	ctx := context.Background()
+
+	stream, err := client.StreamDirectoryBlocks(ctx, &StreamRequest{StartHeight: 0})
+	assert.Nil(t, err)
+	count := 0
+	for {
+		commit, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		assert.EqualValues(t, count, commit.DirectoryBlock.Header.BlockHeight)
+		count++
+	}
+	assert.EqualValues(t, state.GetHighestSavedBlk()+1, count)
+
+	key, err := wsapi.NewAPIKey("reader", &util.APIKeyConfig{Key: "reader-key", Methods: "read"})
+	assert.Nil(t, err)
+	wsapi.SetAPIKeys([]*wsapi.APIKey{key})
+	defer wsapi.SetAPIKeys(nil)
+
+	_, err = client.GetDirectoryBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	reader := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer reader-key")
+	_, err = client.GetDirectoryBlockByHeight(reader, &HeightRequest{Height: 1})
+	assert.Nil(t, err)
+	_, err = client.SubmitTransaction(reader, &SubmitRequest{Data: []byte{0}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServerConcurrencyLimit(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := NewGRPCServer(state)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := NewFactomdClient(conn)
+
+	key, err := wsapi.NewAPIKey("single", &util.APIKeyConfig{Key: "single-key", Methods: "read", MaxConcurrent: 1})
+	assert.Nil(t, err)
+	wsapi.SetAPIKeys([]*wsapi.APIKey{key})
+	defer wsapi.SetAPIKeys(nil)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer single-key")
+
+	// A stream following the chain takes the only call the key may make at once until it is closed
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.StreamDirectoryBlocks(streamCtx, &StreamRequest{Follow: true})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+
+	_, err = client.GetDirectoryBlockByHeight(ctx, &HeightRequest{Height: 1})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	cancel()
+	for i := 0; i < 50; i++ {
+		if _, err = client.GetDirectoryBlockByHeight(ctx, &HeightRequest{Height: 1}); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Nil(t, err, "the closed stream still counts against the limit")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/util/atomic"
+	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -83,6 +84,40 @@ func SplitUpDebugLogRegEx(DebugLogRegEx string) (string, string) {
 	return dirlocation, regex
 }
 
+// stamp is the state a line was logged from, the zero stamp for lines not logged through StateLog*
+type stamp struct {
+	node     string
+	logName  string
+	dbheight int
+	minute   int
+}
+
+// fields returns the structured fields common to every line of a log
+func (st stamp) fields(name string) logrus.Fields {
+	fields := logrus.Fields{"subsystem": name, "seq": sequence}
+	if st.node != "" {
+		fields["subsystem"] = st.logName
+		fields["node-name"] = st.node
+		fields["dbheight"] = st.dbheight
+		fields["minute"] = st.minute
+	}
+	return fields
+}
+
+// openSinks returns the file to write a log to, nil when the file sink is off, and false if the log
+// isn't selected or no sink wants it.  Assumes traceMutex is locked already
+func openSinks(name string) (*os.File, bool) {
+	checkForChangesInDebugRegex()
+	if !checkFileName(globals.Params.DebugLogLocation + strings.ToLower(name)) {
+		return nil, false
+	}
+	if !fileSink {
+		return nil, structured != nil
+	}
+	f := getTraceFile(name)
+	return f, f != nil
+}
+
 // assumes traceMutex is locked already
 func getTraceFile(name string) (f *os.File) {
 	checkForChangesInDebugRegex()
@@ -147,15 +182,15 @@ func getmsg(hash [32]byte) interfaces.IMsg {
 func LogMessage(name string, note string, msg interfaces.IMsg) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
-	logMessage(name, note, msg)
+	logMessage(name, note, msg, stamp{})
 }
 
 var logWhere bool = false // log GoID() of the caller.
 
 // Assumes called managed the locks so we can recurse for multi part messages
-func logMessage(name string, note string, msg interfaces.IMsg) {
-	myfile := getTraceFile(name)
-	if myfile == nil {
+func logMessage(name string, note string, msg interfaces.IMsg, st stamp) {
+	myfile, ok := openSinks(name)
+	if !ok {
 		return
 	}
 
@@ -176,14 +211,17 @@ func logMessage(name string, note string, msg interfaces.IMsg) {
 	msgString := "-nil-"
 	var embeddedMsg interfaces.IMsg
 
+	fields := st.fields(name)
 	if msg != nil {
 		t = msg.Type()
 		msgString = msg.String()
+		fields["vm"] = msg.GetVMIndex()
 
 		// work around message that don't have hashes yet ...
 		mh := msg.GetMsgHash()
 		if mh != nil && !reflect.ValueOf(mh).IsNil() {
 			mhash = mh.String()[:6]
+			fields["msghash"] = mh.String()
 		}
 		h := msg.GetHash()
 		if h != nil && !reflect.ValueOf(h).IsNil() {
@@ -218,7 +256,9 @@ func logMessage(name string, note string, msg interfaces.IMsg) {
 			}
 		}
 		messageType = constants.MessageName(byte(t))
+		fields["messagetype"] = messageType
 	}
+	emit(fields, note+" "+msgString+embeddedHash)
 
 	// handle multi-line printf's
 	lines := strings.Split(msgString, "\n")
@@ -237,12 +277,13 @@ func logMessage(name string, note string, msg interfaces.IMsg) {
 			s = fmt.Sprintf("%9d %02d:%02d:%02d.%03d %-50s M-%v|R-%v|H-%v|%p %30s:%v\n", sequence, now.Hour()%24, now.Minute()%60, now.Second()%60, (now.Nanosecond()/1e6)%1000,
 				note, mhash, rhash, hash, msg, "continue:", text)
 		}
-		s = addNodeNames(s)
-		myfile.WriteString(s)
+		if myfile != nil {
+			myfile.WriteString(addNodeNames(s))
+		}
 	}
 
 	if embeddedMsg != nil {
-		logMessage(name, note+" EmbeddedMsg:", embeddedMsg)
+		logMessage(name, note+" EmbeddedMsg:", embeddedMsg, st)
 	}
 }
 
@@ -293,8 +334,13 @@ func addNodeNames(s string) (rval string) {
 func LogPrintf(name string, format string, more ...interface{}) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
-	myfile := getTraceFile(name)
-	if myfile == nil {
+	logPrintf(name, stamp{}, format, more...)
+}
+
+// assumes traceMutex is locked already
+func logPrintf(name string, st stamp, format string, more ...interface{}) {
+	myfile, ok := openSinks(name)
+	if !ok {
 		return
 	}
 
@@ -305,8 +351,13 @@ func LogPrintf(name string, format string, more ...interface{}) {
 	}
 
 	sequence++
+	text := fmt.Sprintf(format, more...)
+	emit(st.fields(name), text)
+	if myfile == nil {
+		return
+	}
 	// handle multi-line printf's
-	lines := strings.Split(fmt.Sprintf(format, more...), "\n")
+	lines := strings.Split(text, "\n")
 	now := time.Now().Local()
 	for i, text := range lines {
 		var s string
@@ -325,28 +376,35 @@ func LogPrintf(name string, format string, more ...interface{}) {
 func LogParcel(name string, note string, msg string) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
-	myfile := getTraceFile(name)
-	if myfile == nil {
+	myfile, ok := openSinks(name)
+	if !ok {
 		return
 	}
 	sequence++
 	seq := sequence
 
-	myfile.WriteString(fmt.Sprintf("%5v %26s %s\n", seq, note, msg))
+	emit(stamp{}.fields(name), note+" "+msg)
+	if myfile != nil {
+		myfile.WriteString(fmt.Sprintf("%5v %26s %s\n", seq, note, msg))
+	}
 }
 
 // Log a message with a state timestamp
 func StateLogMessage(FactomNodeName string, DBHeight int, CurrentMinute int, logName string, comment string, msg interfaces.IMsg) {
 	logFileName := FactomNodeName + "_" + logName + ".txt"
 	t := fmt.Sprintf("%7d-:-%d ", DBHeight, CurrentMinute)
-	LogMessage(logFileName, t+comment, msg)
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	logMessage(logFileName, t+comment, msg, stamp{FactomNodeName, logName, DBHeight, CurrentMinute})
 }
 
 // Log a printf with a state timestamp
 func StateLogPrintf(FactomNodeName string, DBHeight int, CurrentMinute int, logName string, format string, more ...interface{}) {
 	logFileName := FactomNodeName + "_" + logName + ".txt"
 	t := fmt.Sprintf("%7d-:-%d ", DBHeight, CurrentMinute)
-	LogPrintf(logFileName, t+format, more...)
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	logPrintf(logFileName, stamp{FactomNodeName, logName, DBHeight, CurrentMinute}, t+format, more...)
 }
 
 // unused -- of.File is written by direct calls to write and not buffered and the os closes the files on exit.
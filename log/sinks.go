@@ -0,0 +1,254 @@
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/logrustash"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+The log lines picked by the subsystem regex (-debuglog) have always gone to one text file per log name.
+They can also go out as JSON lines carrying the node name, height, minute and message fields, to
+stdout and to Logstash, through the same logrus pipeline as the packageLogger loggers.  The lines of the
+packageLogger loggers get the node name, height, minute and vm of the node, see SetNode.  The level of the
+logrus loggers, the subsystem regex and the sinks can all be changed while running, see Configure.
+*/
+
+// Sinks the log lines can go to
+const (
+	SinkFile     = "file"     // One text file per log name, what engine/debug/*.sh work from
+	SinkJSON     = "json"     // JSON lines on stdout
+	SinkLogstash = "logstash" // JSON lines sent to Logstash at LogstashURL
+)
+
+// LevelNone turns the logrus loggers off
+const LevelNone = "none"
+
+// Settings of the logging.  Empty fields are left as they are by Configure.
+type Settings struct {
+	Level       string   `json:"level"`       // Level of the logrus loggers, or none
+	Subsystems  string   `json:"subsystems"`  // Regex of the log names to write, "off" for none
+	Sinks       []string `json:"sinks"`       // Where the subsystem lines go
+	LogstashURL string   `json:"logstashurl"` // host:port of Logstash, for the logstash sink
+}
+
+var (
+	settings   = Settings{Level: logrus.InfoLevel.String(), Sinks: []string{SinkFile}}
+	fileSink   = true
+	structured *logrus.Logger // Gets the subsystem lines when the json or logstash sink is on
+	hooked     bool           // The standard logger has forwardHook
+
+	hookMutex    sync.Mutex  // Guards the Logstash hook, which is fired with traceMutex held
+	logstashHook logrus.Hook // Connection to Logstash, kept once made
+	logstashOn   bool
+
+	nodeMutex  sync.Mutex
+	nodeFields func() logrus.Fields // Fields of the node the logrus lines are stamped with, nil for none
+	nodeHooked bool                 // The standard logger has nodeHook
+)
+
+// nodeHook adds the fields of the node to the lines of the standard logger that don't have them
+type nodeHook struct{}
+
+func (nodeHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (nodeHook) Fire(entry *logrus.Entry) error {
+	nodeMutex.Lock()
+	fields := nodeFields
+	nodeMutex.Unlock()
+	if fields == nil {
+		return nil
+	}
+	// The entry's map can be shared with the logger it came from, so the fields go into a copy
+	data := make(logrus.Fields, len(entry.Data)+4)
+	for k, v := range fields() {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	entry.Data = data
+	return nil
+}
+
+// SetNode stamps the lines of the logrus loggers, such as the packageLogger ones, with the fields of a node
+// (node-name, dbheight, minute and vm) where they don't carry their own.  It is only set for a node running
+// on its own, as the lines of a simulation can come from any of its nodes.
+func SetNode(fields func() logrus.Fields) {
+	nodeMutex.Lock()
+	defer nodeMutex.Unlock()
+	nodeFields = fields
+	if !nodeHooked {
+		logrus.AddHook(nodeHook{})
+		nodeHooked = true
+	}
+}
+
+// forwardHook sends the lines of the standard logger to Logstash while the logstash sink is on
+type forwardHook struct{}
+
+func (forwardHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (forwardHook) Fire(entry *logrus.Entry) error {
+	hookMutex.Lock()
+	hook, on := logstashHook, logstashOn
+	hookMutex.Unlock()
+	if !on || hook == nil {
+		return nil
+	}
+	return hook.Fire(entry)
+}
+
+// CurrentSettings returns the logging settings in use
+func CurrentSettings() Settings {
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	s := settings
+	s.Sinks = append([]string{}, settings.Sinks...)
+	_, s.Subsystems = SplitUpDebugLogRegEx(globals.Params.DebugLogRegEx)
+	if s.Subsystems == "" {
+		s.Subsystems = "off"
+	}
+	return s
+}
+
+// Configure changes the logging settings.  Nothing is changed if any of the settings are bad.
+func Configure(s Settings) error {
+	var level logrus.Level
+	if s.Level != "" && s.Level != LevelNone {
+		var err error
+		if level, err = logrus.ParseLevel(s.Level); err != nil {
+			return err
+		}
+	}
+	if s.Subsystems != "" && s.Subsystems != "off" {
+		if _, err := regexp.Compile("(?i)" + s.Subsystems); err != nil {
+			return fmt.Errorf("Bad subsystem regex: %v", err)
+		}
+	}
+	for _, sink := range s.Sinks {
+		switch sink {
+		case SinkFile, SinkJSON, SinkLogstash:
+		default:
+			return fmt.Errorf("Unknown log sink %q, expected %s, %s or %s", sink, SinkFile, SinkJSON, SinkLogstash)
+		}
+	}
+
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+
+	url := settings.LogstashURL
+	if s.LogstashURL != "" {
+		url = s.LogstashURL
+	}
+	sinks := settings.Sinks
+	if s.Sinks != nil {
+		sinks = s.Sinks
+	}
+	hookMutex.Lock()
+	current := logstashHook
+	hookMutex.Unlock()
+	if hasSink(sinks, SinkLogstash) && (current == nil || url != settings.LogstashURL) {
+		hook, err := logrustash.NewAsyncHook("tcp", url, "factomdLogs")
+		if err != nil {
+			return err
+		}
+		hook.ReconnectBaseDelay = time.Second // Wait for one second before first reconnect.
+		hook.ReconnectDelayMultiplier = 2
+		hook.MaxReconnectRetries = 10
+		hookMutex.Lock()
+		logstashHook = hook
+		hookMutex.Unlock()
+	}
+	settings.LogstashURL = url
+
+	switch s.Level {
+	case "":
+	case LevelNone:
+		logrus.SetOutput(ioutil.Discard)
+		settings.Level = LevelNone
+	default:
+		logrus.SetOutput(os.Stdout)
+		logrus.SetLevel(level)
+		settings.Level = level.String()
+	}
+
+	switch s.Subsystems {
+	case "":
+	case "off":
+		globals.Params.DebugLogRegEx = ""
+	default:
+		// Keep writing the files where -debuglog said to
+		globals.Params.DebugLogRegEx = globals.Params.DebugLogLocation + s.Subsystems
+	}
+
+	settings.Sinks = append([]string{}, sinks...)
+	setSinks()
+	return nil
+}
+
+// setSinks puts the sinks of the settings in place, assumes traceMutex is locked already
+func setSinks() {
+	fileSink = hasSink(settings.Sinks, SinkFile)
+	logstash := hasSink(settings.Sinks, SinkLogstash)
+	hookMutex.Lock()
+	logstashOn = logstash
+	hookMutex.Unlock()
+	if logstash && !hooked {
+		logrus.AddHook(forwardHook{})
+		hooked = true
+	}
+
+	json := hasSink(settings.Sinks, SinkJSON)
+	if !json && !logstash {
+		structured = nil
+		return
+	}
+	structured = logrus.New()
+	structured.Formatter = &logrus.JSONFormatter{}
+	structured.Out = ioutil.Discard
+	if json {
+		structured.Out = os.Stdout
+	}
+	structured.Hooks.Add(forwardHook{})
+}
+
+func hasSink(sinks []string, sink string) bool {
+	for _, s := range sinks {
+		if strings.TrimSpace(s) == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSinks splits a comma separated list of sinks
+func ParseSinks(list string) []string {
+	var sinks []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			sinks = append(sinks, s)
+		}
+	}
+	return sinks
+}
+
+// emit sends a subsystem line to the json and logstash sinks, assumes traceMutex is locked already
+func emit(fields logrus.Fields, text string) {
+	if structured == nil {
+		return
+	}
+	structured.WithFields(fields).Info(text)
+}
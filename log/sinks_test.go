@@ -0,0 +1,101 @@
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/log"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureRejectsBadSettings(t *testing.T) {
+	before := log.CurrentSettings()
+
+	assert.Error(t, log.Configure(log.Settings{Level: "loud"}))
+	assert.Error(t, log.Configure(log.Settings{Subsystems: "(unclosed"}))
+	assert.Error(t, log.Configure(log.Settings{Level: "debug", Sinks: []string{"file", "syslog"}}))
+
+	assert.Equal(t, before, log.CurrentSettings(), "a bad setting must not change any of the others")
+}
+
+func TestJSONSink(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = log.Configure(log.Settings{Subsystems: "unittest", Sinks: []string{log.SinkJSON}})
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Configure(log.Settings{Subsystems: "off", Sinks: []string{log.SinkFile}})
+
+	settings := log.CurrentSettings()
+	assert.Equal(t, []string{log.SinkJSON}, settings.Sinks)
+	assert.Equal(t, "unittest", settings.Subsystems)
+
+	log.StateLogPrintf("fnode0", 12, 3, "unittest", "hello %s", "world")
+	log.LogPrintf("notselected", "dropped")
+	w.Close()
+
+	scanner := bufio.NewScanner(r)
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("not a JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "fnode0", lines[0]["node-name"])
+		assert.Equal(t, "unittest", lines[0]["subsystem"])
+		assert.EqualValues(t, 12, lines[0]["dbheight"])
+		assert.EqualValues(t, 3, lines[0]["minute"])
+		assert.Contains(t, lines[0]["msg"], "hello world")
+	}
+}
+
+func TestNodeFields(t *testing.T) {
+	var out bytes.Buffer
+	logrus.SetOutput(&out)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	defer logrus.SetOutput(os.Stdout)
+	defer logrus.SetFormatter(&logrus.TextFormatter{})
+	dbheight := 12
+	log.SetNode(func() logrus.Fields {
+		return logrus.Fields{"node-name": "fnode0", "dbheight": dbheight, "minute": 3, "vm": 1}
+	})
+	defer log.SetNode(nil)
+
+	packageLogger := logrus.WithFields(logrus.Fields{"package": "unittest"})
+	packageLogger.Info("stamped")
+	packageLogger.WithField("node-name", "fnode1").Info("own node")
+	dbheight++
+	packageLogger.Info("stamped again")
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("not a JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "fnode0", lines[0]["node-name"])
+		assert.Equal(t, "unittest", lines[0]["package"])
+		assert.EqualValues(t, 12, lines[0]["dbheight"])
+		assert.EqualValues(t, 3, lines[0]["minute"])
+		assert.EqualValues(t, 1, lines[0]["vm"])
+		assert.Equal(t, "fnode1", lines[1]["node-name"], "a line's own node is kept")
+		assert.EqualValues(t, 13, lines[2]["dbheight"], "the logger's fields must not keep the stamp")
+	}
+}
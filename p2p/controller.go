@@ -19,6 +19,7 @@ import (
 	"unicode"
 
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util/clock"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -58,6 +59,7 @@ type Controller struct {
 	lastPeerRequest      time.Time        // Last time we asked peers about the peers they know about.
 	specialPeers         map[string]*Peer // special peers (from config file and from the command line params) by peer address
 	partsAssembler       *PartsAssembler  // a data structure that assembles full messages from received message parts
+	clock                clock.Clock      // clock of the controller's timers
 
 	// logging
 	logger *log.Entry
@@ -76,6 +78,7 @@ type ControllerInit struct {
 	ConnectionMetricsChannel chan interface{} // Channel on which we put the connection metrics map, periodically.
 	LogPath                  string           // Path for logs
 	LogLevel                 string           // Logging level
+	Clock                    clock.Clock      // Clock of the peer management timers, nil for the wall clock
 }
 
 // CommandDialPeer is used to instruct the Controller to dial a peer address
@@ -178,13 +181,17 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 	NetworkListenPort = ci.Port
 	// Set this to the past so we will do peer management almost right away after starting up.
 	c.lastPeerManagement = time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
-	c.lastPeerRequest = time.Now()
+	c.clock = ci.Clock
+	if c.clock == nil {
+		c.clock = clock.Real
+	}
+	c.lastPeerRequest = c.clock.Now()
 	CurrentNetwork = ci.Network
 	OnlySpecialPeers = ci.Exclusive || ci.ExclusiveIn
 	AllowUnknownIncomingPeers = !ci.ExclusiveIn
 	c.initSpecialPeers(ci)
-	c.lastDiscoveryRequest = time.Now() // Discovery does its own on startup.
-	c.lastConnectionMetricsUpdate = time.Now()
+	c.lastDiscoveryRequest = c.clock.Now() // Discovery does its own on startup.
+	c.lastConnectionMetricsUpdate = c.clock.Now()
 	c.partsAssembler = new(PartsAssembler).Init()
 	discovery := new(Discovery).Init(ci.PeersFile, ci.SeedURL)
 	c.discovery = *discovery
@@ -194,7 +201,7 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 // StartNetwork configures the network, starts the runloop
 func (c *Controller) StartNetwork() {
 	c.logger.Info("Starting network")
-	c.lastStatusReport = time.Now()
+	c.lastStatusReport = c.clock.Now()
 	// start listening on port given
 	c.listen()
 	// Dial all the gathered special peers
@@ -396,7 +403,7 @@ func (c *Controller) parseSpecialPeers(peersString string, peerType uint8) []*Pe
 func (c *Controller) runloop() {
 	// In long running processes it seems the runloop is exiting.
 	c.logger.Debugf("Controller.runloop() @@@@@@@@@@ starting up in %d seconds", 2)
-	time.Sleep(time.Second * time.Duration(2)) // Wait a few seconds to let the system come up.
+	c.clock.Sleep(time.Second * time.Duration(2)) // Wait a few seconds to let the system come up.
 
 	for c.keepRunning { // Run until we get the exit command
 
@@ -409,7 +416,7 @@ func (c *Controller) runloop() {
 			case command := <-c.commandChannel:
 				c.handleCommand(command)
 			default:
-				time.Sleep(time.Millisecond * 20)
+				c.clock.Sleep(time.Millisecond * 20)
 				break commandloop
 			}
 		}
@@ -577,12 +584,12 @@ func (c *Controller) applicationPeerUpdate(qualityDelta int32, peerHash string)
 }
 
 func (c *Controller) managePeers() {
-	managementDuration := time.Since(c.lastPeerManagement)
+	managementDuration := c.clock.Since(c.lastPeerManagement)
 	if PeerSaveInterval < managementDuration {
-		c.lastPeerManagement = time.Now()
+		c.lastPeerManagement = c.clock.Now()
 		c.logger.Debugf("managePeers() time since last peer management: %s", managementDuration.String())
 		// If it's been awhile, update peers from the DNS seed.
-		discoveryDuration := time.Since(c.lastDiscoveryRequest)
+		discoveryDuration := c.clock.Since(c.lastDiscoveryRequest)
 		if PeerDiscoveryInterval < discoveryDuration {
 			c.logger.Debug("calling c.discovery.DiscoverPeersFromSeed()")
 			c.discovery.DiscoverPeersFromSeed()
@@ -600,9 +607,9 @@ func (c *Controller) managePeers() {
 			c.logger.Debug("Saving peers")
 			c.discovery.SavePeers()
 		}
-		duration = time.Since(c.lastPeerRequest)
+		duration = c.clock.Since(c.lastPeerRequest)
 		if PeerRequestInterval < duration {
-			c.lastPeerRequest = time.Now()
+			c.lastPeerRequest = c.clock.Now()
 			parcelp := NewParcel(CurrentNetwork, []byte("Peer Request"))
 			parcel := *parcelp
 			parcel.Header.Type = TypePeerRequest
@@ -626,8 +633,8 @@ func (c *Controller) fillOutgoingSlots(openSlots int) {
 }
 
 func (c *Controller) updateMetrics() {
-	if time.Second < time.Since(c.lastConnectionMetricsUpdate) {
-		c.lastConnectionMetricsUpdate = time.Now()
+	if time.Second < c.clock.Since(c.lastConnectionMetricsUpdate) {
+		c.lastConnectionMetricsUpdate = c.clock.Now()
 		// Apparently golang doesn't make a deep copy when sending structs over channels. Bad golang.
 		newMetrics := make(map[string]ConnectionMetrics)
 		for key, value := range c.connections.All() {
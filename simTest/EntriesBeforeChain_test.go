@@ -29,7 +29,8 @@ func TestEntriesBeforeChain(t *testing.T) {
 
 	numEntries := 9 // set the total number of entries to add
 
-	state0 := SetupSim("LLAAFF", nil, 10, 0, 0, t)
+	// On the simulated clock the blocks take only as long as the nodes need to do their work
+	state0 := SetupSim("LLAAFF", map[string]string{"--simclock": "true"}, 10, 0, 0, t)
 
 	var entries []interfaces.IMsg
 	var oneFct uint64 = factom.FactoidToFactoshi("1")
@@ -113,6 +113,7 @@ func (l *HoldingList) Review() {
 			if msg != nil && l.isMsgStale(msg) {
 				l.holding[h][i] = nil // nil out the held message
 				delete(l.dependents, msg.GetMsgHash().Fixed())
+				l.s.RecordRejection(msg, RejectStale)
 				continue
 			}
 
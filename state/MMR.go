@@ -136,9 +136,13 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 		vm  int
 	}
 
+	clock := s.GetClock()
+	clock.Join() // A simulated clock waits for the MMRs of the asks due to be made
+	defer clock.Leave()
+
 	// Postpone asking for the first 5 seconds so simulations get a chance to get started. Doesn't break things but
 	// there is a flurry of unhelpful MMR activity on start up of simulations with followers
-	time.Sleep(5 * time.Second)
+	clock.Sleep(5 * time.Second)
 
 	var dbheight int // current process list height
 
@@ -242,6 +246,8 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 
 	// tick every "factom second" to check the  pending MMRs
 	go func() {
+		clock.Join()
+		defer clock.Leave()
 		for {
 			if s.RunState.IsTerminating() {
 				return // Factomd is stopping/stopped
@@ -250,7 +256,7 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 			if len(ticker) == cap(ticker) {
 				// If we add to the ticker, we will block forever, so just sleep
 				// and continue. If factomd is stopped, we will catch this on the continue
-				time.Sleep(1 * time.Second)
+				clock.Sleep(1 * time.Second)
 				s.LogPrintf("mmr", "Ticker queue maxed, %d/%d", len(ticker), cap(ticker))
 				continue
 			}
@@ -261,7 +267,7 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 				askDelay = time.Millisecond * 500
 			}
 
-			time.Sleep(askDelay)
+			clock.Sleep(askDelay)
 		}
 	}()
 
@@ -283,16 +289,20 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 			lastAskDelay = askDelay
 		}
 
-		// process any incoming messages
+		// process any incoming messages, letting a simulated clock move on while there are none
+		clock.Idle()
 		select {
 		case msgPair := <-rejects:
+			clock.Busy()
 			s.LogMessage("mmr", "Reject", msgPair.Ack)
 			s.RecentMessage.HandleRejection(msgPair.Msg, msgPair.Ack)
 		case msg := <-s.RecentMessage.NewMsgs:
+			clock.Busy()
 			s.LogPrintf("mmr", "start msg handling")
 			s.RecentMessage.Add(msg) // adds messages to a message map for MMR
 
 		case dbheight = <-dbheights:
+			clock.Busy()
 			s.LogPrintf("mmr", "start dbheight handling")
 			// toss any old pending requests when the height moves up
 			// todo: Keep asks in a  list so cleanup is more efficient
@@ -303,17 +313,20 @@ func (s *State) makeMMRs(asks <-chan askRef, adds <-chan plRef, dbheights <-chan
 				}
 			}
 		case ask := <-asks:
+			clock.Busy()
 			s.LogPrintf("mmr", "start ask handling")
 			addAsk(ask)  // add this ask
 			addAllAsks() // add all pending asks
 
 		case add := <-adds:
+			clock.Busy()
 			s.LogPrintf("mmr", "start add handling")
 			addAllAsks() // add all pending asks before any adds
 			s.LogPrintf("mmr", "asks done")
 			deletePendingAsk(add) // cancel any pending ask for the message just added to the process list
 
 		case now = <-ticker:
+			clock.Busy()
 			s.LogPrintf("mmr", "Ticker handling")
 			addAllAsks()     // process all pending asks before any adds
 			addAllAdds()     // process all pending add before any ticks
@@ -0,0 +1,134 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/anchor"
+)
+
+// The anchor monitor follows the DirBlockInfo records as anchors come in, and keeps the highest directory
+// block anchored on each ledger.  When a ledger falls more than AnchorStallThreshold blocks behind the
+// highest saved block the stalled gauge goes to 1, so an anchor outage raises an alert instead of being
+// found days later.
+
+const (
+	anchorMonitorSleep  = 30 * time.Second // Time between passes over the DirBlockInfos
+	anchorMonitorWindow = 1000             // Most blocks below the top we look at for new anchors
+)
+
+type AnchorLedgerStatus struct {
+	LatestHeight uint32 `json:"latestheight"` // Highest directory block anchored on this ledger
+	BlocksBehind uint32 `json:"blocksbehind"` // Saved directory blocks above LatestHeight
+	Stalled      bool   `json:"stalled"`
+}
+
+type AnchorStatus struct {
+	DirectoryBlockHeight uint32             `json:"directoryblockheight"`
+	StallThreshold       uint32             `json:"stallthreshold"`
+	Bitcoin              AnchorLedgerStatus `json:"bitcoin"`
+	Ethereum             AnchorLedgerStatus `json:"ethereum"`
+	LastRun              int64              `json:"lastrun"` // Unix time of the last pass
+}
+
+type AnchorMonitor struct {
+	mutex  sync.Mutex
+	status AnchorStatus
+}
+
+// Status returns a copy of the anchor status for the API
+func (m *AnchorMonitor) Status() AnchorStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.status
+}
+
+func (m *AnchorMonitor) update(f func(status *AnchorStatus)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	f(&m.status)
+}
+
+// GetAnchorStatus is used by the API to report the latest anchored heights
+func (s *State) GetAnchorStatus() interface{} {
+	return s.AnchorMonitor.Status()
+}
+
+// GoAnchorMonitor()
+// Tracks the latest anchored directory block per ledger, and sets the anchor prometheus gauges.
+func (s *State) GoAnchorMonitor() {
+	var btc, eth uint32 // Highest anchored heights found so far
+	first := true
+	for {
+		top := s.GetHighestSavedBlk()
+
+		// The first pass looks at the whole chain.  After that we only look above the lowest ledger, and
+		// never further back than the window; a ledger that far behind is stalled no matter what.
+		from := btc
+		if eth < from {
+			from = eth
+		}
+		if !first && top > anchorMonitorWindow && from < top-anchorMonitorWindow {
+			from = top - anchorMonitorWindow
+		}
+		first = false
+
+		for h := from; h <= top; h++ {
+			keyMR, err := s.DB.FetchDBKeyMRByHeight(h)
+			if err != nil || keyMR == nil {
+				continue
+			}
+			dbi, err := s.DB.FetchDirBlockInfoByKeyMR(keyMR)
+			if err != nil || dbi == nil {
+				continue
+			}
+			if dbi.GetBTCConfirmed() && h > btc {
+				btc = h
+			}
+			// Ethereum anchors a window of blocks, recorded on the highest block of the window
+			if dbi.GetEthereumConfirmed() && h > eth {
+				eth = h
+			}
+		}
+
+		threshold := uint32(s.AnchorStallThreshold)
+		s.AnchorMonitor.update(func(status *AnchorStatus) {
+			status.DirectoryBlockHeight = top
+			status.StallThreshold = threshold
+			status.Bitcoin = anchorLedgerStatus(top, btc, threshold)
+			status.Ethereum = anchorLedgerStatus(top, eth, threshold)
+			status.LastRun = time.Now().Unix()
+		})
+
+		status := s.AnchorMonitor.Status()
+		setAnchorGauges(anchor.LedgerBitcoin, status.Bitcoin)
+		setAnchorGauges(anchor.LedgerEthereum, status.Ethereum)
+
+		time.Sleep(anchorMonitorSleep)
+	}
+}
+
+// anchorLedgerStatus works out how far behind a ledger is.  A ledger that has never anchored anything is
+// not considered stalled; most test and private networks don't anchor at all.
+func anchorLedgerStatus(top uint32, latest uint32, threshold uint32) AnchorLedgerStatus {
+	ls := AnchorLedgerStatus{LatestHeight: latest}
+	if top > latest {
+		ls.BlocksBehind = top - latest
+	}
+	ls.Stalled = latest > 0 && threshold > 0 && ls.BlocksBehind > threshold
+	return ls
+}
+
+func setAnchorGauges(ledger string, ls AnchorLedgerStatus) {
+	AnchorLatestHeight.WithLabelValues(ledger).Set(float64(ls.LatestHeight))
+	AnchorBlocksBehind.WithLabelValues(ledger).Set(float64(ls.BlocksBehind))
+	stalled := 0.0
+	if ls.Stalled {
+		stalled = 1
+	}
+	AnchorStalled.WithLabelValues(ledger).Set(stalled)
+}
@@ -0,0 +1,317 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+)
+
+// Anchorer is the in-process anchoring subsystem.  Every saved directory block is handed to an anchor
+// backend, and the signed AnchorRecord (V2) the backend returns is written into the ledger's anchor chain,
+// paid for by a configured entry credit key.  Networks with an external anchor service don't need it.
+//
+// An anchor only counts once its entry is in a saved entry block.  Until then it is pending, and it is
+// submitted again if a few blocks are saved without it, as the commit may have been rejected (say for
+// lack of entry credits) or the reveal dropped.
+type Anchorer struct {
+	Backend    anchor.Backend
+	SigningKey *primitives.PrivateKey    // Signs the anchor records
+	ECKey      *primitives.PrivateKey    // Pays for the anchor entries
+	ChainID    interfaces.IHash          // Anchor chain of the backend's ledger
+	Anchored   uint32                    // Highest directory block anchored, with every one below it
+	Submitted  uint32                    // Highest directory block handed to the backend
+	Pending    map[uint32]*PendingAnchor // Anchors submitted but not yet in a saved entry block
+}
+
+// PendingAnchor is an anchor entry waiting to show up in a saved entry block
+type PendingAnchor struct {
+	Entry  *entryBlock.Entry
+	SentAt uint32 // Highest saved directory block when the entry was last submitted
+}
+
+const anchorRetryBlocks = 2 // Blocks saved without a pending anchor before it is submitted again
+
+// StartAnchoring sets up and starts the anchoring subsystem if the config file asks for one.
+func (s *State) StartAnchoring() error {
+	cfg := s.GetCfg().(*util.FactomdConfig)
+	if len(cfg.Anchor.Backend) == 0 {
+		return nil
+	}
+
+	var err error
+	a := new(Anchorer)
+	a.SigningKey, err = primitives.NewPrivateKeyFromHex(cfg.Anchor.SigningKey)
+	if err != nil {
+		return fmt.Errorf("bad anchor SigningKey: %v", err)
+	}
+	if err := checkSigningKey(cfg, cfg.Anchor.Ledger, a.SigningKey); err != nil {
+		return err
+	}
+	a.ECKey, err = primitives.NewPrivateKeyFromHex(cfg.Anchor.ECPrivateKey)
+	if err != nil {
+		return fmt.Errorf("bad anchor ECPrivateKey: %v", err)
+	}
+
+	a.Backend, err = anchor.NewBackend(cfg.Anchor.Backend, anchor.BackendConfig{Ledger: cfg.Anchor.Ledger, Path: cfg.Anchor.Path})
+	if err != nil {
+		return err
+	}
+	a.ChainID = anchor.AnchorChainID(a.Backend.Ledger())
+	a.Anchored = s.highestAnchored()
+	a.Submitted = a.Anchored
+	a.Pending = make(map[uint32]*PendingAnchor)
+	s.Anchorer = a
+
+	go s.GoAnchor()
+	return nil
+}
+
+// checkSigningKey refuses a SigningKey the anchor records of the ledger aren't validated with.  The
+// database would never save the DirBlockInfo of its records, so after every restart nothing would look
+// anchored and we would pay to anchor the whole chain again.
+func checkSigningKey(cfg *util.FactomdConfig, ledger string, key *primitives.PrivateKey) error {
+	keys := cfg.App.BitcoinAnchorRecordPublicKeys
+	if ledger == anchor.LedgerEthereum {
+		keys = cfg.App.EthereumAnchorRecordPublicKeys
+	}
+	for _, k := range keys {
+		if strings.EqualFold(strings.TrimSpace(k), key.Pub.String()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("anchor SigningKey %s is not one of the %s AnchorRecordPublicKeys", key.Pub.String(), ledger)
+}
+
+// highestAnchored looks back from the top of the database for the last directory block with anchor info
+func (s *State) highestAnchored() uint32 {
+	for h := s.GetHighestSavedBlk(); h > 0; h-- {
+		keyMR, err := s.DB.FetchDBKeyMRByHeight(h)
+		if err != nil || keyMR == nil {
+			continue
+		}
+		dbi, err := s.DB.FetchDirBlockInfoByKeyMR(keyMR)
+		if err == nil && dbi != nil {
+			return h
+		}
+	}
+	return 0
+}
+
+// GoAnchor()
+// Anchors every directory block as it is saved.  Blocks are anchored in order, a failure is retried, and
+// so is an anchor that doesn't make it into the blocks saved after it.
+func (s *State) GoAnchor() {
+	a := s.Anchorer
+	for {
+		time.Sleep(s.FactomSecond())
+
+		// Don't anchor while we are still loading from disk or syncing from our neighbors
+		if !s.DBFinished {
+			continue
+		}
+		// Nothing can go into the anchor chain until it exists
+		if !a.haveAnchorChain(s) {
+			continue
+		}
+
+		a.confirm(s)
+		a.retry(s)
+		for a.Submitted < s.GetHighestSavedBlk() {
+			height := a.Submitted + 1
+			if err := a.anchorBlock(s, height); err != nil {
+				s.LogPrintf("anchoring", "Failed to anchor dbht %d: %v", height, err)
+				break
+			}
+			a.Submitted = height
+		}
+	}
+}
+
+// confirm drops the pending anchors whose entries are in a saved entry block, and moves Anchored up to
+// the lowest height still pending.
+func (a *Anchorer) confirm(s *State) {
+	for height, p := range a.Pending {
+		entry, err := s.DB.FetchEntry(p.Entry.GetHash())
+		if err != nil || entry == nil {
+			continue
+		}
+		s.LogPrintf("anchoring", "Anchor of dbht %d confirmed, entry %x", height, p.Entry.GetHash().Bytes()[:4])
+		delete(a.Pending, height)
+	}
+	for a.Anchored < a.Submitted && a.Pending[a.Anchored+1] == nil {
+		a.Anchored++
+	}
+}
+
+// retry submits the pending anchors again once anchorRetryBlocks blocks have been saved without them
+func (a *Anchorer) retry(s *State) {
+	saved := s.GetHighestSavedBlk()
+	for height, p := range a.Pending {
+		if saved < p.SentAt+anchorRetryBlocks {
+			continue
+		}
+		if err := a.submit(s, p.Entry); err != nil {
+			s.LogPrintf("anchoring", "Failed to resubmit the anchor of dbht %d: %v", height, err)
+			continue
+		}
+		p.SentAt = saved
+		s.LogPrintf("anchoring", "Resubmitted the anchor of dbht %d, entry %x", height, p.Entry.GetHash().Bytes()[:4])
+	}
+}
+
+// submit queues the commit and reveal of an anchor entry.  The commit is left out if one at least as
+// good is already waiting for the reveal.
+func (a *Anchorer) submit(s *State, entry *entryBlock.Entry) error {
+	commit, err := a.newCommitEntry(s, entry)
+	if err != nil {
+		return err
+	}
+	if s.IsHighestCommit(entry.GetHash(), commit) {
+		s.APIQueue().Enqueue(commit)
+	}
+	s.APIQueue().Enqueue(a.newReveal(s, entry))
+	return nil
+}
+
+func (a *Anchorer) anchorBlock(s *State, height uint32) error {
+	keyMR, err := s.DB.FetchDBKeyMRByHeight(height)
+	if err != nil {
+		return err
+	}
+	if keyMR == nil {
+		return fmt.Errorf("no directory block at height %d", height)
+	}
+
+	ar, err := a.Backend.Anchor(height, keyMR)
+	if err != nil {
+		return err
+	}
+	content, extIDs, err := ar.SignedEntryParts(a.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	entry := entryBlock.NewEntry()
+	entry.ChainID = a.ChainID
+	entry.Content = primitives.ByteSlice{Bytes: content}
+	for _, id := range extIDs {
+		entry.ExtIDs = append(entry.ExtIDs, primitives.ByteSlice{Bytes: id})
+	}
+
+	if err := a.submit(s, entry); err != nil {
+		return err
+	}
+	a.Pending[height] = &PendingAnchor{Entry: entry, SentAt: s.GetHighestSavedBlk()}
+	s.LogPrintf("anchoring", "Submitted the anchor of dbht %d keymr %x with %s, entry %x", height, keyMR.Bytes()[:4],
+		a.Backend.Name(), entry.GetHash().Bytes()[:4])
+	return nil
+}
+
+// haveAnchorChain returns true once the anchor chain exists.  On a new network the first call creates it.
+func (a *Anchorer) haveAnchorChain(s *State) bool {
+	if head, err := s.DB.FetchHeadIndexByChainID(a.ChainID); err == nil && head != nil {
+		return true
+	}
+	if s.IsNewOrPendingEBlocks(s.GetLeaderHeight(), a.ChainID) {
+		return false // Coming up in this block
+	}
+
+	entry := entryBlock.NewEntry()
+	entry.ChainID = a.ChainID
+	entry.ExtIDs = []primitives.ByteSlice{{Bytes: []byte(anchor.AnchorChainName(a.Backend.Ledger()))}}
+
+	commit, err := a.newCommitChain(s, entry)
+	if err != nil {
+		s.LogPrintf("anchoring", "Failed to create the anchor chain %x: %v", a.ChainID.Bytes()[:4], err)
+		return false
+	}
+	if s.IsHighestCommit(entry.GetHash(), commit) {
+		s.APIQueue().Enqueue(commit)
+		s.APIQueue().Enqueue(a.newReveal(s, entry))
+		s.LogPrintf("anchoring", "Creating anchor chain %x", a.ChainID.Bytes()[:4])
+	}
+	return false
+}
+
+func (a *Anchorer) newCommitChain(s *State, entry *entryBlock.Entry) (*messages.CommitChainMsg, error) {
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	commit := entryCreditBlock.NewCommitChain()
+	commit.Credits, err = util.EntryCost(data)
+	if err != nil {
+		return nil, err
+	}
+	commit.Credits += 10
+	commit.EntryHash = entry.GetHash()
+	commit.MilliTime = a.milliTime(s)
+	commit.ECPubKey = a.ecPubKey()
+	commit.Weld = entry.GetWeldHash()
+	commit.ChainIDHash = primitives.Shad(entry.GetChainID().Bytes())
+	if err := commit.Sign(a.ECKey.Key[:]); err != nil {
+		return nil, err
+	}
+
+	msg := new(messages.CommitChainMsg)
+	msg.CommitChain = commit
+	return msg, nil
+}
+
+func (a *Anchorer) newCommitEntry(s *State, entry *entryBlock.Entry) (*messages.CommitEntryMsg, error) {
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.Credits, err = util.EntryCost(data)
+	if err != nil {
+		return nil, err
+	}
+	commit.EntryHash = entry.GetHash()
+	commit.MilliTime = a.milliTime(s)
+	commit.ECPubKey = a.ecPubKey()
+	if err := commit.Sign(a.ECKey.Key[:]); err != nil {
+		return nil, err
+	}
+
+	msg := messages.NewCommitEntryMsg()
+	msg.CommitEntry = commit
+	return msg, nil
+}
+
+func (a *Anchorer) newReveal(s *State, entry *entryBlock.Entry) *messages.RevealEntryMsg {
+	msg := messages.NewRevealEntryMsg()
+	msg.Entry = entry
+	msg.Timestamp = s.GetTimestamp()
+	return msg
+}
+
+func (a *Anchorer) ecPubKey() *primitives.ByteSlice32 {
+	var b32 primitives.ByteSlice32
+	copy(b32[:], a.ECKey.Pub[:])
+	return &b32
+}
+
+// milliTime returns the 6 byte commit timestamp
+func (a *Anchorer) milliTime(s *State) *primitives.ByteSlice6 {
+	var b6 primitives.ByteSlice6
+	ms := s.GetTimestamp().GetTimeMilliUInt64()
+	for i := 5; i >= 0; i-- {
+		b6[i] = byte(ms)
+		ms >>= 8
+	}
+	return &b6
+}
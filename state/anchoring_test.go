@@ -0,0 +1,194 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/util"
+)
+
+// anchorTestState returns a state with saved directory blocks 0 to height, and an Anchorer writing to a
+// file backend in dir
+func anchorTestState(t *testing.T, dir string, height uint32) (*State, *Anchorer) {
+	s := new(State)
+	s.apiQueue = NewAPIQueue(100)
+	s.Commits = NewSafeMsgMap("commits", s)
+	s.DB = databaseOverlay.NewOverlay(new(mapdb.MapDB))
+	s.DBStates = &DBStateList{State: s}
+	saveTestBlocks(t, s, height)
+
+	backend, err := anchor.NewBackend("file", anchor.BackendConfig{Ledger: anchor.LedgerBitcoin, Path: filepath.Join(dir, "mockchain.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := new(Anchorer)
+	a.Backend = backend
+	a.ChainID = anchor.AnchorChainID(anchor.LedgerBitcoin)
+	a.SigningKey = primitives.RandomPrivateKey()
+	a.ECKey = primitives.RandomPrivateKey()
+	a.Pending = make(map[uint32]*PendingAnchor)
+	return s, a
+}
+
+// saveTestBlocks saves empty directory blocks up to height
+func saveTestBlocks(t *testing.T, s *State, height uint32) {
+	var prev interfaces.IDirectoryBlock
+	if last := s.DBStates.Last(); last != nil {
+		prev = last.DirectoryBlock
+	}
+	for prev == nil || prev.GetDatabaseHeight() < height {
+		dblock := directoryBlock.NewDirectoryBlock(prev)
+		if err := s.DB.(*databaseOverlay.Overlay).ProcessDBlockBatch(dblock); err != nil {
+			t.Fatal(err)
+		}
+		s.DBStates.DBStates = append(s.DBStates.DBStates, &DBState{DirectoryBlock: dblock, Saved: true})
+		prev = dblock
+	}
+}
+
+// queued returns the messages the Anchorer has queued for the API
+func queued(s *State) (commits int, reveals []*messages.RevealEntryMsg) {
+	for s.APIQueue().Length() > 0 {
+		switch msg := s.APIQueue().Dequeue().(type) {
+		case *messages.CommitEntryMsg:
+			commits++
+		case *messages.RevealEntryMsg:
+			reveals = append(reveals, msg)
+		}
+	}
+	return
+}
+
+func TestAnchorBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, a := anchorTestState(t, dir, 3)
+	defer a.Backend.Close()
+
+	if err := a.anchorBlock(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	p := a.Pending[2]
+	if p == nil || p.SentAt != 3 {
+		t.Fatalf("Expected the anchor of dbht 2 pending since dbht 3, got %v", p)
+	}
+
+	commits, reveals := queued(s)
+	if commits != 1 || len(reveals) != 1 {
+		t.Fatalf("Expected a commit and a reveal, got %d and %d", commits, len(reveals))
+	}
+	entry := reveals[0].Entry
+	if !entry.GetChainID().IsSameAs(a.ChainID) || !entry.GetHash().IsSameAs(p.Entry.GetHash()) {
+		t.Errorf("Revealed entry %x is not the pending anchor in the anchor chain", entry.GetHash().Bytes()[:4])
+	}
+	keyMR, _ := s.DB.FetchDBKeyMRByHeight(2)
+	ar, valid, err := anchor.UnmarshalAndValidateAnchorRecordV2(entry.GetContent(), entry.ExternalIDs(), []interfaces.Verifier{a.SigningKey.Pub})
+	if err != nil || !valid {
+		t.Fatalf("Anchor record did not validate: %v", err)
+	}
+	if ar.DBHeight != 2 || ar.KeyMR != keyMR.String() {
+		t.Errorf("Anchored dbht %d keymr %s, expected 2 and %s", ar.DBHeight, ar.KeyMR, keyMR.String())
+	}
+
+	if err := a.anchorBlock(s, 4); err == nil {
+		t.Error("Anchored a directory block we don't have")
+	}
+	if a.Pending[4] != nil {
+		t.Error("A failed anchor is pending")
+	}
+}
+
+func TestAnchorRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, a := anchorTestState(t, dir, 3)
+	defer a.Backend.Close()
+
+	if err := a.anchorBlock(s, 3); err != nil {
+		t.Fatal(err)
+	}
+	queued(s)
+
+	// Not yet missing from enough blocks
+	saveTestBlocks(t, s, 3+anchorRetryBlocks-1)
+	a.retry(s)
+	if commits, reveals := queued(s); commits != 0 || len(reveals) != 0 {
+		t.Errorf("Resubmitted the anchor after %d blocks", anchorRetryBlocks-1)
+	}
+
+	saveTestBlocks(t, s, 3+anchorRetryBlocks)
+	a.retry(s)
+	commits, reveals := queued(s)
+	if commits != 1 || len(reveals) != 1 || !reveals[0].Entry.GetHash().IsSameAs(a.Pending[3].Entry.GetHash()) {
+		t.Fatalf("Expected the anchor to be resubmitted, got %d commits and %d reveals", commits, len(reveals))
+	}
+	if a.Pending[3].SentAt != 3+anchorRetryBlocks {
+		t.Errorf("Resubmitted anchor sent at %d, expected %d", a.Pending[3].SentAt, 3+anchorRetryBlocks)
+	}
+}
+
+func TestAnchorConfirm(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, a := anchorTestState(t, dir, 3)
+	defer a.Backend.Close()
+
+	for height := uint32(1); height <= 3; height++ {
+		if err := a.anchorBlock(s, height); err != nil {
+			t.Fatal(err)
+		}
+		a.Submitted = height
+	}
+
+	// The anchor of 2 lands before the one of 1
+	if err := s.DB.InsertEntry(a.Pending[2].Entry); err != nil {
+		t.Fatal(err)
+	}
+	a.confirm(s)
+	if a.Pending[2] != nil || a.Pending[1] == nil || a.Anchored != 0 {
+		t.Errorf("Expected only the anchor of dbht 2 confirmed, with nothing anchored yet, got anchored %d", a.Anchored)
+	}
+
+	if err := s.DB.InsertEntry(a.Pending[1].Entry); err != nil {
+		t.Fatal(err)
+	}
+	a.confirm(s)
+	if a.Pending[1] != nil || a.Pending[3] == nil || a.Anchored != 2 {
+		t.Errorf("Expected dbht 1 and 2 anchored with 3 pending, got anchored %d", a.Anchored)
+	}
+}
+
+func TestCheckSigningKey(t *testing.T) {
+	cfg := util.ReadConfig("")
+	key := primitives.RandomPrivateKey()
+
+	if err := checkSigningKey(cfg, anchor.LedgerBitcoin, key); err == nil {
+		t.Error("Accepted a SigningKey that isn't one of the anchor record keys")
+	}
+
+	cfg.App.EthereumAnchorRecordPublicKeys = append(cfg.App.EthereumAnchorRecordPublicKeys, key.Pub.String())
+	if err := checkSigningKey(cfg, anchor.LedgerBitcoin, key); err == nil {
+		t.Error("Accepted a SigningKey for Bitcoin that is only an Ethereum anchor record key")
+	}
+	if err := checkSigningKey(cfg, anchor.LedgerEthereum, key); err != nil {
+		t.Error(err)
+	}
+}
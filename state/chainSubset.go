@@ -0,0 +1,79 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// A chain subset node stores every directory, admin, factoid and entry credit block, and every entry
+// block (we need the chain heads to build and validate the next directory block), but only keeps the
+// entries of the chains listed in KeepEntryChains.  Identity, anchor, FER and grant chains are always
+// kept since consensus depends on their entries.
+
+// SetKeepEntryChains parses a comma separated list of chain IDs.  An empty list keeps every chain.  A bad
+// list leaves the chains kept as they were.
+func (s *State) SetKeepEntryChains(list string) error {
+	keep, err := parseKeepEntryChains(list)
+	if err != nil {
+		return err
+	}
+	s.KeepEntryChains = keep
+	return nil
+}
+
+// parseKeepEntryChains returns the set of chain IDs in the list, nil for an empty list
+func parseKeepEntryChains(list string) (map[[32]byte]bool, error) {
+	if len(strings.TrimSpace(list)) == 0 {
+		return nil, nil
+	}
+
+	keep := make(map[[32]byte]bool)
+	for _, c := range strings.Split(list, ",") {
+		c = strings.TrimSpace(c)
+		if len(c) == 0 {
+			continue
+		}
+		h, err := primitives.HexToHash(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain id %q in KeepEntryChains: %s", c, err.Error())
+		}
+		keep[h.Fixed()] = true
+	}
+	return keep, nil
+}
+
+// IsChainSubset returns true if this node only keeps the entries of some chains
+func (s *State) IsChainSubset() bool {
+	return s.KeepEntryChains != nil
+}
+
+// IsChainKept returns true if this node stores the entries of the given chain
+func (s *State) IsChainKept(chainID interfaces.IHash) bool {
+	if s.KeepEntryChains == nil || chainID == nil {
+		return true
+	}
+	if s.KeepEntryChains[chainID.Fixed()] {
+		return true
+	}
+
+	cid := chainID.Bytes()
+	if bytes.Compare(cid[:3], []byte{0x88, 0x88, 0x88}) == 0 { // Identity chains
+		return true
+	}
+	if databaseOverlay.ValidAnchorChains[chainID.String()] {
+		return true
+	}
+	if grants, ok := s.GrantSource.(*GrantChainSource); ok && grants.ChainID.IsSameAs(chainID) {
+		return true
+	}
+	return chainID.String() == s.FERChainId
+}
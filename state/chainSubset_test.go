@@ -0,0 +1,106 @@
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestKeepEntryChains(t *testing.T) {
+	s := new(State)
+	s.FERChainId = "111111118d918a8be684e0dac725493a75862ef96d2d3f43f84b26969329bf03"
+
+	kept := primitives.Sha([]byte("kept"))
+	dropped := primitives.Sha([]byte("dropped"))
+
+	if err := s.SetKeepEntryChains(""); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsChainSubset() || !s.IsChainKept(dropped) {
+		t.Error("An empty list should keep every chain")
+	}
+
+	if err := s.SetKeepEntryChains(" " + kept.String() + " ,"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsChainSubset() {
+		t.Error("Expected a chain subset node")
+	}
+	if !s.IsChainKept(kept) {
+		t.Error("Configured chain should be kept")
+	}
+	if s.IsChainKept(dropped) {
+		t.Error("Unlisted chain should not be kept")
+	}
+
+	for _, c := range []string{
+		"888888d027c59579fc47a6fc6c4a5c0409c7c39bc38a86cb5fc0069978493762",
+		databaseOverlay.BitcoinAnchorChainID,
+		databaseOverlay.EthereumAnchorChainID,
+		s.FERChainId,
+	} {
+		h, _ := primitives.HexToHash(c)
+		if !s.IsChainKept(h) {
+			t.Errorf("System chain %s should always be kept", c)
+		}
+	}
+
+	if err := s.SetKeepEntryChains("not a chain"); err == nil {
+		t.Error("Expected an error for a bad chain id")
+	}
+	if !s.IsChainSubset() || !s.IsChainKept(kept) || s.IsChainKept(dropped) {
+		t.Error("A bad chain id changed the chains kept")
+	}
+}
+
+func TestLoadConfigRejectsBadKeepEntryChains(t *testing.T) {
+	f, err := ioutil.TempFile("", "factomd.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("[app]\nKeepEntryChains = not a chain\n")
+	f.Close()
+
+	// Starting as a full node would silently keep every chain
+	s := new(State)
+	if err := s.LoadConfig(f.Name(), "LOCAL"); err == nil {
+		t.Error("Loaded a config with a bad chain id in KeepEntryChains")
+	}
+}
+
+func TestBadReloadLeavesConfig(t *testing.T) {
+	write := func(config string) string {
+		f, err := ioutil.TempFile("", "factomd.conf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString("[app]\n" + config)
+		f.Close()
+		return f.Name()
+	}
+	kept := primitives.Sha([]byte("kept"))
+	good := write("KeepEntryChains = " + kept.String() + "\nPruneRetention = 10\n")
+	defer os.Remove(good)
+	bad := write("KeepEntryChains = not a chain\nPruneRetention = 20\n")
+	defer os.Remove(bad)
+
+	s := new(State)
+	if err := s.LoadConfig(good, "LOCAL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadConfig(bad, "LOCAL"); err == nil {
+		t.Fatal("Reloaded a config with a bad chain id in KeepEntryChains")
+	}
+	// Reloading the bad config must not turn the node into a full node, nor apply half of it
+	if !s.IsChainSubset() || !s.IsChainKept(kept) {
+		t.Error("A bad reload stopped the node from being a chain subset node")
+	}
+	if s.PruneRetention != 10 || s.ConfigFilePath != good {
+		t.Errorf("A bad reload applied part of the config, PruneRetention %d from %s", s.PruneRetention, s.ConfigFilePath)
+	}
+}
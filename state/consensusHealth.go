@@ -0,0 +1,107 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// The consensus health monitor exports what the diagnostics API and the control panel show about the
+// leaders as prometheus gauges: how far each VM of the process list has been processed, how long it has
+// been since each leader's last EOM, elections, faulted servers and how many DBSigs agreed with our block.
+// The rules in prometheus-alerts.yml page on them.
+
+const consensusHealthSleep = 5 * time.Second // Time between updates of the consensus gauges
+
+type ConsensusHealth struct {
+	mutex   sync.Mutex
+	lastEOM map[[32]byte]time.Time // When we last processed an EOM from each leader
+}
+
+// eomProcessed records an EOM from a leader, and returns the time since its previous one
+func (h *ConsensusHealth) eomProcessed(leader interfaces.IHash, now time.Time) (time.Duration, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.lastEOM == nil {
+		h.lastEOM = make(map[[32]byte]time.Time)
+	}
+	last, ok := h.lastEOM[leader.Fixed()]
+	h.lastEOM[leader.Fixed()] = now
+	return now.Sub(last), ok
+}
+
+// sinceEOM returns the time since the last EOM from a leader, false if we have never had one
+func (h *ConsensusHealth) sinceEOM(leader interfaces.IHash, now time.Time) (time.Duration, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	last, ok := h.lastEOM[leader.Fixed()]
+	return now.Sub(last), ok
+}
+
+// recordEOM is called as each leader's EOM is processed
+func (s *State) recordEOM(leader interfaces.IHash) {
+	if interval, ok := s.ConsensusHealth.eomProcessed(leader, time.Now()); ok {
+		ConsensusEOMInterval.Observe(interval.Seconds())
+	}
+}
+
+// GoConsensusHealth()
+// Keeps the consensus health prometheus gauges up to date.
+func (s *State) GoConsensusHealth() {
+	for {
+		s.UpdateConsensusHealth()
+		time.Sleep(consensusHealthSleep)
+	}
+}
+
+// UpdateConsensusHealth sets the consensus health gauges from the leader process list and the elections
+func (s *State) UpdateConsensusHealth() {
+	pl := s.LeaderPL
+	if pl == nil {
+		return
+	}
+	now := time.Now()
+
+	ConsensusVMListHeight.Reset()
+	ConsensusVMListLength.Reset()
+	faulted := 0
+	for i := 0; i < len(pl.FedServers) && i < len(pl.VMs); i++ {
+		vm := pl.VMs[i]
+		ConsensusVMListHeight.WithLabelValues(strconv.Itoa(i)).Set(float64(vm.Height))
+		ConsensusVMListLength.WithLabelValues(strconv.Itoa(i)).Set(float64(len(vm.List)))
+		if vm.WhenFaulted != 0 {
+			faulted++
+		}
+	}
+	ConsensusFaultedServers.Set(float64(faulted))
+
+	ConsensusMinutesSinceEOM.Reset()
+	for _, fed := range pl.FedServers {
+		if since, ok := s.ConsensusHealth.sinceEOM(fed.GetChainID(), now); ok {
+			ConsensusMinutesSinceEOM.WithLabelValues(fed.GetChainID().String()).Set(since.Minutes())
+		}
+	}
+
+	// The DBSigs of a block are only all in once DBSig syncing is over, so keep the last ratio until then
+	if !s.DBSig && len(pl.FedServers) > 0 {
+		ConsensusDBSigAgreement.Set(float64(len(pl.DBSignatures)) / float64(len(pl.FedServers)))
+	}
+
+	active, round := 0.0, 0.0
+	if e := s.Elections; e != nil {
+		if electing := e.GetElecting(); electing != -1 {
+			active = 1
+			if rounds := e.GetRound(); electing < len(rounds) {
+				round = float64(rounds[electing])
+			}
+		}
+	}
+	ConsensusElectionActive.Set(active)
+	ConsensusElectionRound.Set(round)
+}
@@ -0,0 +1,35 @@
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	metric := &dto.Metric{}
+	if err := gauge.Write(metric); err != nil {
+		t.Fatalf("failed to read gauge: %v", err)
+	}
+	return metric.GetGauge().GetValue()
+}
+
+func TestUpdateConsensusHealth(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	pl := s.LeaderPL
+
+	s.UpdateConsensusHealth()
+	assert.EqualValues(t, pl.VMs[0].Height, gaugeValue(t, ConsensusVMListHeight.WithLabelValues("0")))
+	assert.EqualValues(t, len(pl.VMs[0].List), gaugeValue(t, ConsensusVMListLength.WithLabelValues("0")))
+	assert.EqualValues(t, 0, gaugeValue(t, ConsensusFaultedServers))
+	assert.EqualValues(t, 0, gaugeValue(t, ConsensusElectionActive))
+
+	pl.VMs[0].WhenFaulted = 1
+	defer func() { pl.VMs[0].WhenFaulted = 0 }()
+	s.UpdateConsensusHealth()
+	assert.EqualValues(t, 1, gaugeValue(t, ConsensusFaultedServers))
+}
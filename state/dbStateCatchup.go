@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/util/clock"
 )
 
 type GenericListItem interface {
@@ -27,7 +28,7 @@ func getHeightSafe(i GenericListItem) int {
 func waitForLoaded(s *State) {
 	// Don't start until the db is finished loading.
 	for !s.DBFinished {
-		time.Sleep(1 * time.Second)
+		s.GetClock().Sleep(1 * time.Second)
 	}
 	if s.highestKnown < s.DBHeightAtBoot {
 		s.highestKnown = s.DBHeightAtBoot + 1 // Make sure we ask for the next block after the database at startup.
@@ -42,6 +43,7 @@ func (list *DBStateList) Catchup() {
 	received := list.State.StatesReceived
 
 	factomSecond := list.State.FactomSecond()
+	clock := list.State.GetClock()
 
 	requestTimeout := time.Duration(list.State.RequestTimeout) * factomSecond
 	requestLimit := list.State.RequestLimit
@@ -116,7 +118,7 @@ func (list *DBStateList) Catchup() {
 		list.State.LogPrintf("dbstatecatchup", "Start with hs = %d hk = %d", hs, hk)
 
 		for {
-			start := time.Now()
+			start := clock.Now()
 			// get the height of the saved blocks
 			hs = hsf()
 			hk = hkf()
@@ -174,12 +176,12 @@ func (list *DBStateList) Catchup() {
 			}
 
 			list.State.LogPrintf("dbstatecatchup", "height update took %s. Base:%d/%d/%d, Miss[v%d, ^_, T%d], Wait [v_, ^%d, T%d], Rec[v%d, ^%d, T%d]",
-				time.Since(start),
+				clock.Since(start),
 				received.Base(), hs, list.State.GetDBHeightAtBoot(),
 				getHeightSafe(missing.GetFront()), missing.Len(),
 				getHeightSafe(waiting.GetEnd()), waiting.Len(),
 				received.Base(), received.Heighestreceived(), received.List.Len())
-			time.Sleep(factomSecond)
+			clock.Sleep(factomSecond)
 		}
 	}()
 
@@ -205,7 +207,7 @@ func (list *DBStateList) Catchup() {
 				}
 			}
 
-			time.Sleep(requestTimeout)
+			clock.Sleep(requestTimeout)
 		}
 	}()
 
@@ -234,7 +236,7 @@ func (list *DBStateList) Catchup() {
 				list.State.LogPrintf("dbstatecatchup", "dbstate requesting from %d to %d", b, e)
 
 				if b == 0 && e == 0 {
-					time.Sleep(1 * time.Second)
+					clock.Sleep(1 * time.Second)
 					continue
 				}
 
@@ -263,7 +265,7 @@ func (list *DBStateList) Catchup() {
 					}
 				}
 
-				time.Sleep(50 * time.Millisecond)
+				clock.Sleep(50 * time.Millisecond)
 			}
 		}
 	}()
@@ -427,12 +429,14 @@ func (l *StatesMissing) GetNext() *MissingState {
 type WaitingState struct {
 	height        uint32
 	requestedTime time.Time
+	clock         clock.Clock
 }
 
-func NewWaitingState(height uint32) *WaitingState {
+func NewWaitingState(height uint32, c clock.Clock) *WaitingState {
 	s := new(WaitingState)
 	s.height = height
-	s.requestedTime = time.Now()
+	s.clock = c
+	s.requestedTime = c.Now()
 	return s
 }
 
@@ -441,24 +445,32 @@ func (s *WaitingState) Height() uint32 {
 }
 
 func (s *WaitingState) RequestAge() time.Duration {
-	return time.Since(s.requestedTime)
+	return s.clock.Since(s.requestedTime)
 }
 
 func (s *WaitingState) ResetRequestAge() {
-	s.requestedTime = time.Now()
+	s.requestedTime = s.clock.Now()
 }
 
 type StatesWaiting struct {
 	List *list.List
 	// Notify chan *WaitingState
-	lock *sync.Mutex
+	lock  *sync.Mutex
+	clock clock.Clock // Times the requests
 }
 
+// NewStatesWaiting creates a new list of requested DBStates, timed on the wall clock
 func NewStatesWaiting() *StatesWaiting {
+	return NewStatesWaitingOnClock(clock.Real)
+}
+
+// NewStatesWaitingOnClock creates a new list of requested DBStates, timed on the node's clock
+func NewStatesWaitingOnClock(c clock.Clock) *StatesWaiting {
 	l := new(StatesWaiting)
 	l.List = list.New()
 	// l.Notify = make(chan *WaitingState)
 	l.lock = new(sync.Mutex)
+	l.clock = c
 	return l
 }
 
@@ -484,18 +496,18 @@ func (l *StatesWaiting) Add(height uint32) {
 	for e := l.List.Back(); e != nil; e = e.Prev() {
 		s := e.Value.(*WaitingState)
 		if s == nil {
-			n := NewWaitingState(height)
+			n := NewWaitingState(height, l.clock)
 			l.List.InsertAfter(n, e)
 			return
 		} else if height > s.Height() {
-			n := NewWaitingState(height)
+			n := NewWaitingState(height, l.clock)
 			l.List.InsertAfter(n, e)
 			return
 		} else if height == s.Height() {
 			return
 		}
 	}
-	l.List.PushFront(NewWaitingState(height))
+	l.List.PushFront(NewWaitingState(height, l.clock))
 }
 
 func (l *StatesWaiting) LockAndDelete(height uint32) {
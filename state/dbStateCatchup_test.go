@@ -14,6 +14,7 @@ import (
 	"github.com/FactomProject/factomd/common/directoryBlock"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util/clock"
 )
 
 // Made the lists generic so a test can be run on all of them
@@ -361,3 +362,13 @@ func testMissingConsecutive(t *testing.T, add []int, n, bExp, eExp int) {
 		t.Errorf("Expected %d-%d, found %d-%d", bExp, eExp, b, e)
 	}
 }
+
+func TestWaitingRequestAgeOnClock(t *testing.T) {
+	c := clock.NewSimulated(time.Now())
+	list := state.NewStatesWaitingOnClock(c)
+	list.Add(1)
+	c.Sleep(time.Minute)
+	if age := list.ListAsSlice()[0].RequestAge(); age != time.Minute {
+		t.Errorf("The request is %s old on the node's clock, expected a minute", age)
+	}
+}
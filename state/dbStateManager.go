@@ -23,6 +23,7 @@ import (
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/tracing"
 	"github.com/FactomProject/factomd/util/atomic"
 
 	llog "github.com/FactomProject/factomd/log"
@@ -821,6 +822,17 @@ func (list *DBStateList) FixupLinks(p *DBState, d *DBState) (progress bool) {
 
 		return false
 	}
+
+	// The grants are read before the admin block is touched.  If the grant source can't be read yet the
+	// block is left new, so it isn't built or signed, and is tried again on the next pass.
+	var grantPayouts []interfaces.ITransAddress
+	if currentDBHeight > constants.COINBASE_ACTIVATION && currentDBHeight%constants.COINBASE_PAYOUT_FREQUENCY == 1 {
+		grantPayouts, err = list.State.GetGrantPayouts(currentDBHeight)
+		if err != nil {
+			list.State.LogPrintf("dbstateprocess", "FixupLinks(%d) waiting on the grants: %v", currentDBHeight, err)
+			return false
+		}
+	}
 	//list.State.AddStatus(fmt.Sprintf("FIXUPLINKS: Adding the first %d dbsigs",
 	//	majority))
 
@@ -885,7 +897,6 @@ func (list *DBStateList) FixupLinks(p *DBState, d *DBState) (progress bool) {
 	// every 25 blocks +1 we add grant payouts
 	if currentDBHeight > constants.COINBASE_ACTIVATION && currentDBHeight%constants.COINBASE_PAYOUT_FREQUENCY == 1 {
 		// Add the grants to the list
-		grantPayouts := GetGrantPayoutsFor(currentDBHeight)
 		if len(grantPayouts) > 0 {
 			err := d.AdminBlock.AddCoinbaseDescriptor(grantPayouts)
 			if err != nil {
@@ -1589,6 +1600,9 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	progress = true
 	d.ReadyToSave = false
 	d.Saved = true
+	if list.State.TraceMessages {
+		tracing.BlockSaved(uint32(dbheight))
+	}
 
 	// Now that we have saved the perm balances, we can clear the api hashmaps that held the differences
 	// between the actual saved block prior, and this saved block.  If you are looking for balances of
@@ -0,0 +1,185 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Dry run validation runs the checks a factoid transaction or a commit goes through when it is submitted,
+// against the current temporary balances, but doesn't queue, hold or broadcast anything.  Wallets use it
+// to show the exact reason a submission would be refused.  The validate method of the message decides,
+// run against a dryRunState so a message short of funds isn't put in holding.  The checks it is made of
+// are also run one at a time, to tell the reasons apart.
+
+// The reasons a submission is refused
+const (
+	DryRunMalformed           = "malformed"
+	DryRunBadSignature        = "bad_signature"
+	DryRunInsufficientBalance = "insufficient_balance"
+	DryRunFeeTooLow           = "fee_too_low"
+	DryRunDuplicate           = "duplicate"
+	DryRunTimestamp           = "timestamp_out_of_window"
+)
+
+type DryRunReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Address string `json:"address,omitempty"` // The input or EC address that is short of funds
+}
+
+type DryRunResult struct {
+	Valid   bool           `json:"valid"`
+	TxID    string         `json:"txid"`
+	Reasons []DryRunReason `json:"reasons"`
+}
+
+func (r *DryRunResult) add(code string, address string, format string, args ...interface{}) {
+	r.Reasons = append(r.Reasons, DryRunReason{Code: code, Message: fmt.Sprintf(format, args...), Address: address})
+}
+
+// dryRunState is the state the validate methods of the messages see in a dry run.  The address a message
+// is short of is noted rather than the message added to holding.
+type dryRunState struct {
+	*State
+	short [32]byte
+}
+
+func (d *dryRunState) Add(h [32]byte, msg interfaces.IMsg) int {
+	d.short = h
+	return -2
+}
+
+// DryRunValidate checks a FactoidTransaction, CommitChainMsg or CommitEntryMsg as if it had been submitted.
+// Returns a DryRunResult, or nil if the message is of any other type.
+func (s *State) DryRunValidate(msg interfaces.IMsg) interface{} {
+	var result *DryRunResult
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		result = s.dryRunTransaction(m)
+	case *messages.CommitChainMsg:
+		result = s.dryRunCommit(m, m.CommitChain.Version, m.CommitChain.Credits, 11, 20, m.CommitChain.ECPubKey.Fixed(),
+			m.CommitChain.ValidateSignatures(), m.CommitChain.GetEntryHash())
+	case *messages.CommitEntryMsg:
+		result = s.dryRunCommit(m, m.CommitEntry.Version, m.CommitEntry.Credits, 1, 10, m.CommitEntry.ECPubKey.Fixed(),
+			m.CommitEntry.ValidateSignatures(), m.CommitEntry.GetEntryHash())
+	default:
+		return nil
+	}
+	result.Valid = len(result.Reasons) == 0
+	return *result
+}
+
+func (s *State) dryRunTransaction(m *messages.FactoidTransaction) *DryRunResult {
+	trans := m.Transaction
+	result := &DryRunResult{TxID: trans.GetSigHash().String(), Reasons: []DryRunReason{}}
+
+	// The checks of FactoidTransaction.Validate
+	if err := trans.Validate(1); err != nil {
+		result.add(DryRunMalformed, "", "%v", err)
+		return result // The remaining checks assume the inputs, outputs and RCDs line up
+	}
+	sigErr := trans.ValidateSignatures()
+	if sigErr != nil {
+		result.add(DryRunBadSignature, "", "%v", sigErr)
+	}
+	if err, short := s.FactoidState.Validate(1, trans); err != nil {
+		if short != [32]byte{} {
+			result.add(DryRunInsufficientBalance, primitives.ConvertFctAddressToUserStr(factoid.NewAddress(short[:])),
+				"%v", err)
+		} else {
+			result.add(DryRunMalformed, "", "%v", err) // The inputs of an address overflow
+		}
+	}
+
+	// and those of adding it to the current block, at the exchange rate of the block
+	if block := s.FactoidState.GetCurrentBlock(); block != nil {
+		if sigErr == nil {
+			// Validate and the signatures passed, so only the fee is left for it to refuse
+			if err := block.ValidateTransaction(1, trans); err != nil {
+				result.add(DryRunFeeTooLow, "", "%v", err)
+			}
+		}
+		if err := s.FactoidState.ValidateTransactionAge(trans); err != nil {
+			result.add(DryRunTimestamp, "", "%v", err)
+			return result
+		}
+	}
+
+	dry := &dryRunState{State: s}
+	if m.Validate(dry) < 0 && len(result.Reasons) == 0 {
+		result.add(DryRunMalformed, "", "refused by the transaction validation")
+	}
+	s.dryRunReplay(result, m)
+	return result
+}
+
+func (s *State) dryRunCommit(m interfaces.IMsg, version uint8, credits uint8, min uint8, max uint8, ecAddress [32]byte,
+	sigErr error, entryHash interfaces.IHash) *DryRunResult {
+	result := &DryRunResult{TxID: m.GetRepeatHash().String(), Reasons: []DryRunReason{}}
+
+	// CommitChainMsg.Validate or CommitEntryMsg.Validate decides, and the validity of the commit it starts
+	// with is broken down into its checks
+	dry := &dryRunState{State: s}
+	switch m.Validate(dry) {
+	case 1:
+	case -1:
+		if version != 0 {
+			result.add(DryRunMalformed, "", "version %d is not supported", version)
+		}
+		switch {
+		case credits < min:
+			result.add(DryRunFeeTooLow, "", "%d entry credits paid, at least %d are required", credits, min)
+		case credits > max:
+			result.add(DryRunMalformed, "", "%d entry credits paid, at most %d are allowed", credits, max)
+		}
+		if sigErr != nil {
+			result.add(DryRunBadSignature, "", "%v", sigErr)
+		}
+		if len(result.Reasons) == 0 {
+			result.add(DryRunMalformed, "", "refused by the commit validation")
+		}
+		return result
+	default:
+		if dry.short == ecAddress {
+			result.add(DryRunInsufficientBalance, primitives.ConvertECAddressToUserStr(factoid.NewAddress(ecAddress[:])),
+				"%d entry credits paid, balance is %d", credits, s.FactoidState.GetECBalance(ecAddress))
+		} else {
+			result.add(DryRunMalformed, "", "refused by the commit validation")
+		}
+	}
+
+	if !s.IsHighestCommit(entryHash, m) {
+		result.add(DryRunDuplicate, "", "a commit with equal or greater payment already exists for entry %s", entryHash.String())
+		return result
+	}
+	s.dryRunReplay(result, m)
+	return result
+}
+
+// dryRunReplay checks the replay filters the way the network inputs do, without adding the hash to them
+func (s *State) dryRunReplay(result *DryRunResult, m interfaces.IMsg) {
+	hash := m.GetRepeatHash().Fixed()
+	timestamp := m.GetTimestamp()
+	now := s.GetTimestamp()
+
+	if _, ok := s.Replay.Valid(constants.TIME_TEST, hash, timestamp, now); !ok {
+		result.add(DryRunTimestamp, "", "timestamp %s is too far from the time of this node %s", timestamp.String(), now.String())
+		return
+	}
+	if _, ok := s.FReplay.Valid(constants.BLOCK_REPLAY, hash, timestamp, now); !ok {
+		result.add(DryRunDuplicate, "", "already recorded in a block")
+		return
+	}
+	if _, ok := s.Replay.Valid(constants.NETWORK_REPLAY|constants.INTERNAL_REPLAY, hash, timestamp, now); !ok {
+		result.add(DryRunDuplicate, "", "already submitted")
+	}
+}
@@ -69,6 +69,9 @@ func (s *State) WriteEntries() {
 
 	for {
 		entry := <-s.WriteEntry
+		if entry != nil && !s.IsChainKept(entry.GetChainIDHash()) {
+			continue // Chain subset node, and we don't keep this chain
+		}
 		if entry != nil && !has(s, entry.GetHash()) {
 			err := s.DB.InsertEntry(entry)
 			if err != nil {
@@ -91,7 +94,7 @@ func (s *State) SendManager() {
 
 	for {
 		missingData := <-es.SendRequest
-		now := time.Now()
+		now := s.GetClock().Now()
 		tenSeconds := s.FactomSecond() * 10
 
 		// Every 1000 messages or so, purge our hash map.
@@ -136,7 +139,7 @@ func (s *State) RequestAndCollectMissingEntries() {
 		case <-es.finishedEntries:
 			es.EntriesProcessing--
 		default:
-			time.Sleep(1 * time.Second)
+			s.GetClock().Sleep(1 * time.Second)
 		}
 
 		// Update es.Processing (which tracks what directory block we are working on) and the state variables
@@ -228,7 +231,7 @@ func (s *State) ProcessDBlock(finishedDBlocks chan int, finishedEntries chan int
 // Start up all of our supporting go routines, and run through the directory blocks and make sure we have
 // all the entries they reference.
 func (s *State) GoSyncEntries() {
-	time.Sleep(5 * time.Second)
+	s.GetClock().Sleep(5 * time.Second)
 	s.EntrySyncState = new(EntrySync)
 	s.EntrySyncState.Init() // Initialize our processes
 
@@ -253,7 +256,7 @@ func (s *State) GoSyncEntries() {
 
 		// Sleep often if we are caught up (to the best of our knowledge)
 		if entryScanLimit == highestChecked {
-			time.Sleep(time.Second)
+			s.GetClock().Sleep(time.Second)
 		}
 
 		for scan := highestChecked + 1; scan <= entryScanLimit; scan++ {
@@ -262,20 +265,21 @@ func (s *State) GoSyncEntries() {
 
 			// Wait for the database if we have to
 			for db == nil {
-				time.Sleep(1 * time.Second)
+				s.GetClock().Sleep(1 * time.Second)
 				db = s.GetDirectoryBlockByHeight(scan)
 			}
 
 			// If loading from the database, then give it a bit of preference by sleeping a bit
 			if !s.DBFinished {
-				time.Sleep(1 * time.Millisecond)
+				s.GetClock().Sleep(1 * time.Millisecond)
 			}
 
 			// Run through all the entry blocks and entries in each directory block.
 			// If any entries are missing, collect them.  Then stuff them into the MissingDBlockEntries channel to
 			// collect from the network.
 			var entries []interfaces.IHash
-			for _, ebKeyMR := range db.GetEntryHashes()[3:] {
+			for _, ebEntry := range db.GetEBlockDBEntries() {
+				ebKeyMR := ebEntry.GetKeyMR()
 				eBlock, err := s.DB.FetchEBlock(ebKeyMR)
 				if err != nil {
 					panic(err)
@@ -287,10 +291,14 @@ func (s *State) GoSyncEntries() {
 				// Don't have an eBlock?  Huh. We can go on, but we can't advance.  We just wait until it
 				// does show up.
 				for eBlock == nil {
-					time.Sleep(1 * time.Second)
+					s.GetClock().Sleep(1 * time.Second)
 					eBlock, _ = s.DB.FetchEBlock(ebKeyMR)
 				}
 
+				// On a chain subset node we still clear the pending commits, but we don't go looking
+				// for entries of chains we don't keep.
+				kept := s.IsChainKept(ebEntry.GetChainID())
+
 				hashes := eBlock.GetEntryHashes()
 				s.EntrySyncState.TotalEntries += len(hashes)
 				for _, entryHash := range hashes {
@@ -307,7 +315,7 @@ func (s *State) GoSyncEntries() {
 					// MakeMissingEntryRequests()
 					// This go routine checks every so often to see if we have any missing entries or entry blocks.  It then requests
 					// them if it finds entries in the missing lists.
-					if !has(s, entryHash) {
+					if kept && !has(s, entryHash) {
 						entries = append(entries, entryHash)
 					}
 				}
@@ -7,7 +7,6 @@ package state
 import (
 	"encoding/binary"
 	"fmt"
-	"time"
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
@@ -86,7 +85,7 @@ func markFault(pl *ProcessList, vmIndex int, faultReason int) {
 		return
 	}
 
-	now := time.Now().Unix()
+	now := pl.State.GetClock().Now().Unix()
 	vm := pl.VMs[vmIndex]
 
 	if vm.WhenFaulted == 0 {
@@ -129,7 +128,7 @@ func markNoFault(pl *ProcessList, vmIndex int) {
 }
 
 func FaultCheck(pl *ProcessList) {
-	now := time.Now().Unix()
+	now := pl.State.GetClock().Now().Unix()
 
 	for i := 0; i < len(pl.FedServers); i++ {
 		if i == pl.State.LeaderVMIndex {
@@ -0,0 +1,357 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Grants beyond the hard coded ones can come from a grant file, checked against a sha256 checksum in
+// the config, or from a grant chain holding grant lists signed by the keys in the config.  Every grant
+// must be payable at a descriptor height (Height % COINBASE_PAYOUT_FREQUENCY == 1).  The lists are taken
+// in order, hard coded first; a list giving other grants for a height an earlier list already pays at is
+// dropped entirely, so nodes never pay a grant that isn't agreed.
+
+// GrantEntry is a grant as written in a grant list
+type GrantEntry struct {
+	Height  uint32 `json:"height"`  // Descriptor height it is paid at
+	Amount  uint64 `json:"amount"`  // Factoshis
+	Address string `json:"address"` // FA... address
+}
+
+// A GrantSource supplies grant lists beyond the hard coded grants
+type GrantSource interface {
+	// Name of the source, for logs
+	Name() string
+	// GrantLists returns the lists of grants that may be paid at dbheight, in the order they were made
+	GrantLists(s *State, dbheight uint32) ([][]HardGrant, error)
+}
+
+// checkGrantHeight applies the rules GetGrantPayoutsFor pays grants under
+func checkGrantHeight(h uint32) error {
+	if h <= constants.COINBASE_ACTIVATION {
+		return fmt.Errorf("grant height %d is not after the coinbase activation %d", h, constants.COINBASE_ACTIVATION)
+	}
+	if h%constants.COINBASE_PAYOUT_FREQUENCY != 1 {
+		return fmt.Errorf("grant height %d is not a descriptor height (height %% %d == 1)", h, constants.COINBASE_PAYOUT_FREQUENCY)
+	}
+	return nil
+}
+
+// ParseGrantList reads a JSON list of GrantEntry, checking every address and height
+func ParseGrantList(data []byte) ([]HardGrant, error) {
+	var entries []GrantEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	grants := make([]HardGrant, 0, len(entries))
+	for i, e := range entries {
+		if err := checkGrantHeight(e.Height); err != nil {
+			return nil, fmt.Errorf("grant %d: %v", i, err)
+		}
+		if e.Amount == 0 {
+			return nil, fmt.Errorf("grant %d pays nothing", i)
+		}
+		addr, err := checkGrantAddress(e.Address)
+		if err != nil {
+			return nil, fmt.Errorf("grant %d: %v", i, err)
+		}
+		grants = append(grants, HardGrant{e.Height, e.Amount, addr})
+	}
+	return grants, nil
+}
+
+// GrantFileSource reads grants from a file that matched its checksum when it was opened
+type GrantFileSource struct {
+	Path string
+	data []byte
+}
+
+var _ GrantSource = (*GrantFileSource)(nil)
+
+// NewGrantFileSource reads a grant file, which must have the sha256 checksum given in hex
+func NewGrantFileSource(path string, checksum string) (*GrantFileSource, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), strings.TrimSpace(checksum)) {
+		return nil, fmt.Errorf("checksum %x of %s does not match GrantFileChecksum", sum, path)
+	}
+	return &GrantFileSource{Path: path, data: data}, nil
+}
+
+func (f *GrantFileSource) Name() string {
+	return "grant file " + f.Path
+}
+
+// GrantLists parses the file each time, as the payout frequency is only known once the network is set
+func (f *GrantFileSource) GrantLists(s *State, dbheight uint32) ([][]HardGrant, error) {
+	grants, err := ParseGrantList(f.data)
+	if err != nil {
+		return nil, err
+	}
+	return [][]HardGrant{grants}, nil
+}
+
+// GrantChainSource reads signed grant lists from the entries of a chain.  An entry is a grant list if its
+// first external ID is "grants", its second the public key signing it and its third the signature of the
+// content, a JSON list of GrantEntry.  Only entries in blocks at least COINBASE_PAYOUT_FREQUENCY blocks
+// before a payout are used for it, so every node has them.
+type GrantChainSource struct {
+	ChainID interfaces.IHash
+	Keys    []string // Hex public keys grant lists may be signed by
+}
+
+var _ GrantSource = (*GrantChainSource)(nil)
+
+// GrantExtID marks the entries of a grant chain holding grant lists
+const GrantExtID = "grants"
+
+func (c *GrantChainSource) Name() string {
+	return "grant chain " + c.ChainID.String()
+}
+
+// GrantLists rebuilds the grant lists from the whole chain, so it fails on a node booted from a portable
+// fastboot, which doesn't have the blocks below the import height; it would pay out different grants.
+func (c *GrantChainSource) GrantLists(s *State, dbheight uint32) ([][]HardGrant, error) {
+	if s.FastBootImportHeight != 0 {
+		return nil, fmt.Errorf("the grant chain below the fastboot import height %d is missing", s.FastBootImportHeight)
+	}
+	eblocks, err := s.DB.FetchAllEBlocksByChain(c.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(eblocks, func(i, j int) bool {
+		return eblocks[i].GetHeader().GetEBSequence() < eblocks[j].GetHeader().GetEBSequence()
+	})
+
+	var lists [][]HardGrant
+	for _, eb := range eblocks {
+		if eb.GetHeader().GetDBHeight()+constants.COINBASE_PAYOUT_FREQUENCY > dbheight {
+			break
+		}
+		for _, hash := range eb.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			entry, err := s.DB.FetchEntry(hash)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				return nil, fmt.Errorf("grant chain entry %x is missing", hash.Bytes()[:4])
+			}
+			grants, err := c.grantList(entry)
+			if err != nil {
+				s.LogPrintf("grants", "Ignoring grant chain entry %x: %v", hash.Bytes()[:4], err)
+				continue
+			}
+			if grants != nil {
+				lists = append(lists, grants)
+			}
+		}
+	}
+	return lists, nil
+}
+
+// grantList returns the grants of an entry, nil if it isn't a grant list
+func (c *GrantChainSource) grantList(entry interfaces.IEBEntry) ([]HardGrant, error) {
+	ids := entry.ExternalIDs()
+	if len(ids) < 3 || !bytes.Equal(ids[0], []byte(GrantExtID)) {
+		return nil, nil
+	}
+	signer := hex.EncodeToString(ids[1])
+	trusted := false
+	for _, k := range c.Keys {
+		if strings.EqualFold(strings.TrimSpace(k), signer) {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("signed by %s, which is not a grant key", signer)
+	}
+	if err := primitives.VerifySignature(entry.GetContent(), ids[1], ids[2]); err != nil {
+		return nil, err
+	}
+	return ParseGrantList(entry.GetContent())
+}
+
+// SignGrantList returns the external IDs of a grant chain entry with the grant list as its content
+func SignGrantList(content []byte, key *primitives.PrivateKey) [][]byte {
+	sig := key.Sign(content)
+	return [][]byte{[]byte(GrantExtID), key.Public(), sig.Bytes()}
+}
+
+// grantKey identifies a grant within a height
+func grantKey(g HardGrant) string {
+	return fmt.Sprintf("%d %s", g.Amount, g.Address.String())
+}
+
+// grantsByHeight groups grants by height, with each height's grants in a canonical order
+func grantsByHeight(grants []HardGrant) map[uint32][]HardGrant {
+	rval := make(map[uint32][]HardGrant)
+	for _, g := range grants {
+		rval[g.DBh] = append(rval[g.DBh], g)
+	}
+	for _, list := range rval {
+		sort.Slice(list, func(i, j int) bool { return grantKey(list[i]) < grantKey(list[j]) })
+	}
+	return rval
+}
+
+func sameGrants(a, b []HardGrant) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if grantKey(a[i]) != grantKey(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeGrants adds the grant lists to the base grants in order, dropping any list that gives other grants
+// for a height already paid at.  The dropped lists are returned as errors.
+func MergeGrants(base []HardGrant, lists [][]HardGrant) ([]HardGrant, []error) {
+	merged := append([]HardGrant{}, base...)
+	var errs []error
+	for i, list := range lists {
+		have := grantsByHeight(merged)
+		add := grantsByHeight(list)
+		var disagree []uint32
+		for h, grants := range add {
+			if existing, ok := have[h]; ok && !sameGrants(existing, grants) {
+				disagree = append(disagree, h)
+			}
+		}
+		if len(disagree) > 0 {
+			sort.Slice(disagree, func(i, j int) bool { return disagree[i] < disagree[j] })
+			errs = append(errs, fmt.Errorf("grant list %d disagrees about the grants at %v", i, disagree))
+			continue
+		}
+		for _, g := range list {
+			if _, ok := have[g.DBh]; !ok { // Lists repeating grants already agreed add nothing
+				merged = append(merged, g)
+			}
+		}
+	}
+	return merged, errs
+}
+
+// GetGrantPayouts returns the coinbase payouts of the hard coded grants and those of the grant source
+// that are due at this height.  If the source can't be read there are no payouts, only the error; paying
+// the hard coded grants alone would build a descriptor the rest of the network doesn't.
+func (s *State) GetGrantPayouts(currentDBHeight uint32) ([]interfaces.ITransAddress, error) {
+	if s.GrantSource == nil {
+		return GetGrantPayoutsFor(currentDBHeight), nil
+	}
+	lists, err := s.GrantSource.GrantLists(s, currentDBHeight)
+	if err != nil {
+		s.LogPrintf("grants", "No grants from the %s at %d: %v", s.GrantSource.Name(), currentDBHeight, err)
+		packageLogger.WithField("dbheight", currentDBHeight).WithError(err).Errorf("No grants from the %s", s.GrantSource.Name())
+		return nil, fmt.Errorf("no grants from the %s at %d: %v", s.GrantSource.Name(), currentDBHeight, err)
+	}
+	grants, errs := MergeGrants(GetHardCodedGrants(), lists)
+	for _, err := range errs {
+		s.LogPrintf("grants", "Dropped a list of the %s: %v", s.GrantSource.Name(), err)
+		packageLogger.WithField("dbheight", currentDBHeight).WithError(err).Errorf("Dropped a list of the %s", s.GrantSource.Name())
+	}
+
+	outputs := make([]interfaces.ITransAddress, 0)
+	for _, g := range grants {
+		if g.DBh == currentDBHeight {
+			outputs = append(outputs, factoid.NewOutAddress(g.Address, g.Amount))
+		}
+	}
+	return outputs, nil
+}
+
+// NewGrantSource makes the grant source of the config, nil if none is configured
+func NewGrantSource(file, checksum, chainID, keys string) (GrantSource, error) {
+	switch {
+	case file != "" && chainID != "":
+		return nil, errors.New("configure either a GrantFile or a GrantChainID, not both")
+	case file != "":
+		if checksum == "" {
+			return nil, errors.New("a GrantFile needs its GrantFileChecksum")
+		}
+		source, err := NewGrantFileSource(file, checksum)
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	case chainID != "":
+		id, err := primitives.HexToHash(chainID)
+		if err != nil {
+			return nil, fmt.Errorf("bad GrantChainID: %v", err)
+		}
+		if keys == "" {
+			return nil, errors.New("a GrantChainID needs the GrantChainKeys its lists are signed by")
+		}
+		return &GrantChainSource{ChainID: id, Keys: strings.Split(keys, ",")}, nil
+	}
+	return nil, nil
+}
+
+// GrantChange is a difference between two grant lists at one height
+type GrantChange struct {
+	Height  uint32
+	Removed []HardGrant // Paid by the current list only
+	Added   []HardGrant // Paid by the proposed list only
+}
+
+// DiffGrants compares a proposed grant list with the current one, height by height
+func DiffGrants(current, proposed []HardGrant) []GrantChange {
+	cur := grantsByHeight(current)
+	prop := grantsByHeight(proposed)
+	heights := make(map[uint32]bool)
+	for h := range cur {
+		heights[h] = true
+	}
+	for h := range prop {
+		heights[h] = true
+	}
+
+	var changes []GrantChange
+	for h := range heights {
+		removed, added := diffGrants(cur[h], prop[h])
+		if len(removed) > 0 || len(added) > 0 {
+			changes = append(changes, GrantChange{h, removed, added})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Height < changes[j].Height })
+	return changes
+}
+
+// diffGrants returns the grants only in a and only in b, for lists in the order of grantsByHeight
+func diffGrants(a, b []HardGrant) (onlyA, onlyB []HardGrant) {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && grantKey(a[i]) < grantKey(b[j])):
+			onlyA = append(onlyA, a[i])
+			i++
+		case i == len(a) || grantKey(b[j]) < grantKey(a[i]):
+			onlyB = append(onlyB, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return
+}
@@ -0,0 +1,213 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/constants/runstate"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+const (
+	clay = "FA3oajkmHMfqkNMMShmqpwDThzMCuVrSsBwiXM2kYFVRz3MzxNAJ"
+	bob  = "FA3Ga2XcaheS5NgQ3q22gBpLgE6tXmPu1GhjdU2FsdN2QPMzKJET"
+)
+
+func TestParseGrantList(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	grants, err := ParseGrantList([]byte(`[{"height": 61, "amount": 5, "address": "` + clay + `"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grants) != 1 || grants[0].DBh != 61 || grants[0].Amount != 5 || !grants[0].Address.IsSameAs(validateAddress(clay)) {
+		t.Errorf("Wrong grants %v", grants)
+	}
+
+	for _, bad := range []string{
+		`[{"height": 60, "amount": 5, "address": "` + clay + `"}]`, // Not a descriptor height
+		`[{"height": 61, "amount": 0, "address": "` + clay + `"}]`,
+		`[{"height": 61, "amount": 5, "address": "FA3oajkmHMfqkNMMShmqpwDThzMCuVrSsBwiXM2kYFVRz3MzxNAK"}]`,
+		`{"height": 61}`,
+	} {
+		if _, err := ParseGrantList([]byte(bad)); err == nil {
+			t.Errorf("Accepted %s", bad)
+		}
+	}
+}
+
+func TestMergeGrants(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	hardcoded := GetHardCodedGrants()
+	agrees := []HardGrant{{41, 2, validateAddress(clay)}, {41, 3, validateAddress(bob)}, {61, 5, validateAddress(clay)}}
+	disagrees := []HardGrant{{41, 7, validateAddress(clay)}, {71, 5, validateAddress(bob)}}
+
+	merged, errs := MergeGrants(hardcoded, [][]HardGrant{agrees, disagrees})
+	if len(errs) != 1 {
+		t.Errorf("Expected the second list to be dropped, got %v", errs)
+	}
+	if len(merged) != len(hardcoded)+1 {
+		t.Fatalf("Expected one grant to be added, got %d grants", len(merged))
+	}
+	for _, g := range merged {
+		if g.DBh == 71 || g.Amount == 7 {
+			t.Errorf("Paid %v from a list that disagrees", g)
+		}
+	}
+
+	changes := DiffGrants(hardcoded, merged)
+	if len(changes) != 1 || changes[0].Height != 61 || len(changes[0].Added) != 1 || len(changes[0].Removed) != 0 {
+		t.Errorf("Wrong changes %v", changes)
+	}
+	changes = DiffGrants(hardcoded, disagrees)
+	if len(changes) != 3 {
+		t.Errorf("Expected changes at 41, 51 and 71, got %v", changes)
+	}
+}
+
+func TestGrantFileSource(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	data := []byte(`[{"height": 61, "amount": 5, "address": "` + bob + `"}]`)
+	f, err := ioutil.TempFile("", "grants")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(data)
+	f.Close()
+
+	sum := sha256.Sum256(data)
+	source, err := NewGrantSource(f.Name(), hex.EncodeToString(sum[:]), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := new(State)
+	s.GrantSource = source
+	if payouts, err := s.GetGrantPayouts(61); err != nil || len(payouts) != 1 || payouts[0].GetAmount() != 5 {
+		t.Errorf("Wrong payouts at 61 %v %v", payouts, err)
+	}
+	if payouts, err := s.GetGrantPayouts(41); err != nil || len(payouts) != 2 {
+		t.Errorf("Expected the hard coded grants at 41, got %v %v", payouts, err)
+	}
+
+	if _, err := NewGrantSource(f.Name(), hex.EncodeToString(make([]byte, 32)), "", ""); err == nil {
+		t.Error("Accepted a grant file with the wrong checksum")
+	}
+}
+
+func TestGrantChainEntry(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	key := primitives.RandomPrivateKey()
+	content := []byte(`[{"height": 61, "amount": 5, "address": "` + bob + `"}]`)
+	entry := entryBlock.NewEntry()
+	entry.Content = primitives.ByteSlice{Bytes: content}
+	for _, id := range SignGrantList(content, key) {
+		entry.ExtIDs = append(entry.ExtIDs, primitives.ByteSlice{Bytes: id})
+	}
+
+	source := &GrantChainSource{Keys: []string{key.PublicKeyString()}}
+	grants, err := source.grantList(entry)
+	if err != nil || len(grants) != 1 {
+		t.Fatalf("Expected the signed list, got %v %v", grants, err)
+	}
+
+	source.Keys = []string{primitives.RandomPrivateKey().PublicKeyString()}
+	if _, err := source.grantList(entry); err == nil {
+		t.Error("Accepted a list signed by a key that isn't a grant key")
+	}
+
+	source.Keys = []string{key.PublicKeyString()}
+	entry.Content = primitives.ByteSlice{Bytes: []byte(`[{"height": 61, "amount": 500, "address": "` + bob + `"}]`)}
+	if _, err := source.grantList(entry); err == nil {
+		t.Error("Accepted a list changed after it was signed")
+	}
+}
+
+type unreadableSource struct{}
+
+func (unreadableSource) Name() string { return "unreadable source" }
+
+func (unreadableSource) GrantLists(s *State, dbheight uint32) ([][]HardGrant, error) {
+	return nil, errors.New("grant chain not synced")
+}
+
+func TestGrantPayoutsFailClosed(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	// The hard coded grants alone would make a different descriptor than the rest of the network's
+	s := new(State)
+	s.GrantSource = unreadableSource{}
+	if payouts, err := s.GetGrantPayouts(41); err == nil || len(payouts) != 0 {
+		t.Errorf("Expected an error and no payouts, got %v %v", payouts, err)
+	}
+
+	// A node booted from a portable fastboot doesn't have the grant chain below the import height
+	s.GrantSource = &GrantChainSource{ChainID: primitives.Sha([]byte("grants"))}
+	s.FastBootImportHeight = 100
+	if payouts, err := s.GetGrantPayouts(141); err == nil || len(payouts) != 0 {
+		t.Errorf("Expected an error and no payouts from an imported node, got %v %v", payouts, err)
+	}
+}
+
+func TestLoadConfigGrantSource(t *testing.T) {
+	write := func(config string) string {
+		f, err := ioutil.TempFile("", "factomd.conf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString("[app]\n" + config)
+		f.Close()
+		return f.Name()
+	}
+	none := write("")
+	defer os.Remove(none)
+	bad := write("GrantFile = grants.json\n")
+	defer os.Remove(bad)
+	chain := write("GrantChainID = " + primitives.Sha([]byte("grants")).String() + "\nGrantChainKeys = " +
+		primitives.RandomPrivateKey().PublicKeyString() + "\nPruneRetention = 10\n")
+	defer os.Remove(chain)
+
+	s := new(State)
+	if err := s.LoadConfig(bad, "LOCAL"); err == nil {
+		t.Error("Loaded a grant file without its checksum")
+	}
+
+	s = new(State)
+	if err := s.LoadConfig(none, "LOCAL"); err != nil {
+		t.Fatal(err)
+	}
+	s.RunState = runstate.Running
+	if err := s.LoadConfig(chain, "LOCAL"); err == nil || s.GrantSource != nil {
+		t.Errorf("Changed the grant source of a running node to %v", s.GrantSource)
+	}
+	if s.PruneRetention != 0 || s.ConfigFilePath != none {
+		t.Error("The rejected reload applied part of the config")
+	}
+	if err := s.LoadConfig(none, "LOCAL"); err != nil {
+		t.Errorf("Reloading the same grant source failed: %v", err)
+	}
+
+	s = new(State)
+	if err := s.LoadConfig(chain, "LOCAL"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetKeepEntryChains(primitives.Sha([]byte("other")).String())
+	if source, ok := s.GrantSource.(*GrantChainSource); !ok || !s.IsChainKept(source.ChainID) {
+		t.Error("A chain subset node must keep the grant chain")
+	}
+}
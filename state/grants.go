@@ -1224,10 +1224,19 @@ func GetHardCodedGrants() []HardGrant {
 }
 
 func validateAddress(a string) interfaces.IAddress {
+	addr, err := checkGrantAddress(a)
+	if err != nil {
+		panic(err.Error())
+	}
+	return addr
+}
+
+// checkGrantAddress is validateAddress for grants from a grant source, where a bad address is an error
+func checkGrantAddress(a string) (interfaces.IAddress, error) {
 	if !primitives.ValidateFUserStr(a) {
-		panic(fmt.Sprintf("Bad addr(%s) in grant table", a))
+		return nil, fmt.Errorf("Bad addr(%s) in grant table", a)
 	}
-	return factoid.NewAddress(primitives.ConvertUserStrToAddress(a))
+	return factoid.NewAddress(primitives.ConvertUserStrToAddress(a)), nil
 }
 
 func CheckGrants() {
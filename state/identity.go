@@ -121,7 +121,7 @@ type IdentityEntry struct {
 // in an identity that corresponds to an authority. If initial is true, then calling ProcessIdentityEntry directly will
 // have the same result.
 func (st *State) LoadIdentityByEntry(ent interfaces.IEBEntry, height uint32, dblockTimestamp interfaces.Timestamp, d *DBState) {
-	flog := identLogger.WithFields(st.Logger.Data).WithField("func", "LoadIdentityByEntry")
+	flog := identLogger.WithFields(st.LogFields()).WithField("func", "LoadIdentityByEntry")
 	if ent == nil {
 		return
 	}
@@ -151,7 +151,7 @@ func (st *State) LoadIdentityByEntry(ent interfaces.IEBEntry, height uint32, dbl
 
 // Called by AddServer Message
 func ProcessIdentityToAdminBlock(st *State, chainID interfaces.IHash, servertype int) bool {
-	flog := identLogger.WithFields(st.Logger.Data).WithField("func", "ProcessIdentityToAdminBlock")
+	flog := identLogger.WithFields(st.LogFields()).WithField("func", "ProcessIdentityToAdminBlock")
 
 	err := st.AddIdentityFromChainID(chainID)
 	if err != nil {
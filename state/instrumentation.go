@@ -281,6 +281,80 @@ var (
 			"If there is a delay, it means the ack+msg would benefit from being " +
 			"coupled. The delay is measured in seconds.",
 	}, []string{"leader"})
+
+	//		Anchoring
+	AnchorLatestHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_anchor_latest_height",
+		Help: "Highest directory block anchored on the ledger.",
+	}, []string{"ledger"})
+
+	AnchorBlocksBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_anchor_blocks_behind",
+		Help: "Number of saved directory blocks above the highest anchored block of the ledger.",
+	}, []string{"ledger"})
+
+	AnchorStalled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_anchor_stalled",
+		Help: "1 if the ledger is more than AnchorStallThreshold blocks behind, 0 otherwise.",
+	}, []string{"ledger"})
+
+	// Rejected transactions, commits and reveals
+	MsgRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_msg_rejections_total",
+		Help: "Factoid transactions, commits and reveals dropped, by reason code.",
+	}, []string{"code"})
+
+	//		Consensus health
+	ConsensusVMListHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_vm_list_height",
+		Help: "Number of messages processed in each VM of the leader process list.",
+	}, []string{"vm"})
+
+	ConsensusVMListLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_vm_list_length",
+		Help: "Number of messages in each VM of the leader process list, processed or not.",
+	}, []string{"vm"})
+
+	ConsensusMinutesSinceEOM = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_minutes_since_eom",
+		Help: "Minutes since the last EOM of each leader was processed.",
+	}, []string{"leader"})
+
+	ConsensusEOMInterval = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "factomd_state_consensus_eom_interval_seconds",
+		Help:    "Time between the EOMs of a leader.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+
+	ConsensusElectionActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_election_active",
+		Help: "1 while an election is running, 0 otherwise.",
+	})
+
+	ConsensusElectionRound = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_election_round",
+		Help: "Round of the running election, 0 if there is none.",
+	})
+
+	ConsensusFaultedServers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_faulted_servers",
+		Help: "Number of federated servers whose VM is faulted.",
+	})
+
+	ConsensusDBSigAgreement = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_consensus_dbsig_agreement_ratio",
+		Help: "Fraction of the federated servers whose DBSig of the last block matched ours.",
+	})
+
+	ConsensusDBSigMismatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_consensus_dbsig_mismatches_total",
+		Help: "DBSigs whose directory block did not match ours.",
+	})
+
+	ConsensusBalanceHashMismatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_consensus_balance_hash_mismatches_total",
+		Help: "DBSig acks whose balance hash did not match ours.",
+	})
 )
 
 var registered bool = false
@@ -376,4 +450,22 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(LeaderSyncMsgDelay)
 	prometheus.MustRegister(LeaderSyncAckDelay)
 	prometheus.MustRegister(LeaderSyncAckPairDelay)
+
+	// Anchoring
+	prometheus.MustRegister(AnchorLatestHeight)
+	prometheus.MustRegister(AnchorBlocksBehind)
+	prometheus.MustRegister(AnchorStalled)
+	prometheus.MustRegister(MsgRejections)
+
+	// Consensus health
+	prometheus.MustRegister(ConsensusVMListHeight)
+	prometheus.MustRegister(ConsensusVMListLength)
+	prometheus.MustRegister(ConsensusMinutesSinceEOM)
+	prometheus.MustRegister(ConsensusEOMInterval)
+	prometheus.MustRegister(ConsensusElectionActive)
+	prometheus.MustRegister(ConsensusElectionRound)
+	prometheus.MustRegister(ConsensusFaultedServers)
+	prometheus.MustRegister(ConsensusDBSigAgreement)
+	prometheus.MustRegister(ConsensusDBSigMismatches)
+	prometheus.MustRegister(ConsensusBalanceHashMismatches)
 }
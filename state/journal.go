@@ -0,0 +1,327 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The journal (-journaling) holds one JSON JournalEntry per line for each message the node queued, with
+// when and from where it arrived, the queue it entered and the node clock at the time.  Replaying it with
+// the node clock set from the entries reproduces what the node saw, and ReplayJournal does so without the
+// validator loop, so a captured incident replays the same way every time, e.g. in a unit test.
+// Journals of the first version, "MsgHex: <hex>" lines, can still be read, without the timing.
+
+const JournalVersion = 2
+
+// The queues a journalled message can have entered
+const (
+	JournalInMsgQueue  = "InMsgQueue"  // Fast track from peers and the API
+	JournalInMsgQueue2 = "InMsgQueue2" // Slow track, commits and reveals
+	JournalDataQueue   = "DataQueue"   // Missing data responses
+	JournalMissingMsg  = "MissingMsg"  // Missing message requests, handed to the MMR
+	JournalTimer       = "Timer"       // EOMs the node made as its minute timer fired
+)
+
+// JournalEntry is a line of the journal
+type JournalEntry struct {
+	Version       int    `json:"version"`
+	Received      int64  `json:"received"` // Unix nanoseconds the message was received, zero if not from a peer
+	Clock         int64  `json:"clock"`    // Node clock (GetTimestamp) in milliseconds as the message was queued
+	Queue         string `json:"queue"`
+	Origin        int    `json:"origin"` // Peer the message came from, one based; zero if from this node
+	NetworkOrigin string `json:"networkorigin"`
+	Local         bool   `json:"local"`
+	Network       bool   `json:"network"`
+	Peer2Peer     bool   `json:"peer2peer"`
+	NoResend      bool   `json:"noresend"`
+	Type          byte   `json:"type"`
+	Hash          string `json:"hash"`    // Message hash, to find messages in the journal
+	Message       string `json:"message"` // Hex of the marshalled message
+}
+
+// NewJournalEntry records a message as it enters a queue at the given node clock
+func NewJournalEntry(msg interfaces.IMsg, queue string, clock interfaces.Timestamp) (*JournalEntry, error) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e := new(JournalEntry)
+	e.Version = JournalVersion
+	if t := msg.GetReceivedTime(); !t.IsZero() {
+		e.Received = t.UnixNano()
+	}
+	if clock != nil {
+		e.Clock = clock.GetTimeMilli()
+	}
+	e.Queue = queue
+	e.Origin = msg.GetOrigin()
+	e.NetworkOrigin = msg.GetNetworkOrigin()
+	e.Local = msg.IsLocal()
+	e.Network = msg.IsNetwork()
+	e.Peer2Peer = msg.IsPeer2Peer()
+	e.NoResend = msg.GetNoResend()
+	e.Type = msg.Type()
+	if h := msg.GetMsgHash(); h != nil {
+		e.Hash = h.String()
+	}
+	e.Message = hex.EncodeToString(data)
+	return e, nil
+}
+
+// Msg unmarshals the message of an entry, with how it arrived as it was journalled
+func (e *JournalEntry) Msg() (interfaces.IMsg, error) {
+	if messages.General == nil {
+		return nil, errors.New("no message factory to unmarshal journalled messages with")
+	}
+	data, err := hex.DecodeString(e.Message)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := messages.General.UnmarshalMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	if e.Version < JournalVersion {
+		return msg, nil
+	}
+	if e.Received != 0 {
+		msg.SetReceivedTime(time.Unix(0, e.Received))
+	}
+	msg.SetOrigin(e.Origin)
+	msg.SetNetworkOrigin(e.NetworkOrigin)
+	msg.SetLocal(e.Local)
+	msg.SetNetwork(e.Network)
+	msg.SetPeer2Peer(e.Peer2Peer)
+	msg.SetNoResend(e.NoResend)
+	return msg, nil
+}
+
+// ParseJournalLine reads a line of a journal of either version, nil if it holds no message
+func ParseJournalLine(line []byte) (*JournalEntry, error) {
+	line = bytes.TrimSpace(line)
+	if bytes.HasPrefix(line, []byte("MsgHex:")) {
+		fields := bytes.Fields(line[len("MsgHex:"):])
+		if len(fields) == 0 {
+			return nil, nil
+		}
+		return &JournalEntry{Version: 1, Queue: JournalInMsgQueue, Message: string(fields[0])}, nil
+	}
+	if !bytes.HasPrefix(line, []byte("{")) {
+		return nil, nil
+	}
+	e := new(JournalEntry)
+	if err := json.Unmarshal(line, e); err != nil {
+		return nil, err
+	}
+	if e.Version < JournalVersion || e.Message == "" {
+		return nil, nil // The JSON of the first version can't be unmarshalled back into messages
+	}
+	return e, nil
+}
+
+// ReadJournal reads the entries of a journal
+func ReadJournal(r io.Reader) ([]*JournalEntry, error) {
+	var entries []*JournalEntry
+	reader := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			e, perr := ParseJournalLine(line)
+			if perr != nil {
+				return nil, fmt.Errorf("journal line %d: %v", n, perr)
+			}
+			if e != nil {
+				entries = append(entries, e)
+			}
+		}
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// JournalMessage writes the message to the message journal for debugging
+func (s *State) JournalMessage(msg interfaces.IMsg) {
+	s.JournalMessageTo(msg, "")
+}
+
+// JournalMessageTo writes the message to the journal as it enters a queue
+func (s *State) JournalMessageTo(msg interfaces.IMsg, queue string) {
+	if !s.Journaling || len(s.JournalFile) == 0 {
+		return
+	}
+	e, err := NewJournalEntry(msg, queue, s.GetTimestamp())
+	if err != nil {
+		return
+	}
+	p, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	s.journalMutex.Lock()
+	defer s.journalMutex.Unlock()
+	f, err := os.OpenFile(s.JournalFile, os.O_APPEND+os.O_WRONLY, 0666)
+	if err != nil {
+		s.JournalFile = ""
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, string(p))
+}
+
+// GetJournalMessages gets all messages from the message journal
+func (s *State) GetJournalMessages() [][]byte {
+	ret := make([][]byte, 0)
+	if !s.Journaling || len(s.JournalFile) == 0 {
+		return nil
+	}
+
+	s.journalMutex.Lock()
+	defer s.journalMutex.Unlock()
+	f, err := os.Open(s.JournalFile)
+	if err != nil {
+		s.JournalFile = ""
+		return nil
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		p, err := r.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// ReplayJournalEntry queues the message of an entry where it was queued when journalled.  The node clock
+// is set to the time it was journalled at as the message is executed, not as it is queued, since the
+// messages queued before it may run first.  The state must be replaying (SetIsReplaying).
+func (s *State) ReplayJournalEntry(e *JournalEntry) error {
+	msg, err := e.Msg()
+	if err != nil {
+		return err
+	}
+	if e.Clock != 0 && e.Queue != JournalDataQueue && e.Queue != JournalMissingMsg { // Those are never executed
+		s.queueReplayClock(msg, primitives.NewTimestampFromMilliseconds(uint64(e.Clock)))
+	}
+
+	switch e.Queue {
+	case JournalInMsgQueue2:
+		s.InMsgQueue2().Enqueue(msg)
+	case JournalDataQueue:
+		// Nothing drains the data queue while replaying, so the request is answered here, as the MissingData
+		// thread of the node would have
+		request, ok := msg.(*messages.MissingData)
+		if !ok {
+			return fmt.Errorf("a %s on the data queue", constants.MessageName(msg.Type()))
+		}
+		request.SendResponse(s)
+	case JournalMissingMsg:
+		if s.MissingMessageResponseHandler == nil {
+			return errors.New("no missing message handler to replay a missing message request to")
+		}
+		s.MissingMessageResponseHandler.NotifyPeerMissingMsg(msg)
+	case JournalTimer:
+		s.queueForExecution(msg)
+	default:
+		s.InMsgQueue().Enqueue(msg)
+	}
+	return nil
+}
+
+// queueReplayClock keeps the node clock a message was journalled at until it is executed
+func (s *State) queueReplayClock(msg interfaces.IMsg, clock interfaces.Timestamp) {
+	if msg.GetRepeatHash() == nil {
+		return
+	}
+	s.replayMutex.Lock()
+	defer s.replayMutex.Unlock()
+	if s.replayClocks == nil {
+		s.replayClocks = make(map[[32]byte][]interfaces.Timestamp)
+	}
+	hash := msg.GetRepeatHash().Fixed()
+	s.replayClocks[hash] = append(s.replayClocks[hash], clock)
+}
+
+// setReplayClock sets the node clock to the one the message executing was journalled at.  Messages not
+// from the journal, or executed again from holding, run on the clock of the last one.
+func (s *State) setReplayClock(hash [32]byte) {
+	if !s.IsReplaying {
+		return
+	}
+	s.replayMutex.Lock()
+	defer s.replayMutex.Unlock()
+	clocks := s.replayClocks[hash]
+	if len(clocks) == 0 {
+		return
+	}
+	s.ReplayTimestamp = clocks[0]
+	if len(clocks) == 1 {
+		delete(s.replayClocks, hash)
+	} else {
+		s.replayClocks[hash] = clocks[1:]
+	}
+}
+
+// ReplayJournal replays a journal without the validator loop or the wall clock.  Each message is queued
+// with the node clock it was journalled at, and the state works on it until it makes no more progress
+// before the next is queued, so a replay always runs the same way.
+func (s *State) ReplayJournal(entries []*JournalEntry) error {
+	s.SetIsReplaying()
+	defer s.SetIsDoneReplaying()
+
+	for i, e := range entries {
+		if err := s.ReplayJournalEntry(e); err != nil {
+			return fmt.Errorf("journal entry %d: %v", i, err)
+		}
+		s.settle()
+	}
+	return nil
+}
+
+// settle does the work of the validator loop until the state makes no more progress
+func (s *State) settle() {
+	for i := 0; i < 1000; i++ {
+		progress := false
+		if msg := s.inMsgQueue.Dequeue(); msg != nil {
+			s.queueForExecution(msg)
+			progress = true
+		} else if msg := s.inMsgQueue2.Dequeue(); msg != nil {
+			s.queueForExecution(msg)
+			progress = true
+		}
+		for j := 0; j < 20 && s.Process(); j++ {
+			progress = true
+		}
+		for j := 0; j < 20 && s.UpdateState(); j++ {
+			progress = true
+		}
+		if !progress {
+			return
+		}
+	}
+}
@@ -6,10 +6,15 @@ package state_test
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/messages/msgsupport"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
 	. "github.com/FactomProject/factomd/testHelper"
 )
 
@@ -37,3 +42,166 @@ func TestJournal(t *testing.T) {
 		t.Error("No messages returned from journal")
 	}
 }
+
+func TestJournalReplay(t *testing.T) {
+	messages.General = new(msgsupport.GeneralFactory)
+	primitives.General = messages.General
+
+	s := CreateEmptyTestState()
+	filename := "journalreplay.log"
+	defer os.Remove(filename)
+	if _, err := os.Create(filename); err != nil {
+		t.Fatal(err)
+	}
+	s.JournalFile = filename
+	s.Journaling = true
+
+	msg := CreateTestBlockCommitList()[0]
+	received := time.Unix(1500000000, 12345)
+	msg.SetReceivedTime(received)
+	msg.SetOrigin(3)
+	msg.SetNetwork(true)
+	s.JournalMessageTo(msg, state.JournalInMsgQueue2)
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := state.ReadJournal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 journal entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Version != state.JournalVersion || e.Queue != state.JournalInMsgQueue2 || e.Origin != 3 || !e.Network || e.Local || e.Clock == 0 {
+		t.Errorf("Wrong journal entry %+v", e)
+	}
+
+	replayed, err := e.Msg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replayed.GetMsgHash().IsSameAs(msg.GetMsgHash()) || replayed.GetOrigin() != 3 || !replayed.IsNetwork() || !replayed.GetReceivedTime().Equal(received) {
+		t.Errorf("The journalled message came back as %s", replayed.String())
+	}
+
+	// Replaying queues the message where it went, the clock is only set once it is executed
+	e.Clock = 1234567
+	s.SetIsReplaying()
+	defer s.SetIsDoneReplaying()
+	if err := s.ReplayJournalEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	if s.ReplayTimestamp != nil {
+		t.Errorf("The clock was set to %d as the message was queued", s.ReplayTimestamp.GetTimeMilli())
+	}
+	if s.InMsgQueue2().Length() != 1 {
+		t.Errorf("Expected the message on InMsgQueue2")
+	}
+}
+
+// timerEntry journals a local EOM made at the given node clock
+func timerEntry(t *testing.T, s *state.State, clock interfaces.Timestamp) *state.JournalEntry {
+	eom := new(messages.EOM)
+	eom.Timestamp = clock
+	eom.ChainID = s.GetIdentityChainID()
+	eom.Sign(s)
+	eom.SetLocal(true)
+	e, err := state.NewJournalEntry(eom, state.JournalTimer, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestJournalReplayClockAtExecution(t *testing.T) {
+	messages.General = new(msgsupport.GeneralFactory)
+	primitives.General = messages.General
+
+	s := CreateEmptyTestState()
+	s.IgnoreMissing = true // Drops messages over 15 minutes older than the node clock
+	invalid := len(s.NetworkInvalidMsgQueue())
+
+	// Both are queued before either runs, the first must still run on its own clock
+	first := primitives.NewTimestampFromMilliseconds(uint64(time.Now().Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)))
+	second := primitives.NewTimestampFromMilliseconds(first.GetTimeMilliUInt64() + uint64(time.Hour/time.Millisecond))
+	s.SetIsReplaying()
+	defer s.SetIsDoneReplaying()
+	for _, e := range []*state.JournalEntry{timerEntry(t, s, first), timerEntry(t, s, second)} {
+		if err := s.ReplayJournalEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Process()
+
+	if len(s.NetworkInvalidMsgQueue()) != invalid {
+		t.Error("The first EOM was executed on the clock of the second")
+	}
+	if s.ReplayTimestamp == nil || s.ReplayTimestamp.GetTimeMilli() != second.GetTimeMilli() {
+		t.Errorf("Expected the clock of the last message executed %d, got %v", second.GetTimeMilli(), s.ReplayTimestamp)
+	}
+}
+
+func TestReplayJournal(t *testing.T) {
+	messages.General = new(msgsupport.GeneralFactory)
+	primitives.General = messages.General
+
+	s := CreateEmptyTestState()
+	s.IgnoreMissing = true
+	invalid := len(s.NetworkInvalidMsgQueue())
+
+	clock := primitives.NewTimestampFromMilliseconds(uint64(time.Now().Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)))
+	request, err := state.NewJournalEntry(messages.NewMissingData(s, primitives.Sha([]byte("missing"))), state.JournalDataQueue, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing drains the data queue, the replay must not wait on it
+	entries := []*state.JournalEntry{timerEntry(t, s, clock)}
+	for i := 0; i < cap(s.DataMsgQueue())+1; i++ {
+		entries = append(entries, request)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.ReplayJournal(entries) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("The replay is blocked")
+	}
+
+	if len(s.NetworkInvalidMsgQueue()) != invalid {
+		t.Error("The EOM was not executed on its journalled clock")
+	}
+	if s.InMsgQueue().Length() != 0 || len(s.DataMsgQueue()) != 0 {
+		t.Error("The replay left messages queued")
+	}
+	if s.IsReplaying || s.ReplayTimestamp != nil {
+		t.Error("The replay left the node on the journal clock")
+	}
+}
+
+func TestReadJournalVersions(t *testing.T) {
+	journal := "MsgHex: 0a0b0c\n" +
+		`{"Type":1,"Message":{}}` + "\n" + // The first JSON journal can't be replayed
+		"some other line\n" +
+		`{"version":2,"clock":5,"queue":"InMsgQueue","message":"0d0e"}`
+	entries, err := state.ReadJournal(strings.NewReader(journal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 journal entries, got %d", len(entries))
+	}
+	if entries[0].Version != 1 || entries[0].Message != "0a0b0c" || entries[1].Clock != 5 || entries[1].Message != "0d0e" {
+		t.Errorf("Wrong journal entries %+v %+v", entries[0], entries[1])
+	}
+	if _, err := state.ReadJournal(strings.NewReader(`{"version":2,`)); err == nil {
+		t.Error("Read a broken journal line")
+	}
+}
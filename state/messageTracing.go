@@ -0,0 +1,25 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/tracing"
+)
+
+// TraceMessage moves a message on to the next stage of its trace.  Only the node with TraceMessages set
+// traces, as the nodes of a simulation see the same messages and would share their traces.
+func (s *State) TraceMessage(msg interfaces.IMsg, stage string) {
+	if s.TraceMessages {
+		tracing.Stage(msg, stage)
+	}
+}
+
+// DropMessageTrace ends the trace of a message that was thrown away, as TraceMessage only on the traced node
+func (s *State) DropMessageTrace(msg interfaces.IMsg, reason string) {
+	if s.TraceMessages {
+		tracing.Drop(msg, reason)
+	}
+}
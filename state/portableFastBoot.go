@@ -0,0 +1,294 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// A portable fastboot is a fastboot generation signed by the operator that saved it, so a new node can
+// start from it rather than replaying the chain from genesis.  The importer checks the signature against
+// the keys it trusts and the directory block of the save against a trusted checkpoint, writes the blocks
+// the save holds to its empty database, and then checks its balances against the balance hash on the
+// first leader ack it processes.  Blocks from before the save are not in the database of such a node, so
+// it can only ever boot from a fastboot; the height it was imported at is kept in the database to enforce it.
+
+type PortableFastBoot struct {
+	Generation []byte                // FastBootHeader, integrity hash and the marshalled DBStateList
+	Signature  *primitives.Signature // Signature of the Sha of Generation
+}
+
+// NewPortableFastBoot signs a fastboot generation
+func NewPortableFastBoot(generation []byte, key *primitives.PrivateKey) (*PortableFastBoot, error) {
+	p := new(PortableFastBoot)
+	p.Generation = generation
+	if _, err := p.Header(); err != nil {
+		return nil, err
+	}
+	sig, ok := key.Sign(primitives.Sha(generation).Bytes()).(*primitives.Signature)
+	if !ok {
+		return nil, errors.New("unexpected signature type")
+	}
+	p.Signature = sig
+	return p, nil
+}
+
+func (p *PortableFastBoot) Header() (*FastBootHeader, error) {
+	h := new(FastBootHeader)
+	if _, err := h.UnmarshalBinaryData(p.Generation); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (p *PortableFastBoot) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushBytes(p.Generation); err != nil {
+		return nil, err
+	}
+	if err := buf.PushBinaryMarshallable(p.Signature); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (p *PortableFastBoot) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
+	buf := primitives.NewBuffer(data)
+	if p.Generation, err = buf.PopBytes(); err != nil {
+		return nil, err
+	}
+	p.Signature = new(primitives.Signature)
+	if err = buf.PopBinaryMarshallable(p.Signature); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (p *PortableFastBoot) UnmarshalBinary(data []byte) error {
+	_, err := p.UnmarshalBinaryData(data)
+	return err
+}
+
+// Verify checks the save was signed by one of the trusted public keys (hex), and was saved at the
+// checkpoint.  A nil checkpoint only checks the signature.
+func (p *PortableFastBoot) Verify(trustedKeys []string, checkpointHeight uint32, checkpoint interfaces.IHash) (*FastBootHeader, error) {
+	if p.Signature == nil || !p.Signature.Verify(primitives.Sha(p.Generation).Bytes()) {
+		return nil, errors.New("the signature of the portable fastboot is not valid")
+	}
+	signer := fmt.Sprintf("%x", p.Signature.GetPubBytes())
+	trusted := false
+	for _, k := range trustedKeys {
+		if strings.EqualFold(strings.TrimSpace(k), signer) {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("the portable fastboot is signed by %s, which is not a trusted key", signer)
+	}
+
+	header, err := p.Header()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil && (header.DBHeight != checkpointHeight || !header.KeyMR.IsSameAs(checkpoint)) {
+		return nil, fmt.Errorf("the portable fastboot was saved at %d:%s, not at the checkpoint %d:%s",
+			header.DBHeight, header.KeyMR.String(), checkpointHeight, checkpoint.String())
+	}
+	return header, nil
+}
+
+// ParseFastBootCheckpoint parses a checkpoint written as height:keymr
+func ParseFastBootCheckpoint(checkpoint string) (uint32, interfaces.IHash, error) {
+	parts := strings.Split(strings.TrimSpace(checkpoint), ":")
+	if len(parts) != 2 {
+		return 0, nil, fmt.Errorf("checkpoint %q is not height:keymr", checkpoint)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, nil, fmt.Errorf("checkpoint %q has a bad height: %v", checkpoint, err)
+	}
+	keymr, err := primitives.HexToHash(parts[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("checkpoint %q has a bad keymr: %v", checkpoint, err)
+	}
+	return uint32(height), keymr, nil
+}
+
+// PortableFilename is the file the signed copy of the newest fastboot generation is written to
+func PortableFilename(networkName string, fileLocation string) string {
+	return strings.TrimSuffix(NetworkIDToFilename(networkName, fileLocation), ".db") + "_portable.db"
+}
+
+// savePortable writes a signed copy of a generation, when a signing key is configured
+func (sss *StateSaverStruct) savePortable(s *State, networkName string, generation []byte) error {
+	if sss.SigningKey == nil {
+		return nil
+	}
+	p, err := NewPortableFastBoot(generation, sss.SigningKey)
+	if err != nil {
+		return err
+	}
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return SaveToFile(s, sss.TmpDBHt, b, PortableFilename(networkName, sss.FastBootLocation))
+}
+
+// ImportPortableFastBoot boots a node with an empty database from a portable fastboot
+func (s *State) ImportPortableFastBoot(filename string) error {
+	if head, err := s.DB.FetchDBlockHead(); err != nil {
+		return err
+	} else if head != nil {
+		return errors.New("the database is not empty, a portable fastboot can only start a new node")
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	p := new(PortableFastBoot)
+	if err := p.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	if s.FastBootCheckpoint == "" {
+		return errors.New("no FastBootCheckpoint is configured to check the portable fastboot against")
+	}
+	height, keymr, err := ParseFastBootCheckpoint(s.FastBootCheckpoint)
+	if err != nil {
+		return err
+	}
+	header, err := p.Verify(strings.Split(s.FastBootTrustedKeys, ","), height, keymr)
+	if err != nil {
+		return err
+	}
+	if err := consistentVersion(header); err != nil {
+		return err
+	}
+
+	state, err := (&FastBootHeader{}).UnmarshalBinaryData(p.Generation)
+	if err != nil {
+		return err
+	}
+	if err := restoreDBStateList(s.DBStates, state); err != nil {
+		return err
+	}
+	for _, dbstate := range s.DBStates.DBStates {
+		if dbstate != nil {
+			dbstate.SaveStruct.Commits.s = s
+			if err := s.saveImportedBlocks(dbstate); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Boot from the imported state from now on, as from any other save
+	if err := s.StateSaverStruct.saveGeneration(s, s.Network, 0, p.Generation); err != nil {
+		return err
+	}
+
+	s.FastBootImportHeight = header.DBHeight
+	if err := s.saveFastBootImport(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%20s Imported the portable fastboot %s saved at %d:%s\n", s.FactomNodeName, filename,
+		header.DBHeight, header.KeyMR.String())
+	return nil
+}
+
+// saveImportedBlocks writes the blocks of a DBState of an imported fastboot, so the database starts there
+func (s *State) saveImportedBlocks(d *DBState) error {
+	if d.DirectoryBlock == nil || d.AdminBlock == nil || d.FactoidBlock == nil || d.EntryCreditBlock == nil {
+		return nil
+	}
+	s.DB.StartMultiBatch()
+	if err := s.DB.ProcessABlockMultiBatch(d.AdminBlock); err != nil {
+		return err
+	}
+	if err := s.DB.ProcessFBlockMultiBatch(d.FactoidBlock); err != nil {
+		return err
+	}
+	if err := s.DB.ProcessECBlockMultiBatch(d.EntryCreditBlock, false); err != nil {
+		return err
+	}
+	for _, eb := range d.EntryBlocks {
+		if err := s.DB.ProcessEBlockMultiBatch(eb, true); err != nil {
+			return err
+		}
+	}
+	if err := s.DB.ProcessDBlockMultiBatch(d.DirectoryBlock); err != nil {
+		return err
+	}
+	return s.DB.ExecuteMultiBatch()
+}
+
+// fastBootImportKey is the KEY_VALUE_STORE key of the height of the portable fastboot the database was
+// started from, and whether its balances were verified
+var fastBootImportKey = []byte("FastBootImport")
+
+func (s *State) saveFastBootImport() error {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushUInt32(s.FastBootImportHeight); err != nil {
+		return err
+	}
+	if err := buf.PushBool(s.FastBootImportVerified); err != nil {
+		return err
+	}
+	return s.DB.SaveKeyValueStore(&primitives.ByteSlice{Bytes: buf.DeepCopyBytes()}, fastBootImportKey)
+}
+
+// loadFastBootImport reads back the portable fastboot the database was started from, leaving the height
+// zero if it wasn't
+func (s *State) loadFastBootImport() error {
+	bs := new(primitives.ByteSlice)
+	if v, err := s.DB.FetchKeyValueStore(fastBootImportKey, bs); err != nil || v == nil || len(bs.Bytes) == 0 {
+		return err
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	height, err := buf.PopUInt32()
+	if err != nil {
+		return err
+	}
+	verified, err := buf.PopBool()
+	if err != nil {
+		return err
+	}
+	s.FastBootImportHeight, s.FastBootImportVerified = height, verified
+	return nil
+}
+
+// checkImportedBalances compares the balance hash on a leader's ack with ours, the first time one is seen
+// after booting from a portable fastboot.  If they differ the node stops, rather than follow the network
+// with balances it doesn't share.
+func (s *State) checkImportedBalances(ack interfaces.IHash) {
+	if s.FastBootImportHeight == 0 || s.FastBootImportVerified || ack == nil || s.Balancehash == nil {
+		return
+	}
+	if s.RunState != runstate.Running {
+		return
+	}
+	if !ack.IsSameAs(s.Balancehash) {
+		s.StateSaverStruct.DeleteSaveState(s, s.Network)
+		fmt.Fprintf(os.Stderr, "%20s The balances imported from the portable fastboot at %d don't match the leaders' balance hash %x, ours %x.  "+
+			"Delete the database and import a different fastboot, or sync from genesis.\n",
+			s.FactomNodeName, s.FastBootImportHeight, ack.Bytes()[:4], s.Balancehash.Bytes()[:4])
+		s.ShutdownNode(1)
+		return
+	}
+	s.FastBootImportVerified = true
+	if err := s.saveFastBootImport(); err != nil {
+		s.LogPrintf("faulting", "Failed to record the verified portable fastboot import: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "%20s The balances imported from the portable fastboot at %d match the leaders\n", s.FactomNodeName, s.FastBootImportHeight)
+}
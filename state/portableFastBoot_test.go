@@ -0,0 +1,176 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages/electionMsgs"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPortableFastBoot(t *testing.T) {
+	keymr := primitives.RandomHash()
+	header := &FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 2000, KeyMR: keymr}
+	generation, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	generation = append(generation, []byte("saved state")...)
+
+	key := primitives.RandomPrivateKey()
+	p, err := NewPortableFastBoot(generation, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2 := new(PortableFastBoot)
+	if err := p2.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	trusted := []string{primitives.RandomPrivateKey().PublicKeyString(), key.PublicKeyString()}
+	h, err := p2.Verify(trusted, 2000, keymr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.DBHeight != 2000 || !h.KeyMR.IsSameAs(keymr) {
+		t.Errorf("Wrong header %v", h)
+	}
+
+	if _, err := p2.Verify(trusted[:1], 2000, keymr); err == nil {
+		t.Error("Accepted a fastboot signed by a key that isn't trusted")
+	}
+	if _, err := p2.Verify(trusted, 2000, primitives.RandomHash()); err == nil {
+		t.Error("Accepted a fastboot saved at a different block than the checkpoint")
+	}
+	if _, err := p2.Verify(trusted, 1000, keymr); err == nil {
+		t.Error("Accepted a fastboot saved at a different height than the checkpoint")
+	}
+
+	p2.Generation[len(p2.Generation)-1] ^= 1
+	if _, err := p2.Verify(trusted, 2000, keymr); err == nil {
+		t.Error("Accepted a fastboot changed after it was signed")
+	}
+}
+
+func TestParseFastBootCheckpoint(t *testing.T) {
+	keymr := primitives.RandomHash()
+	height, h, err := ParseFastBootCheckpoint(fmt.Sprintf("1234:%s", keymr.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 1234 || !h.IsSameAs(keymr) {
+		t.Errorf("Parsed %d:%s", height, h.String())
+	}
+
+	for _, bad := range []string{"", "1234", "x:" + keymr.String(), "1234:zz"} {
+		if _, _, err := ParseFastBootCheckpoint(bad); err == nil {
+			t.Errorf("Accepted checkpoint %q", bad)
+		}
+	}
+}
+
+// bootState starts a node on a database as the test states are, with its fastboots in dir
+func bootState(db interfaces.DBOverlaySimple, dir string, configure func(s *State)) *State {
+	s := new(State)
+	s.EventService = events.NewEventService()
+	s.TimestampAtBoot = new(primitives.Timestamp)
+	s.TimestampAtBoot.SetTime(0)
+	s.EFactory = new(electionMsgs.ElectionsFactory)
+	s.SetLeaderTimestamp(primitives.NewTimestampFromMilliseconds(0))
+	s.DB = db
+	s.LoadConfig("", "")
+	s.Network = "LOCAL"
+	s.LogPath = "stdout"
+	s.StateSaverStruct.FastBoot = false
+	s.StateSaverStruct.FastBootLocation = dir
+	s.StateSaverStruct.InDB = false
+	configure(s)
+	s.Init()
+	return s
+}
+
+func TestImportPortableFastBootAndRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A portable fastboot saved by a node that has the chain
+	source := testHelper.CreatePopulateAndExecuteTestState()
+	header, state := goodGeneration(t, source)
+	hb, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := primitives.RandomPrivateKey()
+	p, err := NewPortableFastBoot(append(hb, state...), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "portable.db")
+	if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new node with an empty database imports it, without FastBoot set
+	db := testHelper.CreateEmptyTestDatabaseOverlay()
+	s := bootState(db, dir, func(s *State) {
+		s.FastBootImport = filename
+		s.FastBootTrustedKeys = key.PublicKeyString()
+		s.FastBootCheckpoint = fmt.Sprintf("%d:%s", header.DBHeight, header.KeyMR.String())
+	})
+	if s.FastBootImportHeight != header.DBHeight || !s.StateSaverStruct.FastBoot {
+		t.Fatalf("Imported at %d with FastBoot %v, expected %d", s.FastBootImportHeight, s.StateSaverStruct.FastBoot, header.DBHeight)
+	}
+	head, err := db.FetchDBlockHead()
+	if err != nil || head == nil || head.GetDatabaseHeight() != header.DBHeight {
+		t.Fatalf("The imported blocks were not saved: %v, %v", head, err)
+	}
+
+	// Restarted without the import configured, or FastBoot, it still boots from the fastboot and will check
+	// the imported balances
+	r := bootState(db, dir, func(s *State) {})
+	if !r.StateSaverStruct.FastBoot {
+		t.Error("Booted a database started from a portable fastboot without FastBoot")
+	}
+	if r.FastBootImportHeight != header.DBHeight || r.FastBootImportVerified {
+		t.Errorf("The import at %d wasn't kept, read %d verified %v", header.DBHeight, r.FastBootImportHeight, r.FastBootImportVerified)
+	}
+	if last := r.DBStates.Last(); last == nil || last.DirectoryBlock.GetDatabaseHeight() != header.DBHeight {
+		t.Errorf("Didn't boot from the imported fastboot at %d", header.DBHeight)
+	}
+
+	// Without its fastboot it refuses to boot rather than load from a database missing the blocks below it
+	if err := r.StateSaverStruct.DeleteSaveState(r, r.Network); err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Booted a database started from a portable fastboot without its fastboot")
+			}
+		}()
+		bootState(db, dir, func(s *State) {})
+	}()
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/tracing"
 	"github.com/FactomProject/factomd/util/atomic"
 
 	//"github.com/FactomProject/factomd/database/databaseOverlay"
@@ -898,6 +899,9 @@ func (p *ProcessList) processVM(vm *VM) (progress bool) {
 			vm.heartBeat = 0
 			vm.Height = j + 1 // Don't process it again if the process worked.
 			s.LogMessage("process", fmt.Sprintf("done %v/%v/%v", p.DBHeight, i, j), msg)
+			if s.TraceMessages {
+				tracing.Processed(msg, p.DBHeight)
+			}
 			//s.LogPrintf("process", "thisAck  %x", thisAck.SerialHash.Bytes())
 
 			progress = true
@@ -925,6 +929,7 @@ func (p *ProcessList) RemoveFromPL(vm *VM, j int, reason string) {
 	p.State.LogMessage("process", fmt.Sprintf("nil out message %v/%v/%v, %s", p.DBHeight, vm.VmIndex, j, reason), vm.List[j]) //todo: revisit message
 
 	p.State.rejects <- MsgPair{vm.ListAck[j], vm.List[j]} // Notify MMR framework that we rejected this message
+	p.State.DropMessageTrace(vm.List[j], reason)
 
 	vm.List[j] = nil
 	if vm.HighestNil > j {
@@ -1138,6 +1143,7 @@ func (p *ProcessList) AddToProcessList(s *State, ack *messages.Ack, m interfaces
 	p.VMs[ack.VMIndex].List[ack.Height] = m
 	p.VMs[ack.VMIndex].ListAck[ack.Height] = ack
 	p.AddOldMsgs(m)
+	s.TraceMessage(m, tracing.StageProcessList)
 	p.OldAcks[msgHash.Fixed()] = ack
 
 	if s.adds != nil {
@@ -0,0 +1,151 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// The pruner walks the database of a chain subset node behind the entry sync, and removes the entries
+// of the chains it doesn't keep.  They may predate the KeepEntryChains setting, or have come with a
+// DBState.  Only blocks more than PruneRetention blocks below the entry complete height are touched.
+// Progress is saved to the database so a restart picks up where we left off.
+//
+// Nothing else is removed.  Every entry block stays, as it is part of the DBStates we hand to peers
+// syncing from us, and of the blocks we load at boot.  In memory, DBStateList.Put already drops all but
+// the last few saved DBStates, and ProcessLists.UpdateState the process lists below the one being
+// built, so neither grows with the height.  Unconfirmed dirblock info is deleted when its confirmed
+// record is saved, by ProcessDirBlockInfoBatch.
+
+const pruneSleep = 10 * time.Second // How long to wait when there is nothing to prune
+
+type PruneStatus struct {
+	Enabled        bool   `json:"enabled"`
+	Running        bool   `json:"running"`
+	Retention      uint32 `json:"retention"`
+	PrunedHeight   uint32 `json:"prunedheight"` // Highest directory block we are done with
+	TargetHeight   uint32 `json:"targetheight"` // Highest directory block we are allowed to prune
+	EntriesRemoved int64  `json:"entriesremoved"`
+	LastError      string `json:"lasterror,omitempty"`
+	LastRun        int64  `json:"lastrun"` // Unix time of the last pass
+}
+
+type Pruner struct {
+	mutex  sync.Mutex
+	status PruneStatus
+}
+
+// Status returns a copy of the pruner status for the debug API
+func (p *Pruner) Status() PruneStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.status
+}
+
+func (p *Pruner) update(f func(status *PruneStatus)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	f(&p.status)
+}
+
+// GetPruneStatus is used by the debug API to report on the background compaction
+func (s *State) GetPruneStatus() interface{} {
+	status := s.Pruner.Status()
+	status.Enabled = s.PruneRetention > 0 && s.IsChainSubset()
+	status.Retention = uint32(s.PruneRetention)
+	return status
+}
+
+// GoPrune()
+// Background compaction of the database.  Does nothing unless PruneRetention is set on a chain subset
+// node, as a node keeping every chain has nothing to remove.
+func (s *State) GoPrune() {
+	if s.PruneRetention <= 0 || !s.IsChainSubset() {
+		return
+	}
+	retention := uint32(s.PruneRetention)
+
+	pruned, err := s.DB.FetchDatabasePruneHeight()
+	if err != nil {
+		pruned = 0
+	}
+	s.Pruner.update(func(status *PruneStatus) { status.PrunedHeight = pruned })
+
+	for {
+		// Never get ahead of the entry sync; entries we haven't synced yet we can't judge.
+		complete := s.EntryDBHeightComplete
+		if complete <= retention || complete-retention <= pruned {
+			time.Sleep(pruneSleep)
+			continue
+		}
+		target := complete - retention
+
+		s.Pruner.update(func(status *PruneStatus) {
+			status.Running = true
+			status.TargetHeight = target
+		})
+
+		for pruned < target {
+			height := pruned + 1
+			entries, err := s.pruneBlock(height)
+			if err != nil {
+				s.LogPrintf("pruning", "Failed to prune dbht %d: %v", height, err)
+				s.Pruner.update(func(status *PruneStatus) { status.LastError = err.Error() })
+				break
+			}
+			pruned = height
+			if err := s.DB.SaveDatabasePruneHeight(pruned); err != nil {
+				s.LogPrintf("pruning", "Failed to save prune height %d: %v", pruned, err)
+			}
+			s.Pruner.update(func(status *PruneStatus) {
+				status.PrunedHeight = pruned
+				status.EntriesRemoved += int64(entries)
+			})
+
+			// Give the rest of the node preference over compaction
+			time.Sleep(time.Millisecond)
+		}
+
+		s.LogPrintf("pruning", "Pruned to dbht %d of %d", pruned, target)
+		s.Pruner.update(func(status *PruneStatus) {
+			status.Running = false
+			status.LastRun = time.Now().Unix()
+		})
+		time.Sleep(pruneSleep)
+	}
+}
+
+// pruneBlock removes the entries of the chains we don't keep from one directory block.  Returns the
+// number of entries removed.
+func (s *State) pruneBlock(height uint32) (entries int, err error) {
+	db, err := s.DB.FetchDBlockByHeight(height)
+	if err != nil || db == nil {
+		return
+	}
+
+	for _, ebEntry := range db.GetEBlockDBEntries() {
+		if s.IsChainKept(ebEntry.GetChainID()) {
+			continue
+		}
+		eblock, err := s.DB.FetchEBlock(ebEntry.GetKeyMR())
+		if err != nil {
+			return entries, err
+		}
+		if eblock == nil {
+			continue
+		}
+		for _, entryHash := range eblock.GetEntryHashes() {
+			if entryHash.IsMinuteMarker() || !has(s, entryHash) {
+				continue
+			}
+			if err := s.DB.DeleteEntry(entryHash); err != nil {
+				return entries, err
+			}
+			entries++
+		}
+	}
+	return
+}
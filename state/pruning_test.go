@@ -0,0 +1,103 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// testChains returns the entry chain and the anchor chain of the test database
+func testChains(t *testing.T, s *State) (chain interfaces.IHash, anchor interfaces.IHash) {
+	db, err := s.DB.FetchDBlockByHeight(1)
+	if err != nil || db == nil {
+		t.Fatalf("test state has no directory block 1: %v", err)
+	}
+	for _, eb := range db.GetEBlockDBEntries() {
+		if databaseOverlay.ValidAnchorChains[eb.GetChainID().String()] {
+			anchor = eb.GetChainID()
+		} else {
+			chain = eb.GetChainID()
+		}
+	}
+	if chain == nil || anchor == nil {
+		t.Fatal("test state is missing the entry or the anchor chain")
+	}
+	return
+}
+
+func entryCount(t *testing.T, s *State, chainID interfaces.IHash) int {
+	entries, err := s.DB.FetchAllEntriesByChainID(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+// prune runs the pruner over every block of the test database, keeping the chains in the list
+func prune(t *testing.T, keep string) *State {
+	s := testHelper.CreateAndPopulateTestState()
+	if err := s.SetKeepEntryChains(keep); err != nil {
+		t.Fatal(err)
+	}
+	s.PruneRetention = 1
+	s.EntryDBHeightComplete = uint32(testHelper.BlockCount)
+	target := s.EntryDBHeightComplete - uint32(s.PruneRetention)
+
+	go s.GoPrune()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		if status := s.GetPruneStatus().(PruneStatus); status.PrunedHeight == target && !status.Running {
+			if status.LastError != "" {
+				t.Fatalf("Pruning failed: %s", status.LastError)
+			}
+			return s
+		}
+	}
+	t.Fatalf("Didn't prune to %d: %+v", target, s.GetPruneStatus())
+	return nil
+}
+
+func TestGoPruneRemovesChainsNotKept(t *testing.T) {
+	s := prune(t, primitives.Sha([]byte("other")).String())
+	chain, anchor := testChains(t, s)
+
+	// The pruner starts above block 0, which holds the first entry of the chain
+	if n := entryCount(t, s, chain); n != 1 {
+		t.Errorf("%d entries left of a chain not kept, expected only the one in block 0", n)
+	}
+	if entryCount(t, s, anchor) == 0 {
+		t.Error("Removed the entries of the anchor chain, which is always kept")
+	}
+	if status := s.GetPruneStatus().(PruneStatus); status.EntriesRemoved == 0 {
+		t.Error("Expected the removed entries to be counted")
+	}
+	height, err := s.DB.FetchDatabasePruneHeight()
+	if err != nil || height != s.GetPruneStatus().(PruneStatus).PrunedHeight {
+		t.Errorf("Saved prune height %d, expected %d (%v)", height, s.GetPruneStatus().(PruneStatus).PrunedHeight, err)
+	}
+}
+
+func TestGoPruneLeavesKeptChains(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	chain, _ := testChains(t, s)
+	before := entryCount(t, s, chain)
+	if before == 0 {
+		t.Fatal("test chain has no entries")
+	}
+
+	s = prune(t, chain.String())
+	if n := entryCount(t, s, chain); n != before {
+		t.Errorf("Kept chain has %d entries, expected %d", n, before)
+	}
+	if status := s.GetPruneStatus().(PruneStatus); status.EntriesRemoved != 0 {
+		t.Errorf("Removed %d entries with every chain kept", status.EntriesRemoved)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// When a factoid transaction, commit or reveal is dropped from holding, refused as a replay, or expires, the
+// reason is kept for a while so the ack APIs can say what happened to it instead of "Unknown".  Only the
+// most recent rejections are kept.
+
+const rejectionCacheSize = 10000
+
+// The reason codes reported by the ack APIs and the live feed
+const (
+	RejectExpired    = "expired"     // Held past the time it could be processed
+	RejectStale      = "stale"       // Dropped from dependent holding as stale
+	RejectReplay     = "replay"      // Already seen, or already in a block
+	RejectDuplicate  = "duplicate"   // The entry has already been committed or revealed
+	RejectInvalid    = "invalid"     // Failed validation
+	RejectTossed     = "tossed"      // Dropped by the process list
+	RejectNodeBehind = "node_behind" // This node is too far behind to hold messages
+)
+
+type MsgRejection struct {
+	Code     string
+	Reason   string // The reason given where the message was dropped
+	Time     interfaces.Timestamp
+	DBHeight uint32
+}
+
+type rejectionKey struct {
+	hash    [32]byte
+	msgType byte
+}
+
+type RejectionCache struct {
+	mutex   sync.Mutex
+	entries map[rejectionKey]MsgRejection
+	order   []rejectionKey // Ring of the keys, oldest is overwritten first
+	next    int
+}
+
+func NewRejectionCache(size int) *RejectionCache {
+	c := new(RejectionCache)
+	c.entries = make(map[rejectionKey]MsgRejection)
+	c.order = make([]rejectionKey, 0, size)
+	return c
+}
+
+func (c *RejectionCache) put(key rejectionKey, r MsgRejection) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		if len(c.order) < cap(c.order) {
+			c.order = append(c.order, key)
+		} else {
+			delete(c.entries, c.order[c.next])
+			c.order[c.next] = key
+			c.next = (c.next + 1) % len(c.order)
+		}
+	}
+	c.entries[key] = r
+}
+
+func (c *RejectionCache) Get(hash [32]byte, msgType byte) (MsgRejection, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r, ok := c.entries[rejectionKey{hash, msgType}]
+	return r, ok
+}
+
+func (c *RejectionCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+// RejectionCode maps a reason given to DeleteFromHolding to a reason code.  Returns "" when the message
+// wasn't rejected, i.e. it was processed.
+func RejectionCode(reason string) string {
+	switch {
+	case reason == "expired", strings.HasPrefix(reason, "old "):
+		return RejectExpired
+	case reason == RejectStale:
+		return RejectStale
+	case strings.HasSuffix(reason, "_REPLAY"):
+		return RejectReplay
+	case strings.HasPrefix(strings.ToLower(strings.Replace(reason, " ", "", -1)), "alreadycommitted"):
+		return RejectDuplicate
+	case reason == "invalid from holding", reason == "InvalidMsg":
+		return RejectInvalid
+	case strings.HasPrefix(reason, "Toss"):
+		return RejectTossed
+	case reason == "HKB-HSB>1000":
+		return RejectNodeBehind
+	}
+	return ""
+}
+
+// RecordRejection sends the reason a message was dropped to the live feed, and keeps it for the ack APIs
+func (s *State) RecordRejection(msg interfaces.IMsg, reason string) {
+	code := RejectionCode(reason)
+	if code == "" {
+		return
+	}
+	if s.EventService != nil {
+		s.EventService.EmitRejectedEvent(msg, code)
+	}
+	s.DropMessageTrace(msg, reason)
+	s.keepRejection(msg, reason, code)
+}
+
+// keepRejection keeps the reason a message was dropped.  The message is found again by the hashes the ack
+// APIs are called with: the transaction ID of a factoid transaction or commit, and the entry hash of a
+// commit or reveal.
+func (s *State) keepRejection(msg interfaces.IMsg, reason string, code string) {
+	if code == "" {
+		return
+	}
+	if s.Rejections == nil {
+		return
+	}
+
+	var hashes []interfaces.IHash
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		hashes = append(hashes, m.GetRepeatHash())
+	case *messages.CommitChainMsg:
+		hashes = append(hashes, m.GetRepeatHash(), m.CommitChain.EntryHash)
+	case *messages.CommitEntryMsg:
+		hashes = append(hashes, m.GetRepeatHash(), m.CommitEntry.EntryHash)
+	case *messages.RevealEntryMsg:
+		hashes = append(hashes, m.Entry.GetHash())
+	default:
+		return
+	}
+
+	r := MsgRejection{Code: code, Reason: reason, Time: primitives.NewTimestampNow(), DBHeight: s.LLeaderHeight}
+	for _, h := range hashes {
+		s.Rejections.put(rejectionKey{h.Fixed(), msg.Type()}, r)
+	}
+	MsgRejections.WithLabelValues(code).Inc()
+}
+
+// GetMsgRejection returns why a message of the given type was last dropped, code is "" if we don't know of it
+func (s *State) GetMsgRejection(hash interfaces.IHash, msgType byte) (code string, reason string, when interfaces.Timestamp) {
+	if s.Rejections == nil || hash == nil {
+		return "", "", nil
+	}
+	r, ok := s.Rejections.Get(hash.Fixed(), msgType)
+	if !ok {
+		return "", "", nil
+	}
+	return r.Code, r.Reason, r.Time
+}
@@ -0,0 +1,76 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestRejectionCode(t *testing.T) {
+	for reason, code := range map[string]string{
+		"expired":                  RejectExpired,
+		"old EOM":                  RejectExpired,
+		"stale":                    RejectStale,
+		"BLOCK_REPLAY":             RejectReplay,
+		"INTERNAL_REPLAY":          RejectReplay,
+		"AlreadyCommitted2":        RejectDuplicate,
+		"already committed reveal": RejectDuplicate,
+		"invalid from holding":     RejectInvalid,
+		"TossDuplicate":            RejectTossed,
+		"HKB-HSB>1000":             RejectNodeBehind,
+		"Process()":                "",
+		"msg.Process done":         "",
+	} {
+		if got := RejectionCode(reason); got != code {
+			t.Errorf("%q gave %q, expected %q", reason, got, code)
+		}
+	}
+}
+
+func TestRecordRejection(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	var txs []*messages.FactoidTransaction
+	for i := 0; i < 3; i++ {
+		tx := new(factoid.Transaction)
+		tx.AddInput(testHelper.NewFactoidAddress(uint64(i)), uint64(i+1))
+		tx.SetTimestamp(primitives.NewTimestampNow())
+		msg := new(messages.FactoidTransaction)
+		msg.Transaction = tx
+		txs = append(txs, msg)
+	}
+
+	s.RecordRejection(txs[0], "Process()")
+	if code, _, _ := s.GetMsgRejection(txs[0].GetRepeatHash(), constants.FACTOID_TRANSACTION_MSG); code != "" {
+		t.Errorf("A processed message was recorded as %s", code)
+	}
+
+	s.RecordRejection(txs[0], "BLOCK_REPLAY")
+	code, reason, when := s.GetMsgRejection(txs[0].GetRepeatHash(), constants.FACTOID_TRANSACTION_MSG)
+	if code != RejectReplay || reason != "BLOCK_REPLAY" || when == nil {
+		t.Errorf("Got %q %q %v", code, reason, when)
+	}
+	if code, _, _ := s.GetMsgRejection(txs[0].GetRepeatHash(), constants.COMMIT_ENTRY_MSG); code != "" {
+		t.Error("Found the rejection under the wrong message type")
+	}
+
+	// Only the most recent rejections are kept
+	s.Rejections = NewRejectionCache(2)
+	for _, tx := range txs {
+		s.RecordRejection(tx, "expired")
+	}
+	if s.Rejections.Len() != 2 {
+		t.Errorf("Cache holds %d rejections, expected 2", s.Rejections.Len())
+	}
+	if code, _, _ := s.GetMsgRejection(txs[0].GetRepeatHash(), constants.FACTOID_TRANSACTION_MSG); code != "" {
+		t.Error("The oldest rejection was not dropped")
+	}
+	if code, _, _ := s.GetMsgRejection(txs[2].GetRepeatHash(), constants.FACTOID_TRANSACTION_MSG); code != RejectExpired {
+		t.Errorf("Latest rejection is %q", code)
+	}
+}
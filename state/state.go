@@ -5,11 +5,9 @@
 package state
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/rand"
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -36,11 +34,13 @@ import (
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/database/mapdb"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/factomd/util/atomic"
+	"github.com/FactomProject/factomd/util/clock"
 	"github.com/FactomProject/factomd/wsapi"
-	"github.com/FactomProject/logrustash"
 
 	"github.com/FactomProject/factomd/Utilities/CorrectChainHeads/correctChainHeads"
 	log "github.com/sirupsen/logrus"
@@ -173,6 +173,7 @@ type State struct {
 	tickerQueue            chan int
 	timerMsgQueue          chan interfaces.IMsg
 	TimeOffset             interfaces.Timestamp
+	Clock                  clock.Clock // Nil for the wall clock
 	MaxTimeOffset          interfaces.Timestamp
 	networkOutMsgQueue     NetOutMsgQueue
 	networkInvalidMsgQueue chan interfaces.IMsg
@@ -191,6 +192,7 @@ type State struct {
 	ShutdownChan chan int // For gracefully halting Factom
 	JournalFile  string
 	Journaling   bool
+	journalMutex sync.Mutex
 
 	ServerPrivKey         *primitives.PrivateKey
 	ServerPubKey          *primitives.PublicKey
@@ -208,6 +210,8 @@ type State struct {
 	FactomdLocations   string
 
 	CorsDomains []string
+	// Most requests in a JSON-RPC batch.  Zero uses the API default.
+	RpcMaxBatchSize int
 	// Server State
 	StartDelay      int64 // Time in Milliseconds since the last DBState was applied
 	StartDelayLimit int64
@@ -308,6 +312,9 @@ type State struct {
 	DB     interfaces.DBOverlaySimple
 	Anchor interfaces.IAnchor
 
+	// In-process anchoring, for networks without an anchor service.  nil if not configured.
+	Anchorer *Anchorer
+
 	// Directory Block State
 	DBStates       *DBStateList // Holds all DBStates not yet processed.
 	StatesMissing  *StatesMissing
@@ -352,10 +359,48 @@ type State struct {
 	// For Replay / journal
 	IsReplaying     bool
 	ReplayTimestamp interfaces.Timestamp
+	replayMutex     sync.Mutex
+	replayClocks    map[[32]byte][]interfaces.Timestamp // Journalled clocks of the queued messages, by repeat hash
 
 	// State for the Entry Syncing process
 	EntrySyncState *EntrySync
 
+	// Chains whose entries we keep.  nil means we keep every chain.
+	KeepEntryChains map[[32]byte]bool
+
+	// Background compaction of the database of a chain subset node.  Blocks within PruneRetention of
+	// the entry complete height are never pruned.  Zero disables pruning.
+	PruneRetention int
+	Pruner         Pruner
+
+	// Follows the anchor records, and flags a ledger as stalled when it is more than
+	// AnchorStallThreshold blocks behind.  Zero never flags a stall.
+	AnchorStallThreshold int
+	AnchorMonitor        AnchorMonitor
+
+	// Times of the leaders' EOMs, for the consensus health gauges
+	ConsensusHealth ConsensusHealth
+
+	GrpcPort int // Port of the gRPC API, zero if it is off
+
+	TraceEndpoint string // OTLP collector the message traces go to, see the tracing package
+	TraceLog      bool   // Log the message traces when there is no TraceEndpoint
+
+	// Portable fastboot to start a node with an empty database from, and what it must match
+	FastBootImport         string
+	FastBootTrustedKeys    string // Comma separated public keys a portable fastboot may be signed by
+	FastBootCheckpoint     string // height:keymr the portable fastboot must have been saved at
+	FastBootImportHeight   uint32 // Height of the portable fastboot booted from, zero if none was
+	FastBootImportVerified bool   // A leader's ack has had the balance hash of the imported balances
+
+	ActivationHeights string // Activation heights overriding those compiled in, see activations.LoadSchedule
+	ActivationFile    string
+
+	GrantSource       GrantSource // Grants paid on top of the hard coded ones, nil if none are
+	grantSourceConfig string      // The config the GrantSource was made from, it can't change after boot
+
+	TraceMessages bool // The messages of this node are traced; only one node of a simulation is
+
 	MissingEntryBlockRepeat interfaces.Timestamp
 	// DBlock Height at which node has a complete set of eblocks+entries
 	EntryBlockDBHeightComplete uint32
@@ -443,6 +488,7 @@ type State struct {
 	executeRecursionDetection map[[32]byte]interfaces.IMsg
 	Hold                      HoldingList
 	EventService              events.EventService
+	Rejections                *RejectionCache // Recent reasons messages were dropped, for the ack APIs
 
 	// MissingMessageResponse is a cache of the last 1000 msgs we receive such that when
 	// we send out a missing message, we can find that message locally before we ask the net
@@ -489,7 +535,9 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	config := false
 	if _, err := os.Stat(configfile); !os.IsNotExist(err) {
 		os.Stderr.WriteString(fmt.Sprintf("   Using the %s config file.\n", configfile))
-		newState.LoadConfig(configfile, s.GetNetworkName())
+		if err := newState.LoadConfig(configfile, s.GetNetworkName()); err != nil {
+			panic(fmt.Sprintf("Bad config %s: %v", configfile, err))
+		}
 		config = true
 	}
 
@@ -586,6 +634,24 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 
 	newState.FastSaveRate = s.FastSaveRate
 	newState.CorsDomains = s.CorsDomains
+	newState.RpcMaxBatchSize = s.RpcMaxBatchSize
+	newState.KeepEntryChains = s.KeepEntryChains
+	newState.PruneRetention = s.PruneRetention
+	newState.AnchorStallThreshold = s.AnchorStallThreshold
+	newState.GrpcPort = s.GrpcPort
+	newState.TraceEndpoint = s.TraceEndpoint
+	newState.TraceLog = s.TraceLog
+	newState.FastBootImport = s.FastBootImport
+	newState.FastBootTrustedKeys = s.FastBootTrustedKeys
+	newState.FastBootCheckpoint = s.FastBootCheckpoint
+	newState.ActivationHeights = s.ActivationHeights
+	newState.ActivationFile = s.ActivationFile
+	newState.GrantSource = s.GrantSource
+	newState.grantSourceConfig = s.grantSourceConfig
+	newState.Clock = s.Clock
+	newState.StateSaverStruct.SigningKey = s.StateSaverStruct.SigningKey
+	newState.StateSaverStruct.Generations = s.StateSaverStruct.Generations
+	newState.StateSaverStruct.InDB = s.StateSaverStruct.InDB
 	switch newState.DBType {
 	case "LDB":
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
@@ -726,6 +792,10 @@ func (s *State) GetRpcUser() string {
 func (s *State) GetCorsDomains() []string {
 	return s.CorsDomains
 }
+
+func (s *State) GetRpcMaxBatchSize() int {
+	return s.RpcMaxBatchSize
+}
 func (s *State) GetRpcPass() string {
 	return s.RpcPass
 }
@@ -763,7 +833,7 @@ func (s *State) GetPreviousMinuteStartTime() int64 {
 }
 
 func (s *State) GetCurrentTime() int64 {
-	return time.Now().UnixNano()
+	return s.GetClock().Now().UnixNano()
 }
 
 func (s *State) IsSyncing() bool {
@@ -809,15 +879,35 @@ func (s *State) GetAckChange() (bool, error) {
 	return flag, nil
 }
 
-func (s *State) LoadConfig(filename string, networkFlag string) {
+// LoadConfig sets the state from the config file, or to the defaults if no file is given.  It
+// returns an error for a config the node must not start with.
+func (s *State) LoadConfig(filename string, networkFlag string) error {
 	//	s.FactomNodeName = s.Prefix + "FNode0" // Default Factom Node Name for Simulation
 
 	if len(filename) > 0 {
-		s.ConfigFilePath = filename
-		s.ReadCfg(filename)
-
 		// Get our factomd configuration information.
-		cfg := s.GetCfg().(*util.FactomdConfig)
+		cfg := util.ReadConfig(filename)
+
+		// Check the settings the node must not run with before applying any, so a bad reload leaves the
+		// node as it was
+		keepEntryChains, err := parseKeepEntryChains(cfg.App.KeepEntryChains)
+		if err != nil {
+			return err
+		}
+		// The grants are consensus, so the grant source is only read at boot; a reload can't swap it
+		grantConfig := strings.Join([]string{cfg.App.GrantFile, cfg.App.GrantFileChecksum, cfg.App.GrantChainID, cfg.App.GrantChainKeys}, "|")
+		grants := s.GrantSource
+		if s.RunState == runstate.New {
+			grants, err = NewGrantSource(cfg.App.GrantFile, cfg.App.GrantFileChecksum, cfg.App.GrantChainID, cfg.App.GrantChainKeys)
+			if err != nil {
+				return fmt.Errorf("bad grant source: %v", err)
+			}
+		} else if grantConfig != s.grantSourceConfig {
+			return errors.New("the grant source can only be changed by restarting the node")
+		}
+
+		s.ConfigFilePath = filename
+		s.Cfg = cfg
 
 		s.Network = cfg.App.Network
 		if 0 < len(networkFlag) { // Command line overrides the config file.
@@ -874,6 +964,24 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 
 		s.StateSaverStruct.FastBoot = cfg.App.FastBoot
 		s.StateSaverStruct.FastBootLocation = cfg.App.FastBootLocation
+		s.StateSaverStruct.Generations = cfg.App.FastBootGenerations
+		s.StateSaverStruct.InDB = cfg.App.FastBootInDB
+		s.StateSaverStruct.SigningKey = nil
+		if cfg.App.FastBootSigningKey != "" {
+			key, err := primitives.NewPrivateKeyFromHex(cfg.App.FastBootSigningKey)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "FastBootSigningKey is not a valid private key, not signing fastboots: %v\n", err)
+			} else {
+				s.StateSaverStruct.SigningKey = key
+			}
+		}
+		s.FastBootImport = cfg.App.FastBootImport
+		s.FastBootTrustedKeys = cfg.App.FastBootTrustedKeys
+		s.FastBootCheckpoint = cfg.App.FastBootCheckpoint
+		s.ActivationHeights = cfg.App.ActivationHeights
+		s.ActivationFile = cfg.App.ActivationFile
+		s.GrantSource = grants
+		s.grantSourceConfig = grantConfig
 		s.FastBoot = cfg.App.FastBoot
 		s.FastBootLocation = cfg.App.FastBootLocation
 
@@ -892,6 +1000,14 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 				s.CorsDomains = append(s.CorsDomains, strings.Trim(domain, " "))
 			}
 		}
+		s.RpcMaxBatchSize = cfg.App.FactomdRpcMaxBatchSize
+		s.KeepEntryChains = keepEntryChains
+		s.PruneRetention = cfg.App.PruneRetention
+		s.AnchorStallThreshold = cfg.App.AnchorStallThreshold
+		s.GrpcPort = cfg.App.GrpcPort
+		s.TraceEndpoint = cfg.App.TraceEndpoint
+		s.TraceLog = cfg.App.TraceLog
+
 		s.FactomdTLSEnable = cfg.App.FactomdTlsEnabled
 
 		FactomdTLSKeyFile := cfg.App.FactomdTlsPrivateKey
@@ -995,6 +1111,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 	s.JournalFile = s.LogPath + "/journal0" + ".log"
 
 	s.updateNetworkControllerConfig()
+	return nil
 }
 
 func (s *State) GetSalt(ts interfaces.Timestamp) uint32 {
@@ -1051,6 +1168,7 @@ func (s *State) Init() {
 	s.Hold.Init(s)                           // setup the dependant holding map
 	s.TimeOffset = new(primitives.Timestamp) //interfaces.Timestamp(int64(rand.Int63() % int64(time.Microsecond*10)))
 
+	s.Rejections = NewRejectionCache(rejectionCacheSize)
 	s.InvalidMessages = make(map[[32]byte]interfaces.IMsg, 0)
 
 	s.ShutdownChan = make(chan int, 1)                //Channel to gracefully shut down.
@@ -1114,7 +1232,7 @@ func (s *State) Init() {
 	s.DBStates.DBStates = make([]*DBState, 0)
 
 	s.StatesMissing = NewStatesMissing()
-	s.StatesWaiting = NewStatesWaiting()
+	s.StatesWaiting = NewStatesWaitingOnClock(s.GetClock())
 	s.StatesReceived = NewStatesReceived()
 
 	switch s.NodeMode {
@@ -1238,20 +1356,45 @@ func (s *State) Init() {
 	// Allocate the missing message handler
 	s.MissingMessageResponseHandler = NewMissingMessageReponseCache(s)
 
-	if s.StateSaverStruct.FastBoot {
+	imported := false
+	if s.FastBootImport != "" {
+		if d, err := s.DB.FetchDBlockHead(); err == nil && d == nil {
+			if err := s.ImportPortableFastBoot(s.FastBootImport); err != nil {
+				panic(fmt.Sprintf("Failed to import the portable fastboot %s: %v", s.FastBootImport, err))
+			}
+			imported = true
+		}
+	}
+	if !imported {
+		if err := s.loadFastBootImport(); err != nil {
+			panic(fmt.Sprintf("Failed to read the portable fastboot the database was started from: %v", err))
+		}
+	}
+	// A database started from a portable fastboot has no blocks below the import to load them from
+	fromImport := s.FastBootImport != "" || s.FastBootImportHeight != 0
+	if fromImport && !s.StateSaverStruct.FastBoot {
+		fmt.Fprintf(os.Stderr, "%20s The database was started from a portable fastboot, booting from the fastboot\n", s.FactomNodeName)
+		s.StateSaverStruct.FastBoot = true
+	}
+
+	if s.StateSaverStruct.FastBoot && !imported {
 		d, err := s.DB.FetchDBlockHead()
 		if err != nil {
 			panic(err)
 		}
 
-		if d == nil || int(d.GetDatabaseHeight()) < s.FastSaveRate {
+		if !fromImport && (d == nil || int(d.GetDatabaseHeight()) < s.FastSaveRate) {
 			//If we have less than whatever our block rate is, we wipe SaveState
 			//This is to ensure we don't accidentally keep SaveState while deleting a database
-			s.StateSaverStruct.DeleteSaveState(s.Network)
+			s.StateSaverStruct.DeleteSaveState(s, s.Network)
 		} else {
 			err = s.StateSaverStruct.LoadDBStateList(s, s.DBStates, s.Network)
+			if err != nil && fromImport {
+				panic(fmt.Sprintf("No usable fastboot to boot the database started from a portable fastboot: %v.  "+
+					"It has no blocks below the import to load, delete it and import the portable fastboot again.", err))
+			}
 			if err != nil {
-				s.StateSaverStruct.DeleteSaveState(s.Network)
+				s.StateSaverStruct.DeleteSaveState(s, s.Network)
 				s.LogPrintf("faulting", "Database load failed %v", err)
 			}
 			if err == nil {
@@ -1280,18 +1423,15 @@ func (s *State) Init() {
 	}
 }
 
+// HookLogstash adds the logstash sink, which sends the logrus lines and the -debuglog lines to Logstash
 func (s *State) HookLogstash() error {
-	hook, err := logrustash.NewAsyncHook("tcp", s.LogstashURL, "factomdLogs")
-	if err != nil {
-		return err
+	sinks := llog.CurrentSettings().Sinks
+	for _, sink := range sinks {
+		if sink == llog.SinkLogstash {
+			return nil
+		}
 	}
-
-	hook.ReconnectBaseDelay = time.Second // Wait for one second before first reconnect.
-	hook.ReconnectDelayMultiplier = 2
-	hook.MaxReconnectRetries = 10
-
-	s.Logger.Logger.Hooks.Add(hook)
-	return nil
+	return llog.Configure(llog.Settings{Sinks: append(sinks, llog.SinkLogstash), LogstashURL: s.LogstashURL})
 }
 
 func (s *State) GetEntryBlockDBHeightComplete() uint32 {
@@ -1959,7 +2099,7 @@ func (s *State) IsStalled() bool {
 	stalltime = stalltime * 1.5 * 1e9
 	//fmt.Println("STALL 2", s.CurrentMinuteStartTime/1e9, time.Now().UnixNano()/1e9, stalltime/1e9, (float64(time.Now().UnixNano())-stalltime)/1e9)
 
-	if float64(s.CurrentMinuteStartTime) < float64(time.Now().UnixNano())-stalltime { //-90 seconds was arbitrary
+	if float64(s.CurrentMinuteStartTime) < float64(s.GetClock().Now().UnixNano())-stalltime { //-90 seconds was arbitrary
 		return true
 	}
 
@@ -1974,59 +2114,6 @@ func (s *State) DatabaseContains(hash interfaces.IHash) bool {
 	return false
 }
 
-// JournalMessage writes the message to the message journal for debugging
-func (s *State) JournalMessage(msg interfaces.IMsg) {
-	type journalentry struct {
-		Type    byte
-		Message interfaces.IMsg
-	}
-
-	if s.Journaling && len(s.JournalFile) != 0 {
-		f, err := os.OpenFile(s.JournalFile, os.O_APPEND+os.O_WRONLY, 0666)
-		if err != nil {
-			s.JournalFile = ""
-			return
-		}
-		defer f.Close()
-
-		e := new(journalentry)
-		e.Type = msg.Type()
-		e.Message = msg
-
-		p, err := json.Marshal(e)
-		if err != nil {
-			return
-		}
-		fmt.Fprintln(f, string(p))
-	}
-}
-
-// GetJournalMessages gets all messages from the message journal
-func (s *State) GetJournalMessages() [][]byte {
-	ret := make([][]byte, 0)
-	if !s.Journaling || len(s.JournalFile) == 0 {
-		return nil
-	}
-
-	f, err := os.Open(s.JournalFile)
-	if err != nil {
-		s.JournalFile = ""
-		return nil
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
-	for {
-		p, err := r.ReadBytes('\n')
-		if err != nil {
-			break
-		}
-		ret = append(ret, p)
-	}
-
-	return ret
-}
-
 func (s *State) GetLeaderVM() int {
 	return s.LeaderVMIndex
 }
@@ -2251,12 +2338,23 @@ func (s *State) initServerKeys() {
 	s.ServerPubKey = s.ServerPrivKey.Pub
 }
 
+// LogFields are the fields of the node its logrus lines carry
+func (s *State) LogFields() log.Fields {
+	return log.Fields{
+		"node-name": s.GetFactomNodeName(),
+		"identity":  s.GetIdentityChainID().String(),
+		"dbheight":  s.LLeaderHeight,
+		"minute":    s.CurrentMinute,
+		"vm":        s.LeaderVMIndex,
+	}
+}
+
 func (s *State) Log(level string, message string) {
-	packageLogger.WithFields(s.Logger.Data).Info(message)
+	packageLogger.WithFields(s.LogFields()).Info(message)
 }
 
 func (s *State) Logf(level string, format string, args ...interface{}) {
-	llog := packageLogger.WithFields(s.Logger.Data)
+	llog := packageLogger.WithFields(s.LogFields())
 	switch level {
 	case "emergency":
 		llog.Panicf(format, args...)
@@ -2288,15 +2386,25 @@ func (s *State) SetIsReplaying() {
 func (s *State) SetIsDoneReplaying() {
 	s.IsReplaying = false
 	s.ReplayTimestamp = nil
+	s.replayMutex.Lock()
+	s.replayClocks = nil
+	s.replayMutex.Unlock()
 }
 
 // Returns a millisecond timestamp
 func (s *State) GetTimestamp() interfaces.Timestamp {
-	if s.IsReplaying == true {
-		fmt.Println("^^^^^^^^ IsReplying is true")
+	if s.IsReplaying && s.ReplayTimestamp != nil {
 		return s.ReplayTimestamp
 	}
-	return primitives.NewTimestampNow()
+	return primitives.NewTimestampFromMilliseconds(uint64(s.GetClock().Now().UnixNano() / int64(time.Millisecond)))
+}
+
+// GetClock returns the clock of the node, the wall clock unless it is in a simulation on a simulated clock
+func (s *State) GetClock() clock.Clock {
+	if s.Clock == nil {
+		return clock.Real
+	}
+	return s.Clock
 }
 
 func (s *State) GetTimeOffset() interfaces.Timestamp {
@@ -3110,11 +3218,22 @@ func (s *State) IsActive(id activations.ActivationType) bool {
 	if rval && !s.reportedActivations[id] {
 		s.LogPrintf("executeMsg", "Activating Feature %s at height %v", id.String(), highestCompletedBlk)
 		s.reportedActivations[id] = true
+		if s.EventService != nil {
+			s.EventService.EmitNodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL,
+				"Activation %s is active on node %s at height %d", id.String(), s.GetFactomNodeName(), highestCompletedBlk)
+		}
 	}
 
 	return rval
 }
 
+// reportActivations reports the activations whose height has passed, whether or not anything checked them
+func (s *State) reportActivations() {
+	for id := range activations.ActivationMap {
+		s.IsActive(id)
+	}
+}
+
 func (s *State) PassOutputRegEx(RegEx *regexp.Regexp, RegExString string) {
 	s.LogPrintf("networkOutputs", "SetOutputRegEx to '%s'", RegExString)
 	s.OutputRegEx = RegEx
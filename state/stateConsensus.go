@@ -25,6 +25,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/tracing"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/factomd/util/atomic"
 
@@ -72,6 +73,7 @@ func (s *State) LogPrintf(logName string, format string, more ...interface{}) {
 	}
 }
 func (s *State) AddToHolding(hash [32]byte, msg interfaces.IMsg) {
+	s.TraceMessage(msg, tracing.StageHolding)
 	if !constants.NeedsAck(msg.Type()) {
 		s.LogMessage("holding", "add non-ack'd", msg)
 	}
@@ -85,13 +87,19 @@ func (s *State) AddToHolding(hash [32]byte, msg interfaces.IMsg) {
 }
 
 func (s *State) DeleteFromHolding(hash [32]byte, msg interfaces.IMsg, reason string) {
+	if reason != "Process()" {
+		// Dropped, whether or not it got as far as holding
+		s.DropMessageTrace(msg, reason)
+	}
 	_, ok := s.Holding[hash]
 	if ok {
 		delete(s.Holding, hash)
 		s.LogMessage("holding", "delete "+reason, msg)
 		TotalHoldingQueueOutputs.Inc()
 		if reason != "Process()" {
+			// Every rejection goes to the live feed, and is kept for the ack APIs if it has a reason code
 			s.EventService.EmitStateChangeEvent(msg, eventmessages.EntityState_REJECTED)
+			s.keepRejection(msg, reason, RejectionCode(reason))
 		}
 	}
 
@@ -238,6 +246,7 @@ func (s *State) executeMsg(msg interfaces.IMsg) (ret bool) {
 		s.executeRecursionDetection[repeatHash] = msg
 		defer delete(s.executeRecursionDetection, repeatHash)
 	}
+	s.setReplayClock(repeatHash)
 
 	if msg.GetHash() == nil || reflect.ValueOf(msg.GetHash()).IsNil() {
 		s.LogMessage("badEvents", "Nil hash in executeMsg", msg)
@@ -809,6 +818,7 @@ func (s *State) MoveStateToHeight(dbheight uint32, newMinute int) {
 			panic(fmt.Sprintf("Can't jump to the middle of a block minute: %d", newMinute))
 		}
 		s.DBStates.UpdateState()
+		s.reportActivations()
 
 		// update cached values that change with current minute
 		s.CurrentMinute = 0                // Update height and minute
@@ -829,7 +839,7 @@ func (s *State) MoveStateToHeight(dbheight uint32, newMinute int) {
 		// update cached values that change with height
 		s.dbheights <- int(dbheight) // Notify MMR process we have moved on...
 
-		s.CurrentMinuteStartTime = time.Now().UnixNano()
+		s.CurrentMinuteStartTime = s.GetClock().Now().UnixNano()
 		s.CurrentBlockStartTime = s.CurrentMinuteStartTime
 
 		// If an we added or removed servers or elections tool place in minute 9, our lists will be unsorted. Fix that
@@ -876,7 +886,7 @@ func (s *State) MoveStateToHeight(dbheight uint32, newMinute int) {
 		// there might be a circumstance where we get here in a weird state
 		// so make it the normal starting state
 
-		s.CurrentMinuteStartTime = time.Now().UnixNano()
+		s.CurrentMinuteStartTime = s.GetClock().Now().UnixNano()
 		// If an election took place, our lists will be unsorted. Fix that
 		s.LeaderPL.SortAuditServers()
 		s.LeaderPL.SortFedServers()
@@ -981,7 +991,7 @@ func (s *State) repost(m interfaces.IMsg, delay int) {
 	//whereAmI := atomic.WhereAmIString(1)
 	go func() { // This is a trigger to issue the EOM, but we are still syncing.  Wait to retry.
 		if delay > 0 {
-			time.Sleep(time.Duration(delay) * s.FactomSecond()) // delay in Factom seconds
+			s.GetClock().Sleep(time.Duration(delay) * s.FactomSecond()) // delay in Factom seconds
 		}
 		//s.LogMessage("MsgQueue", fmt.Sprintf("enqueue_%s(%d)", whereAmI, len(s.msgQueue)), m)
 		s.LogMessage("MsgQueue", fmt.Sprintf("repost enqueue (%d)", len(s.msgQueue)), m)
@@ -1512,7 +1522,7 @@ func (s *State) LeaderExecuteEOM(m interfaces.IMsg) {
 		fix = true
 	}
 
-	s.EOMIssueTime = time.Now().UnixNano() // Time we issue the EOM
+	s.EOMIssueTime = s.GetClock().Now().UnixNano() // Time we issue the EOM
 
 	// make sure EOM has the right data
 	eom.DBHeight = s.LLeaderHeight
@@ -2052,7 +2062,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 			s.EOMDone = false  // ProcessEOM (EOM complete)
 			s.EOMProcessed = 0 // ProcessEOM (EOM complete)
 
-			s.EOMSyncEnd = time.Now().UnixNano()
+			s.EOMSyncEnd = s.GetClock().Now().UnixNano()
 
 			for _, vm := range pl.VMs {
 				vm.Synced = false // ProcessEOM (EOM complete)
@@ -2188,6 +2198,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 		}
 
 		//fmt.Println(fmt.Sprintf("SigType PROCESS: %10s vm %2d Process Once: !e.Processed(%v) SigType: %s", s.FactomNodeName, e.VMIndex, e.Processed, e.String()))
+		s.recordEOM(e.ChainID)
 		vm.LeaderMinute++
 		s.EOMProcessed++
 		//fmt.Println(fmt.Sprintf("EOM PROCESS: %10s vm %2d EOMProcessed++ (%2d)", s.FactomNodeName, e.VMIndex, s.EOMProcessed))
@@ -2424,6 +2435,7 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 
 		if dbs.DirectoryBlockHeader.GetBodyMR().Fixed() != dblk.GetHeader().GetBodyMR().Fixed() {
 			pl.IncrementDiffSigTally()
+			ConsensusDBSigMismatches.Inc()
 			s.LogPrintf("processList", "Failed. DBSig and DBlocks do not match Expected-Body-Mr: [%d]%x, Got: [%d]%x",
 				dblk.GetHeader().GetDBHeight(), dblk.GetHeader().GetBodyMR().Fixed(), dbs.DirectoryBlockHeader.GetDBHeight(), dbs.DirectoryBlockHeader.GetBodyMR().Fixed())
 
@@ -2469,6 +2481,16 @@ func (s *State) ProcessDBSig(dbheight uint32, msg interfaces.IMsg) bool {
 		dbs.Matches = true
 		s.AddDBSig(dbheight, dbs.ServerIdentityChainID, dbs.DBSignature)
 
+		// The leader puts its balance hash on the ack of its DBSig, it should match ours
+		if ack := vm.ListAck[0]; ack != nil && ack.BalanceHash != nil && s.Balancehash != nil && !ack.BalanceHash.IsSameAs(s.Balancehash) {
+			ConsensusBalanceHashMismatches.Inc()
+			s.LogPrintf("processList", "Balance hash mismatch in DBSig ack from VM %d: %x, ours %x", dbs.VMIndex,
+				ack.BalanceHash.Bytes()[:4], s.Balancehash.Bytes()[:4])
+		}
+		if ack := vm.ListAck[0]; ack != nil {
+			s.checkImportedBalances(ack.BalanceHash)
+		}
+
 		s.DBSigProcessed++
 		//fmt.Println(fmt.Sprintf("Process DBSig %10s vm %2v DBSigProcessed++ (%2d)", s.FactomNodeName, dbs.VMIndex, s.DBSigProcessed))
 		vm.Synced = true // ProcessDBsig
@@ -9,15 +9,28 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 )
 
+// Fastboot saves are kept in generations, each headed by the format version and the directory block it
+// was saved at.  At boot the newest generation whose directory block is in the database is loaded,
+// falling back to older generations if it is corrupt or was saved on a fork the database doesn't have.
+
+const defaultFastBootGenerations = 3 // Used when no number of generations is configured
+
 type StateSaverStruct struct {
 	FastBoot         bool
 	FastBootLocation string
+	Generations      int  // Number of fastboot saves kept
+	InDB             bool // Keep the saves in the KEY_VALUE_STORE bucket rather than in files
+	// Signs a portable copy of each generation saved, see PortableFastBoot.  nil if none is configured
+	SigningKey *primitives.PrivateKey
 
 	TmpDBHt  uint32
 	TmpState []byte
@@ -25,14 +38,55 @@ type StateSaverStruct struct {
 	Stop     bool
 }
 
+// FastBootHeader heads each fastboot generation
+type FastBootHeader struct {
+	Version  uint32           // constants.SaveStateVersion the generation was saved with
+	DBHeight uint32           // Height of the last directory block in the saved state
+	KeyMR    interfaces.IHash // KeyMR of that directory block
+}
+
+func (h *FastBootHeader) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushUInt32(h.Version); err != nil {
+		return nil, err
+	}
+	if err := buf.PushUInt32(h.DBHeight); err != nil {
+		return nil, err
+	}
+	if err := buf.PushIHash(h.KeyMR); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (h *FastBootHeader) UnmarshalBinaryData(p []byte) (newData []byte, err error) {
+	buf := primitives.NewBuffer(p)
+	if h.Version, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	if h.DBHeight, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	if h.KeyMR, err = buf.PopIHash(); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
 func (sss *StateSaverStruct) StopSaving() {
 	sss.Mutex.Lock()
 	defer sss.Mutex.Unlock()
 	sss.Stop = true
 }
 
+func (sss *StateSaverStruct) generations() int {
+	if sss.Generations < 1 {
+		return defaultFastBootGenerations
+	}
+	return sss.Generations
+}
+
 func (sss *StateSaverStruct) SaveDBStateList(s *State, ss *DBStateList, networkName string) error {
-	//For now, to file. Later - to DB
 	if sss.Stop == true {
 		return nil // if we have closed the database then don't save
 	}
@@ -47,13 +101,16 @@ func (sss *StateSaverStruct) SaveDBStateList(s *State, ss *DBStateList, networkN
 	//Actually save data from previous cached state to prevent dealing with rollbacks
 	// Save the N block old state and then make a new savestate for the next save
 	if sss.TmpDBHt != ss.State.LLeaderHeight && len(sss.TmpState) > 0 {
-		filename := NetworkIDToFilename(networkName, sss.FastBootLocation)
-		s.LogPrintf("executeMsg", "%d-:-%d %20s Saving %s for dbht %d", s.LLeaderHeight, s.CurrentMinute, s.FactomNodeName, filename, sss.TmpDBHt)
-		err := SaveToFile(s, sss.TmpDBHt, sss.TmpState, filename)
+		slot := int(sss.TmpDBHt) / ss.State.FastSaveRate % sss.generations()
+		s.LogPrintf("executeMsg", "%d-:-%d %20s Saving fastboot generation %d for dbht %d", s.LLeaderHeight, s.CurrentMinute, s.FactomNodeName, slot, sss.TmpDBHt)
+		err := sss.saveGeneration(s, networkName, slot, sss.TmpState)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "SaveState SaveToFile Failed", err)
+			fmt.Fprintln(os.Stderr, "SaveState saveGeneration Failed", err)
 			return err
 		}
+		if err := sss.savePortable(s, networkName, sss.TmpState); err != nil {
+			fmt.Fprintln(os.Stderr, "SaveState savePortable Failed", err)
+		}
 	}
 
 	if sss.TmpDBHt != ss.State.LLeaderHeight {
@@ -66,19 +123,163 @@ func (sss *StateSaverStruct) SaveDBStateList(s *State, ss *DBStateList, networkN
 		//adding an integrity check
 		h := primitives.Sha(b)
 		b = append(h.Bytes(), b...)
-		sss.TmpState = b
+
+		header := &FastBootHeader{Version: constants.SaveStateVersion, KeyMR: primitives.NewZeroHash()}
+		if d := lastSaved(ss); d != nil {
+			header.DBHeight = d.DirectoryBlock.GetDatabaseHeight()
+			header.KeyMR = d.DirectoryBlock.GetKeyMR()
+		}
+		hb, err := header.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		sss.TmpState = append(hb, b...)
 		sss.TmpDBHt = ss.State.LLeaderHeight
 	}
 
 	return nil
 }
 
-func (sss *StateSaverStruct) DeleteSaveState(networkName string) error {
-	return DeleteFile(NetworkIDToFilename(networkName, sss.FastBootLocation))
+// lastSaved returns the newest DBState of the list holding a SaveState, the one a fastboot restores from
+func lastSaved(list *DBStateList) *DBState {
+	for i := len(list.DBStates) - 1; i >= 0; i-- {
+		if d := list.DBStates[i]; d != nil && d.SaveStruct != nil && d.DirectoryBlock != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+// generationKey is the KEY_VALUE_STORE key of a generation kept in the database
+func generationKey(networkName string, slot int) []byte {
+	return []byte(fmt.Sprintf("FastBoot_%s_%d", networkName, slot))
+}
+
+// GenerationFilename is the file holding one generation of the fastboot saves
+func GenerationFilename(networkName string, fileLocation string, slot int) string {
+	return strings.TrimSuffix(NetworkIDToFilename(networkName, fileLocation), ".db") + fmt.Sprintf("_%d.db", slot)
+}
+
+func (sss *StateSaverStruct) saveGeneration(s *State, networkName string, slot int, b []byte) error {
+	if sss.InDB {
+		return s.DB.SaveKeyValueStore(&primitives.ByteSlice{Bytes: b}, generationKey(networkName, slot))
+	}
+	return SaveToFile(s, sss.TmpDBHt, b, GenerationFilename(networkName, sss.FastBootLocation, slot))
+}
+
+// loadGeneration returns the bytes of a generation, nil if there is none in the slot
+func (sss *StateSaverStruct) loadGeneration(s *State, networkName string, slot int) []byte {
+	if sss.InDB {
+		bs := new(primitives.ByteSlice)
+		if v, err := s.DB.FetchKeyValueStore(generationKey(networkName, slot), bs); err != nil || v == nil {
+			return nil
+		}
+		return bs.Bytes
+	}
+	filename := GenerationFilename(networkName, sss.FastBootLocation, slot)
+	if _, err := os.Stat(filename); err != nil {
+		return nil
+	}
+	b, _ := LoadFromFile(s, filename)
+	return b
 }
 
+// deleteGeneration removes the generation in a slot
+func (sss *StateSaverStruct) deleteGeneration(s *State, networkName string, slot int) error {
+	if sss.InDB {
+		// There is no delete for the KEY_VALUE_STORE bucket, an empty generation is skipped at boot
+		return s.DB.SaveKeyValueStore(&primitives.ByteSlice{Bytes: []byte{}}, generationKey(networkName, slot))
+	}
+	if err := DeleteFile(GenerationFilename(networkName, sss.FastBootLocation, slot)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeleteSaveState removes all the generations, so the next boot is a full reload
+func (sss *StateSaverStruct) DeleteSaveState(s *State, networkName string) error {
+	var err error
+	for slot := 0; slot < sss.generations(); slot++ {
+		if e := sss.deleteGeneration(s, networkName, slot); e != nil {
+			err = e
+		}
+	}
+	// The single file saved before generations were kept
+	if e := DeleteFile(NetworkIDToFilename(networkName, sss.FastBootLocation)); e != nil && !os.IsNotExist(e) {
+		err = e
+	}
+	return err
+}
+
+// RollBackSaveState removes the generations saved at directory blocks the database no longer has, as after
+// it is rolled back, so the next boot restores from one saved at or below the height kept and replays the
+// blocks above it, or does a full reload if there is none.  The single file saved before generations were
+// kept doesn't say what it was saved at, so it is removed too.  Returns the number of generations removed.
+func (sss *StateSaverStruct) RollBackSaveState(s *State, networkName string) (int, error) {
+	removed := 0
+	for slot := 0; slot < sss.generations(); slot++ {
+		b := sss.loadGeneration(s, networkName, slot)
+		if len(b) == 0 {
+			continue
+		}
+		header := new(FastBootHeader)
+		if _, err := header.UnmarshalBinaryData(b); err == nil && consistentBlock(s, header) == nil {
+			continue
+		}
+		if err := sss.deleteGeneration(s, networkName, slot); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	if err := DeleteFile(NetworkIDToFilename(networkName, sss.FastBootLocation)); err != nil && !os.IsNotExist(err) {
+		return removed, err
+	}
+	return removed, nil
+}
+
+type fastBootGeneration struct {
+	slot   int
+	header FastBootHeader
+	state  []byte // Integrity hash and the marshalled DBStateList
+}
+
+// LoadDBStateList restores from the newest generation consistent with the database
 func (sss *StateSaverStruct) LoadDBStateList(s *State, statelist *DBStateList, networkName string) error {
+	var gens []*fastBootGeneration
+	for slot := 0; slot < sss.generations(); slot++ {
+		b := sss.loadGeneration(s, networkName, slot)
+		if len(b) == 0 {
+			continue
+		}
+		g := &fastBootGeneration{slot: slot}
+		rest, err := g.header.UnmarshalBinaryData(b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%20s Fastboot generation %d has a bad header: %v\n", s.FactomNodeName, slot, err)
+			continue
+		}
+		g.state = rest
+		gens = append(gens, g)
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i].header.DBHeight > gens[j].header.DBHeight })
+
+	for _, g := range gens {
+		if err := consistentGeneration(s, &g.header); err != nil {
+			fmt.Fprintf(os.Stderr, "%20s Skipping fastboot generation %d for dbht %d: %v\n", s.FactomNodeName, g.slot, g.header.DBHeight, err)
+			continue
+		}
+		if err := restoreDBStateList(statelist, g.state); err != nil {
+			fmt.Fprintf(os.Stderr, "%20s Skipping fastboot generation %d for dbht %d: %v\n", s.FactomNodeName, g.slot, g.header.DBHeight, err)
+			continue
+		}
+		fmt.Println(statelist.State.FactomNodeName, "Loaded fastboot generation", g.slot, "for dbht", g.header.DBHeight)
+		return nil
+	}
+
+	// Fall back on the single file saved before generations were kept
 	filename := NetworkIDToFilename(networkName, sss.FastBootLocation)
+	if _, err := os.Stat(filename); err != nil {
+		return errors.New("no usable fastboot generation")
+	}
 	fmt.Println(statelist.State.FactomNodeName, "Loading from", filename)
 	b, err := LoadFromFile(s, filename)
 	if err != nil {
@@ -89,8 +290,44 @@ func (sss *StateSaverStruct) LoadDBStateList(s *State, statelist *DBStateList, n
 		fmt.Fprintln(os.Stderr, "LoadDBStateList LoadFromFile returned nil")
 		return errors.New("failed to load from file")
 	}
+	return restoreDBStateList(statelist, b)
+}
+
+// consistentGeneration checks a generation was saved at a directory block the database has
+func consistentGeneration(s *State, header *FastBootHeader) error {
+	if err := consistentVersion(header); err != nil {
+		return err
+	}
+	return consistentBlock(s, header)
+}
+
+// consistentBlock checks the directory block a generation was saved at is the one the database has
+func consistentBlock(s *State, header *FastBootHeader) error {
+	keymr, err := s.DB.FetchDBKeyMRByHeight(header.DBHeight)
+	if err != nil {
+		return err
+	}
+	if keymr == nil {
+		return errors.New("the database doesn't have its directory block")
+	}
+	if !keymr.IsSameAs(header.KeyMR) {
+		return fmt.Errorf("directory block %x doesn't match %x in the database", header.KeyMR.Bytes()[:4], keymr.Bytes()[:4])
+	}
+	return nil
+}
+
+// consistentVersion checks a generation was saved in the format of this release
+func consistentVersion(header *FastBootHeader) error {
+	if header.Version != constants.SaveStateVersion {
+		return fmt.Errorf("saved with version %d, expected %d", header.Version, constants.SaveStateVersion)
+	}
+	return nil
+}
+
+// restoreDBStateList checks the integrity hash of a save and restores the state from it
+func restoreDBStateList(statelist *DBStateList, b []byte) error {
 	h := primitives.NewZeroHash()
-	b, err = h.UnmarshalBinaryData(b)
+	b, err := h.UnmarshalBinaryData(b)
 	if err != nil {
 		return err
 	}
@@ -101,14 +338,14 @@ func (sss *StateSaverStruct) LoadDBStateList(s *State, statelist *DBStateList, n
 		//return fmt.Errorf("Integrity hashes do not match")
 	}
 
-	statelist.UnmarshalBinary(b)
-	var i int
-	for i = len(statelist.DBStates) - 1; i >= 0; i-- {
-		if statelist.DBStates[i].SaveStruct != nil {
-			break
-		}
+	if err := statelist.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	d := lastSaved(statelist)
+	if d == nil {
+		return errors.New("fastboot has no saved state")
 	}
-	statelist.DBStates[i].SaveStruct.RestoreFactomdState(statelist.State)
+	d.SaveStruct.RestoreFactomdState(statelist.State)
 
 	return nil
 }
@@ -0,0 +1,233 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestFastBootHeader(t *testing.T) {
+	h := &FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1234, KeyMR: primitives.RandomHash()}
+	b, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = append(b, 0xAA)
+
+	h2 := new(FastBootHeader)
+	rest, err := h2.UnmarshalBinaryData(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h2.Version != h.Version || h2.DBHeight != h.DBHeight || !h2.KeyMR.IsSameAs(h.KeyMR) {
+		t.Errorf("Header changed - %v vs %v", h, h2)
+	}
+	if len(rest) != 1 || rest[0] != 0xAA {
+		t.Errorf("Expected the state after the header, got %x", rest)
+	}
+}
+
+func writeGeneration(t *testing.T, filename string, header *FastBootHeader, state []byte) {
+	b, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filename, append(b, state...), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDBStateListSkipsBadGenerations(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sss := &s.StateSaverStruct
+	sss.FastBootLocation = dir
+	sss.Generations = 3
+	sss.InDB = false
+
+	keymr, err := s.DB.FetchDBKeyMRByHeight(1)
+	if err != nil || keymr == nil {
+		t.Fatalf("test state has no directory block 1: %v", err)
+	}
+	junk := append(primitives.Sha([]byte("other")).Bytes(), []byte("state")...)
+
+	// Saved on a fork, at a block the database has a different KeyMR for
+	writeGeneration(t, GenerationFilename(s.Network, dir, 0),
+		&FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1, KeyMR: primitives.RandomHash()}, junk)
+	// Matches the database, but the state doesn't match its integrity hash
+	writeGeneration(t, GenerationFilename(s.Network, dir, 1),
+		&FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1, KeyMR: keymr}, junk)
+	// From an old release
+	writeGeneration(t, GenerationFilename(s.Network, dir, 2),
+		&FastBootHeader{Version: constants.SaveStateVersion - 1, DBHeight: 1, KeyMR: keymr}, junk)
+
+	if err := sss.LoadDBStateList(s, s.DBStates, s.Network); err == nil {
+		t.Error("Expected no usable generation")
+	}
+
+	if err := sss.DeleteSaveState(s, s.Network); err != nil {
+		t.Error(err)
+	}
+	for slot := 0; slot < sss.Generations; slot++ {
+		if _, err := os.Stat(GenerationFilename(s.Network, dir, slot)); !os.IsNotExist(err) {
+			t.Errorf("Generation %d was not deleted", slot)
+		}
+	}
+}
+
+func TestRollBackSaveState(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sss := &s.StateSaverStruct
+	sss.FastBootLocation = dir
+	sss.Generations = 3
+	sss.InDB = false
+
+	keymr, err := s.DB.FetchDBKeyMRByHeight(1)
+	if err != nil || keymr == nil {
+		t.Fatalf("test state has no directory block 1: %v", err)
+	}
+	state := append(primitives.Sha([]byte("state")).Bytes(), []byte("state")...)
+
+	// Saved at a block the database has
+	writeGeneration(t, GenerationFilename(s.Network, dir, 0),
+		&FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1, KeyMR: keymr}, state)
+	// Saved at a block that was replaced
+	writeGeneration(t, GenerationFilename(s.Network, dir, 1),
+		&FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1, KeyMR: primitives.RandomHash()}, state)
+	// Saved above the head of the database
+	writeGeneration(t, GenerationFilename(s.Network, dir, 2),
+		&FastBootHeader{Version: constants.SaveStateVersion, DBHeight: 1 << 20, KeyMR: primitives.RandomHash()}, state)
+
+	removed, err := sss.RollBackSaveState(s, s.Network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Removed %d generations, expected 2", removed)
+	}
+	if _, err := os.Stat(GenerationFilename(s.Network, dir, 0)); err != nil {
+		t.Errorf("Removed the generation the database has the block of: %v", err)
+	}
+	for slot := 1; slot < sss.Generations; slot++ {
+		if _, err := os.Stat(GenerationFilename(s.Network, dir, slot)); !os.IsNotExist(err) {
+			t.Errorf("Generation %d was not removed", slot)
+		}
+	}
+}
+
+// goodGeneration returns the header and state of a generation saved from the DBStates of the test state
+func goodGeneration(t *testing.T, s *State) (*FastBootHeader, []byte) {
+	var last *DBState
+	for _, d := range s.DBStates.DBStates {
+		if d != nil && d.DirectoryBlock != nil {
+			last = d
+		}
+	}
+	if last == nil {
+		t.Fatal("test state has no DBStates")
+	}
+	last.SaveStruct = SaveFactomdState(s, last)
+
+	b, err := s.DBStates.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &FastBootHeader{Version: constants.SaveStateVersion, DBHeight: last.DirectoryBlock.GetDatabaseHeight(),
+		KeyMR: last.DirectoryBlock.GetKeyMR()}
+	return header, append(primitives.Sha(b).Bytes(), b...)
+}
+
+func TestLoadDBStateListLoadsGoodGeneration(t *testing.T) {
+	s := testHelper.CreatePopulateAndExecuteTestState()
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sss := &s.StateSaverStruct
+	sss.FastBootLocation = dir
+	sss.Generations = 3
+	sss.InDB = false
+
+	header, state := goodGeneration(t, s)
+	writeGeneration(t, GenerationFilename(s.Network, dir, 1), header, state)
+
+	if err := sss.LoadDBStateList(s, s.DBStates, s.Network); err != nil {
+		t.Fatal(err)
+	}
+	if s.DBStates.Last() == nil || s.DBStates.Last().DirectoryBlock.GetDatabaseHeight() != header.DBHeight {
+		t.Errorf("Expected the DBStates up to %d", header.DBHeight)
+	}
+}
+
+func TestLoadDBStateListFallsBackToOlderGeneration(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sss := &s.StateSaverStruct
+	sss.FastBootLocation = dir
+	sss.Generations = 3
+	sss.InDB = false
+
+	// Save a generation part way through the blocks, then the newest one at the end
+	msgs := testHelper.GetAllDBStateMsgsFromDatabase(s)
+	executeBlocks(s, msgs[:len(msgs)/2])
+	olderHeader, olderState := goodGeneration(t, s)
+	executeBlocks(s, msgs[len(msgs)/2:])
+	header, state := goodGeneration(t, s)
+	if olderHeader.DBHeight >= header.DBHeight {
+		t.Fatalf("older generation saved at %d, not below the newest at %d", olderHeader.DBHeight, header.DBHeight)
+	}
+
+	// The newest generation matches the database, but was corrupted
+	corrupt := append([]byte{}, state...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	writeGeneration(t, GenerationFilename(s.Network, dir, 0), header, corrupt)
+	// An older one that is good
+	writeGeneration(t, GenerationFilename(s.Network, dir, 2), olderHeader, olderState)
+
+	if err := sss.LoadDBStateList(s, s.DBStates, s.Network); err != nil {
+		t.Fatalf("Didn't fall back to the older generation: %v", err)
+	}
+	if s.DBStates.Last() == nil || s.DBStates.Last().DirectoryBlock.GetDatabaseHeight() != olderHeader.DBHeight {
+		t.Errorf("Expected the DBStates up to the older generation at %d", olderHeader.DBHeight)
+	}
+}
+
+// executeBlocks follows the DBStates from the database and processes them into the state
+func executeBlocks(s *State, msgs []interfaces.IMsg) {
+	for _, dbs := range msgs {
+		dbs.(*messages.DBStateMsg).IgnoreSigs = true
+		dbs.(*messages.DBStateMsg).IsInDB = true
+
+		s.FollowerExecuteDBState(dbs)
+	}
+	s.UpdateState()
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/FactomProject/factomd/common/constants/runstate"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/tracing"
 	"github.com/FactomProject/factomd/util/atomic"
 )
 
@@ -22,6 +23,8 @@ var ValidationDebug bool = false
 // This is the tread with access to state. It does process and update state
 func (s *State) DoProcessing() {
 	s.validatorLoopThreadID = atomic.Goid()
+	s.GetClock().Join() // A simulated clock waits for the node to run out of work
+	defer s.GetClock().Leave()
 
 	s.EventService.EmitNodeInfoMessageF(eventmessages.NodeMessageCode_STARTED, "Node %s startup complete", s.GetFactomNodeName())
 	s.RunState = runstate.Running
@@ -64,7 +67,7 @@ func (s *State) DoProcessing() {
 		// if we were unable to accomplish any work sleep a bit.
 		if !p1 && !p2 && !p3 {
 			// No work? Sleep for a bit
-			time.Sleep(10 * time.Millisecond)
+			s.GetClock().Sleep(10 * time.Millisecond)
 			s.ValidatorLoopSleepCnt++
 			i3++
 			slp = true
@@ -128,7 +131,7 @@ func (s *State) ValidatorLoop() {
 				if c > 0 {
 					go func() {
 						// We sleep for 1/10 of a minute, and try again
-						time.Sleep(s.GetMinuteDuration() / 10)
+						s.GetClock().Sleep(s.GetMinuteDuration() / 10)
 						s.tickerQueue <- c - 1
 					}()
 				}
@@ -153,19 +156,25 @@ func (s *State) ValidatorLoop() {
 			eom.SetLocal(true) // local EOMs are really just timeout indicators that we need to generate an EOM
 			msg = eom
 			s.LogMessage("validator", fmt.Sprintf("generated c:%d  %d-:-%d %d", c, s.LLeaderHeight, s.CurrentMinute, s.LeaderVMIndex), eom)
+			s.JournalMessageTo(eom, JournalTimer)
 		case msg = <-s.inMsgQueue:
 			s.LogMessage("InMsgQueue", "dequeue", msg)
 		case msg = <-s.inMsgQueue2:
 			s.LogMessage("InMsgQueue2", "dequeue", msg)
 		}
 
-		if t := msg.Type(); t == constants.ACK_MSG {
-			s.LogMessage("ackQueue", "enqueue ValidatorLoop", msg)
-			s.ackQueue <- msg
-		} else {
-			s.LogMessage("msgQueue", "enqueue ValidatorLoop", msg)
-			s.msgQueue <- msg
-		}
+		s.queueForExecution(msg)
+	}
+}
+
+// queueForExecution hands a message from the input queues to the executing thread
+func (s *State) queueForExecution(msg interfaces.IMsg) {
+	if t := msg.Type(); t == constants.ACK_MSG {
+		s.LogMessage("ackQueue", "enqueue ValidatorLoop", msg)
+		s.ackQueue <- msg
+	} else {
+		s.LogMessage("msgQueue", "enqueue ValidatorLoop", msg)
+		s.msgQueue <- msg
 	}
 }
 
@@ -188,5 +197,8 @@ func shutdown(state *State) {
 	state.StateSaverStruct.StopSaving()
 	state.DB.Close()
 	fmt.Println("Database on", state.GetFactomNodeName(), "closed")
+	if state.TraceMessages {
+		tracing.Stop()
+	}
 	state.RunState = runstate.Stopped
 }
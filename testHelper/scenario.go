@@ -0,0 +1,425 @@
+package testHelper
+
+// Declarative simulation scenarios.  A scenario is a YAML or JSON file with a list of steps, run in
+// order against the simulator.  It replaces strings of single character simControl commands with
+// named actions, so a test reads as what it does to the network.
+//
+//	name: brainswap
+//	steps:
+//	  - action: start
+//	    nodes: LLLAFF
+//	    height: 15
+//	  - action: wait-block
+//	    height: 6
+//	  - action: offline
+//	    node: 5
+//	    at: {block: 9, minute: 0}
+//	  - action: assert-authorities
+//	    nodes: LLFFLA
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/state"
+)
+
+type Scenario struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Steps       []ScenarioStep `json:"steps" yaml:"steps"`
+
+	Funcs map[string]ScenarioFunc `json:"-" yaml:"-"` // Go checks the call action runs, by name
+}
+
+// ScenarioFunc is a check or setup step written in Go, for what a scenario can't say.  state0 is nil
+// before the network is started.
+type ScenarioFunc func(t *testing.T, state0 *state.State)
+
+// ScenarioTime is a point in the life of the network, block and minute
+type ScenarioTime struct {
+	Block  int `json:"block" yaml:"block"`
+	Minute int `json:"minute" yaml:"minute"`
+}
+
+// ScenarioStep is one action.  Only the fields the action uses need to be set.  Node selects the node the
+// action applies to, and whose clock the wait actions follow; it defaults to node 0.
+type ScenarioStep struct {
+	Action string        `json:"action" yaml:"action"`
+	At     *ScenarioTime `json:"at,omitempty" yaml:"at,omitempty"` // Wait for this block and minute of node 0 first
+	Node   int           `json:"node,omitempty" yaml:"node,omitempty"`
+
+	Nodes     string            `json:"nodes,omitempty" yaml:"nodes,omitempty"` // Authority set, i.e. LLLAAF
+	Options   map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+	Height    int               `json:"height,omitempty" yaml:"height,omitempty"`
+	Elections int               `json:"elections,omitempty" yaml:"elections,omitempty"`
+	Rounds    int               `json:"rounds,omitempty" yaml:"rounds,omitempty"`
+
+	Count    int    `json:"count,omitempty" yaml:"count,omitempty"`
+	Minute   int    `json:"minute,omitempty" yaml:"minute,omitempty"`
+	Role     string `json:"role,omitempty" yaml:"role,omitempty"`   // leader or audit
+	Rate     int    `json:"rate,omitempty" yaml:"rate,omitempty"`   // Drop rate in tenths of a percent, or entries per second
+	Delay    int    `json:"delay,omitempty" yaml:"delay,omitempty"` // Milliseconds
+	Regex    string `json:"regex,omitempty" yaml:"regex,omitempty"` // Message filter
+	Identity int    `json:"identity,omitempty" yaml:"identity,omitempty"`
+	Extra    string `json:"extra,omitempty" yaml:"extra,omitempty"`
+	Secret   string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Address  string `json:"address,omitempty" yaml:"address,omitempty"`
+	Amount   int64  `json:"amount,omitempty" yaml:"amount,omitempty"`
+	Cmd      string `json:"cmd,omitempty" yaml:"cmd,omitempty"`     // Raw simControl command
+	Other    int    `json:"other,omitempty" yaml:"other,omitempty"` // Node compared with Node
+	Index    int    `json:"index,omitempty" yaml:"index,omitempty"` // Coinbase output
+	Func     string `json:"func,omitempty" yaml:"func,omitempty"`   // Go function of the test
+}
+
+type scenarioRun struct {
+	t      *testing.T
+	sc     *Scenario
+	state0 *state.State
+}
+
+type scenarioAction struct {
+	needsSim bool // Only valid after the start step
+	run      func(r *scenarioRun, step *ScenarioStep)
+}
+
+var scenarioActions map[string]scenarioAction
+
+func init() {
+	scenarioActions = map[string]scenarioAction{
+		// Setup
+		"reset-home":    {false, func(r *scenarioRun, step *ScenarioStep) { ResetSimHome(r.t) }},
+		"write-configs": {false, scenarioWriteConfigs},
+		"write-config":  {false, func(r *scenarioRun, step *ScenarioStep) { WriteConfigFile(step.Identity, step.Node, step.Extra, r.t) }},
+		"start":         {false, scenarioStart},
+		"add-node":      {true, scenarioAddNode},
+		"call":          {false, func(r *scenarioRun, step *ScenarioStep) { r.sc.Funcs[step.Func](r.t, r.state0) }},
+
+		// Authority set
+		"identities":         {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("g%d", step.Count)) }},
+		"promote":            {true, scenarioPromote},
+		"demote":             {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("%d", step.Node)); RunCmd("z") }},
+		"assign-identity":    {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("%d", step.Node)); RunCmd("t") }},
+		"cancel-coinbase":    {true, scenarioCancelCoinbase},
+		"expect-authorities": {true, func(r *scenarioRun, step *ScenarioStep) { AdjustAuthoritySet(step.Nodes) }},
+
+		// Network conditions
+		"offline":       {true, func(r *scenarioRun, step *ScenarioStep) { scenarioNetState(step.Node, true) }},
+		"online":        {true, func(r *scenarioRun, step *ScenarioStep) { scenarioNetState(step.Node, false) }},
+		"drop-rate":     {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("S%d", step.Rate)) }},
+		"delay":         {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("F%d", step.Delay)) }},
+		"wsapi":         {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("%d", step.Node)); RunCmd("w") }},
+		"input-filter":  {true, func(r *scenarioRun, step *ScenarioStep) { scenarioFilter(r, step, SetInputFilter) }},
+		"output-filter": {true, func(r *scenarioRun, step *ScenarioStep) { scenarioFilter(r, step, SetOutputFilter) }},
+
+		// Load
+		"load":     {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("R%d", step.Rate)) }},
+		"send-fct": {true, scenarioSendFCT},
+		"cmd":      {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(step.Cmd) }},
+		"focus":    {true, func(r *scenarioRun, step *ScenarioStep) { RunCmd(fmt.Sprintf("%d", step.Node)) }},
+		"sim-ctrl": {true, scenarioSimCtrl},
+
+		// Waits
+		"wait-blocks":  {true, func(r *scenarioRun, step *ScenarioStep) { WaitBlocks(scenarioState(step), step.Count) }},
+		"wait-minutes": {true, func(r *scenarioRun, step *ScenarioStep) { WaitMinutes(scenarioState(step), step.Count) }},
+		"wait-block":   {true, func(r *scenarioRun, step *ScenarioStep) { WaitForBlock(scenarioState(step), step.Height) }},
+		"wait-minute":  {true, func(r *scenarioRun, step *ScenarioStep) { WaitForMinute(scenarioState(step), step.Minute) }},
+		"wait-all":     {true, func(r *scenarioRun, step *ScenarioStep) { WaitForAllNodes(r.state0) }},
+		"wait-holding": {true, scenarioWaitHolding},
+		"sleep":        {false, func(r *scenarioRun, step *ScenarioStep) { time.Sleep(time.Duration(step.Delay) * time.Millisecond) }},
+		"status":       {true, func(r *scenarioRun, step *ScenarioStep) { StatusEveryMinute(scenarioState(step)) }},
+
+		// Asserts
+		"assert-authorities": {true, func(r *scenarioRun, step *ScenarioStep) { AssertAuthoritySet(r.t, step.Nodes) }},
+		"check-authorities":  {true, func(r *scenarioRun, step *ScenarioStep) { CheckAuthoritySet(r.t) }},
+		"assert-height":      {true, scenarioAssertHeight},
+		"assert-balance":     {true, scenarioAssertBalance},
+		"assert-leader":      {true, func(r *scenarioRun, step *ScenarioStep) { scenarioAssertLeader(r, step, true) }},
+		"assert-not-leader":  {true, func(r *scenarioRun, step *ScenarioStep) { scenarioAssertLeader(r, step, false) }},
+		"assert-focus":       {true, scenarioAssertFocus},
+		"assert-same-height": {true, scenarioAssertSameHeight},
+	}
+}
+
+// LoadScenario reads a scenario file.  Files ending in .json are JSON, anything else is YAML.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := new(Scenario)
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		// As strict as the YAML, so a misspelled field is an error rather than a step that does nothing
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(sc)
+	} else {
+		err = yaml.UnmarshalStrict(data, sc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(sc.Name) == 0 {
+		sc.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return sc, nil
+}
+
+// Validate checks the actions and their order without starting anything
+func (sc *Scenario) Validate() error {
+	started := false
+	for i, step := range sc.Steps {
+		action, ok := scenarioActions[step.Action]
+		if !ok {
+			return fmt.Errorf("step %d: unknown action %q", i, step.Action)
+		}
+		if action.needsSim && !started {
+			return fmt.Errorf("step %d: %s before the network is started", i, step.Action)
+		}
+		if step.At != nil && !started {
+			return fmt.Errorf("step %d: a timed step before the network is started", i)
+		}
+		switch step.Action {
+		case "start":
+			if started {
+				return fmt.Errorf("step %d: the network is already started", i)
+			}
+			if len(step.Nodes) == 0 {
+				return fmt.Errorf("step %d: start needs nodes", i)
+			}
+			started = true
+		case "promote":
+			if step.Role != "leader" && step.Role != "audit" {
+				return fmt.Errorf("step %d: promote to %q, not leader or audit", i, step.Role)
+			}
+		case "assert-authorities", "expect-authorities":
+			if len(step.Nodes) == 0 {
+				return fmt.Errorf("step %d: %s needs nodes", i, step.Action)
+			}
+		case "cancel-coinbase":
+			if step.Height == 0 {
+				return fmt.Errorf("step %d: cancel-coinbase needs a height", i)
+			}
+		case "call":
+			if len(step.Func) == 0 {
+				return fmt.Errorf("step %d: call needs a func", i)
+			}
+		case "assert-balance", "send-fct":
+			if len(step.Address) == 0 {
+				return fmt.Errorf("step %d: %s needs an address", i, step.Action)
+			}
+		case "cmd", "sim-ctrl":
+			if len(step.Cmd) == 0 {
+				return fmt.Errorf("step %d: %s needs a command", i, step.Action)
+			}
+		case "input-filter", "output-filter":
+			if len(step.Regex) == 0 {
+				return fmt.Errorf("step %d: %s needs a regex", i, step.Action)
+			}
+		}
+		if step.At != nil && (step.At.Minute < 0 || step.At.Minute > 9) {
+			return fmt.Errorf("step %d: minute %d out of range", i, step.At.Minute)
+		}
+	}
+	if !started {
+		return fmt.Errorf("the scenario never starts the network")
+	}
+	return nil
+}
+
+// RunScenarioFile loads and runs a scenario file
+func RunScenarioFile(t *testing.T, path string) *state.State {
+	sc, err := LoadScenario(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return RunScenario(t, sc)
+}
+
+// RunScenarioFileWith loads and runs a scenario file that calls the Go functions of the test
+func RunScenarioFileWith(t *testing.T, path string, funcs map[string]ScenarioFunc) *state.State {
+	sc, err := LoadScenario(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.Funcs = funcs
+	return RunScenario(t, sc)
+}
+
+// RunScenario runs the steps of a scenario in order, and shuts the network down at the end.
+// Returns the state of node 0.
+func RunScenario(t *testing.T, sc *Scenario) *state.State {
+	if err := sc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i, step := range sc.Steps {
+		if step.Action == "call" && sc.Funcs[step.Func] == nil {
+			t.Fatalf("step %d: the test has no func %q", i, step.Func)
+		}
+	}
+
+	r := &scenarioRun{t: t, sc: sc}
+	for i := range sc.Steps {
+		step := &sc.Steps[i]
+		if step.At != nil {
+			WaitForQuiet(r.state0, step.At.Block, step.At.Minute)
+		}
+		t.Logf("Scenario %s step %d: %s", sc.Name, i, step.Action)
+		scenarioActions[step.Action].run(r, step)
+	}
+	ShutDownEverything(t)
+	return r.state0
+}
+
+func scenarioState(step *ScenarioStep) *state.State {
+	return engine.GetFnodes()[step.Node].State
+}
+
+func scenarioStart(r *scenarioRun, step *ScenarioStep) {
+	r.state0 = SetupSim(step.Nodes, step.Options, step.Height, step.Elections, step.Rounds, r.t)
+}
+
+// scenarioWriteConfigs writes the minimal config, with the default identity, for nodes 0 to Count-1
+func scenarioWriteConfigs(r *scenarioRun, step *ScenarioStep) {
+	for i := 0; i < step.Count; i++ {
+		WriteConfigFile(i, i, step.Extra, r.t)
+	}
+}
+
+// scenarioAddNode boots a new follower from a copy of the database of Node
+func scenarioAddNode(r *scenarioRun, step *ScenarioStep) {
+	CloneFnodeData(step.Node, len(engine.GetFnodes()), r.t)
+	AddFNode()
+}
+
+func scenarioPromote(r *scenarioRun, step *ScenarioStep) {
+	RunCmd(fmt.Sprintf("%d", step.Node))
+	if step.Role == "leader" {
+		RunCmd("l")
+	} else {
+		RunCmd("o")
+	}
+}
+
+// scenarioCancelCoinbase has Node vote to cancel output Index of the coinbase declared at Height
+func scenarioCancelCoinbase(r *scenarioRun, step *ScenarioStep) {
+	RunCmd(fmt.Sprintf("%d", step.Node))
+	RunCmd(fmt.Sprintf("L%d.%d", step.Height, step.Index))
+}
+
+// scenarioNetState takes a node off the network or brings it back.  Unlike the x command it doesn't toggle,
+// so a scenario says what it means.
+func scenarioNetState(node int, off bool) {
+	s := engine.GetFnodes()[node].State
+	if off {
+		os.Stdout.WriteString("Take " + s.FactomNodeName + " off the network\n")
+	} else {
+		os.Stdout.WriteString("Bring " + s.FactomNodeName + " back onto the network\n")
+	}
+	s.SetNetStateOff(off)
+}
+
+func scenarioSendFCT(r *scenarioRun, step *ScenarioStep) {
+	s := scenarioState(step)
+	if _, err := engine.SendTxn(s, uint64(step.Amount), step.Secret, step.Address, s.GetFactoshisPerEC()); err != nil {
+		r.t.Fatalf("send-fct to %s: %v", step.Address, err)
+	}
+}
+
+// scenarioFilter sets the input or output message filter of the node the wsapi points at
+func scenarioFilter(r *scenarioRun, step *ScenarioStep, set func(string) (*http.Response, error)) {
+	resp, err := set(step.Regex)
+	if err != nil {
+		r.t.Fatalf("%s %q: %v", step.Action, step.Regex, err)
+	}
+	resp.Body.Close()
+}
+
+// scenarioSimCtrl runs a simControl command through the sim-ctrl call of the debug API of node 0
+func scenarioSimCtrl(r *scenarioRun, step *ScenarioStep) {
+	req := primitives.NewJSON2Request("sim-ctrl", 0, map[string][]string{"commands": {step.Cmd}})
+	j, err := json.Marshal(req)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/debug", r.state0.GetPort()), "application/json", bytes.NewBuffer(j))
+	if err != nil {
+		r.t.Fatalf("sim-ctrl %q: %v", step.Cmd, err)
+	}
+	defer resp.Body.Close()
+
+	res := primitives.NewJSON2Response()
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		r.t.Fatalf("sim-ctrl %q: %v", step.Cmd, err)
+	}
+	if res.Error != nil {
+		r.t.Fatalf("sim-ctrl %q: %v", step.Cmd, res.Error)
+	}
+}
+
+// scenarioWaitHolding waits a block at a time until the node holds no more than Count messages
+func scenarioWaitHolding(r *scenarioRun, step *ScenarioStep) {
+	s := scenarioState(step)
+	for s.Hold.GetSize() > step.Count || len(s.Holding) > step.Count {
+		WaitBlocks(s, 1)
+	}
+}
+
+func scenarioAssertLeader(r *scenarioRun, step *ScenarioStep, leader bool) {
+	s := scenarioState(step)
+	if s.Leader != leader {
+		r.t.Fatalf("%s leader is %v, expected %v", s.FactomNodeName, s.Leader, leader)
+	}
+}
+
+// scenarioAssertFocus checks the node simControl commands go to
+func scenarioAssertFocus(r *scenarioRun, step *ScenarioStep) {
+	name := engine.GetFocus().State.FactomNodeName
+	if name != fmt.Sprintf("FNode%02d", step.Node) {
+		r.t.Fatalf("The focus is on %s, expected node %d", name, step.Node)
+	}
+}
+
+func scenarioAssertSameHeight(r *scenarioRun, step *ScenarioStep) {
+	s, other := scenarioState(step), engine.GetFnodes()[step.Other].State
+	if s.GetLLeaderHeight() != other.GetLLeaderHeight() {
+		r.t.Fatalf("%s is at height %d, %s at %d", s.FactomNodeName, s.GetLLeaderHeight(), other.FactomNodeName, other.GetLLeaderHeight())
+	}
+}
+
+func scenarioAssertHeight(r *scenarioRun, step *ScenarioStep) {
+	s := scenarioState(step)
+	if int(s.GetDBHeightComplete()) < step.Height {
+		r.t.Fatalf("%s is at height %d, expected at least %d", s.FactomNodeName, s.GetDBHeightComplete(), step.Height)
+	}
+}
+
+// scenarioAssertBalance checks the balance of a Factoid (FA) or Entry Credit (EC) address on a node
+func scenarioAssertBalance(r *scenarioRun, step *ScenarioStep) {
+	s := scenarioState(step)
+	var balance int64
+	if strings.HasPrefix(step.Address, "EC") {
+		balance = engine.GetBalanceEC(s, step.Address)
+	} else {
+		balance = engine.GetBalance(s, step.Address)
+	}
+	if balance != step.Amount {
+		r.t.Fatalf("%s balance of %s is %d, expected %d", s.FactomNodeName, step.Address, balance, step.Amount)
+	}
+}
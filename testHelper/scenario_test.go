@@ -0,0 +1,96 @@
+package testHelper_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"good.yaml": `
+steps:
+  - action: start
+    nodes: LLAF
+    height: 10
+  - action: offline
+    node: 3
+    at: {block: 4, minute: 2}
+  - action: promote
+    node: 3
+    role: audit
+  - action: assert-authorities
+    nodes: LLAA
+`,
+		"good.json": `{"name": "json", "steps": [
+	{"action": "write-configs", "count": 2},
+	{"action": "start", "nodes": "LF"},
+	{"action": "wait-blocks", "count": 2},
+	{"action": "assert-balance", "address": "EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r", "amount": 0}
+]}`,
+		"unknown.yaml":    "steps:\n  - action: start\n    nodes: L\n  - action: explode\n",
+		"notstarted.yaml": "steps:\n  - action: wait-blocks\n    count: 1\n",
+		"twostarts.yaml":  "steps:\n  - action: start\n    nodes: L\n  - action: start\n    nodes: L\n",
+		"badrole.yaml":    "steps:\n  - action: start\n    nodes: LF\n  - action: promote\n    node: 1\n    role: king\n",
+		"badfield.yaml":   "steps:\n  - action: start\n    nodes: L\n    nodez: LF\n",
+		"nostart.json":    `{"steps": [{"action": "reset-home"}]}`,
+		"badfield.json":   `{"steps": [{"action": "start", "nodes": "L", "nodez": "LF"}]}`,
+		"nofilter.yaml":   "steps:\n  - action: start\n    nodes: L\n  - action: input-filter\n",
+		"nofunc.yaml":     "steps:\n  - action: start\n    nodes: L\n  - action: call\n",
+		"nosimctrl.yaml":  "steps:\n  - action: start\n    nodes: L\n  - action: sim-ctrl\n",
+		"nocoinbase.yaml": "steps:\n  - action: start\n    nodes: LF\n  - action: cancel-coinbase\n    node: 1\n",
+		"noexpect.yaml":   "steps:\n  - action: start\n    nodes: L\n  - action: expect-authorities\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sc, err := LoadScenario(filepath.Join(dir, "good.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Name != "good" || len(sc.Steps) != 4 {
+		t.Errorf("Bad scenario %v", sc)
+	}
+	if sc.Steps[1].At == nil || sc.Steps[1].At.Block != 4 || sc.Steps[1].At.Minute != 2 || sc.Steps[1].Node != 3 {
+		t.Errorf("Bad timed step %v", sc.Steps[1])
+	}
+
+	sc, err = LoadScenario(filepath.Join(dir, "good.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Name != "json" || len(sc.Steps) != 4 || sc.Steps[0].Count != 2 {
+		t.Errorf("Bad scenario %v", sc)
+	}
+
+	for _, name := range []string{"unknown.yaml", "notstarted.yaml", "twostarts.yaml", "badrole.yaml", "badfield.yaml", "nostart.json", "badfield.json", "nofilter.yaml",
+		"nofunc.yaml", "nosimctrl.yaml", "nocoinbase.yaml", "noexpect.yaml"} {
+		if _, err := LoadScenario(filepath.Join(dir, name)); err == nil {
+			t.Errorf("Expected an error loading %s", name)
+		}
+	}
+}
+
+func TestSimTestScenarios(t *testing.T) {
+	paths, err := filepath.Glob("../simTest/scenarios/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if _, err := LoadScenario(path); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -377,7 +377,7 @@ func StatusEveryMinute(s *state.State) {
 	}
 }
 
-// Wait till block = newBlock and minute = newMinute
+// Wait till block = newBlock and minute = newMinute, polling on the node clock so a simulated clock is kept up with
 func WaitForQuiet(s *state.State, newBlock int, newMinute int) {
 	//	fmt.Printf("%s: %d-:-%d WaitFor(%d-:-%d)\n", s.FactomNodeName, s.LLeaderHeight, s.CurrentMinute, newBlock, newMinute)
 	sleepTime := time.Duration(globals.Params.BlkTime) * 1000 / 40 // Figure out how long to sleep in milliseconds
@@ -388,7 +388,7 @@ func WaitForQuiet(s *state.State, newBlock int, newMinute int) {
 		x := int(s.LLeaderHeight)
 		// wait for the next block
 		for int(s.LLeaderHeight) == x {
-			time.Sleep(sleepTime * time.Millisecond) // wake up and about 4 times per minute
+			s.GetClock().Sleep(sleepTime * time.Millisecond) // wake up and about 4 times per minute
 		}
 		if int(s.LLeaderHeight) < newBlock {
 			TimeNow(s)
@@ -397,7 +397,7 @@ func WaitForQuiet(s *state.State, newBlock int, newMinute int) {
 
 	// wait for the right minute
 	for s.CurrentMinute != newMinute {
-		time.Sleep(sleepTime * time.Millisecond) // wake up and about 4 times per minute
+		s.GetClock().Sleep(sleepTime * time.Millisecond) // wake up and about 4 times per minute
 	}
 }
 
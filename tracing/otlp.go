@@ -0,0 +1,102 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	stopTimeout  = 10 * time.Second // Time allowed to flush the spans when stopping
+	instrumentID = "github.com/FactomProject/factomd"
+)
+
+// OTLPRecorder exports the spans as OpenTelemetry traces, one per message with a child span per stage.
+// The stages of a message are held until the span of the whole message ends, as that is the parent they
+// are exported under.
+type OTLPRecorder struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	stages   map[string][]Span // Ended stages by message hash, waiting on the span of the whole message
+}
+
+// NewOTLPRecorder exports the spans to the OTLP collector at endpoint (host:port of its gRPC receiver)
+func NewOTLPRecorder(endpoint string, node string) (*OTLPRecorder, error) {
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("Error starting the OTLP exporter: %v", err)
+	}
+	packageLogger.WithFields(log.Fields{"endpoint": endpoint}).Info("Exporting message traces")
+	return NewExporterRecorder(exporter, node), nil
+}
+
+// NewExporterRecorder sends the spans to any exporter, such as an in memory one for testing
+func NewExporterRecorder(exporter sdktrace.SpanExporter, node string) *OTLPRecorder {
+	r := new(OTLPRecorder)
+	r.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "factomd"),
+			attribute.String("service.instance.id", node))))
+	r.tracer = r.provider.Tracer(instrumentID)
+	r.stages = make(map[string][]Span)
+	return r
+}
+
+func (r *OTLPRecorder) Record(span Span) {
+	if span.Stage != "" {
+		r.stages[span.MsgHash] = append(r.stages[span.MsgHash], span)
+		return
+	}
+	stages := r.stages[span.MsgHash]
+	delete(r.stages, span.MsgHash)
+
+	ctx, root := r.tracer.Start(context.Background(), "message", trace.WithTimestamp(span.Start),
+		trace.WithAttributes(attributes(span)...))
+	for _, s := range stages {
+		_, child := r.tracer.Start(ctx, s.Stage, trace.WithTimestamp(s.Start), trace.WithAttributes(attributes(s)...))
+		child.End(trace.WithTimestamp(s.End))
+	}
+	root.SetAttributes(attribute.String("status", span.Status))
+	if span.Status == StatusDropped {
+		root.SetStatus(codes.Error, span.Reason)
+	}
+	root.End(trace.WithTimestamp(span.End))
+}
+
+// Close flushes the spans to the collector
+func (r *OTLPRecorder) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	if err := r.provider.Shutdown(ctx); err != nil {
+		packageLogger.WithError(err).Error("Failed to flush the message traces")
+	}
+}
+
+func attributes(span Span) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("msg_hash", span.MsgHash),
+		attribute.String("msg_type", span.MsgType),
+	}
+	if span.Height != 0 {
+		attrs = append(attrs, attribute.Int64("height", int64(span.Height)))
+	}
+	if span.Reason != "" {
+		attrs = append(attrs, attribute.String("reason", span.Reason))
+	}
+	return attrs
+}
@@ -0,0 +1,272 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package tracing follows factoid transactions, commits and reveals from the API or the network through
+// the queues, holding and the process list until the block holding them is saved.  Each message gets a
+// trace keyed by its message hash, with a span per stage and one for the whole message, and each span is
+// handed to a Recorder as it ends.  The node exports them over OTLP to a collector, or without one writes
+// them to its log, so the time spent in each stage can be broken down.
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+var packageLogger = log.WithFields(log.Fields{"package": "tracing"})
+
+const (
+	maxTraces   = 100000      // Most messages followed at once, more are not traced
+	maxTraceAge = time.Hour   // Traces not finished by then are ended as expired
+	expireSleep = time.Minute // Time between looking for expired traces
+)
+
+// Stages of a message, used as the span names
+const (
+	StageAPIQueue    = "api-queue"    // Submitted to the API, waiting for the network processor
+	StageInMsgQueue  = "in-msg-queue" // Waiting for the validator
+	StageHolding     = "holding"      // Waiting for its ack, or for what it depends on
+	StageProcessList = "process-list" // Acked, waiting to be processed
+	StageBlock       = "block"        // Processed, waiting for the block to be saved
+)
+
+// How a message's trace ended, the status of the span of the whole message
+const (
+	StatusSaved      = "saved"      // The block holding the message was saved
+	StatusDropped    = "dropped"    // The message was thrown away, or never finished within maxTraceAge
+	StatusUnfinished = "unfinished" // Tracing stopped before the message was saved or dropped
+)
+
+// Span is one stage of a message, or with an empty Stage, the whole of it from the first stage to the end
+type Span struct {
+	MsgHash string
+	MsgType string
+	Stage   string
+	Start   time.Time
+	End     time.Time
+	Height  uint32 // Block the message was processed into, zero if it wasn't
+	Status  string // Only set on the span of the whole message
+	Reason  string // Why the message was dropped
+}
+
+// Duration is the time the message spent in the span
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// A Recorder is given each span as it ends.  It is called with the tracing lock held, so it must not call
+// back into this package.
+type Recorder interface {
+	Record(span Span)
+}
+
+// A Recorder that is also a Closer is closed once tracing stops, outside of the lock
+type Closer interface {
+	Close()
+}
+
+// LogRecorder writes the spans to the log of the node, when there is no collector to export them to
+type LogRecorder struct {
+	Node string
+}
+
+func (r *LogRecorder) Record(span Span) {
+	fields := log.Fields{
+		"node":     r.Node,
+		"msg_hash": span.MsgHash,
+		"msg_type": span.MsgType,
+		"start":    span.Start.UnixNano() / int64(time.Millisecond),
+		"duration": span.Duration().Seconds(),
+	}
+	if span.Stage != "" {
+		fields["stage"] = span.Stage
+	}
+	if span.Height != 0 {
+		fields["height"] = span.Height
+	}
+	if span.Status != "" {
+		fields["status"] = span.Status
+	}
+	if span.Reason != "" {
+		fields["reason"] = span.Reason
+	}
+	packageLogger.WithFields(fields).Info("Message trace")
+}
+
+type msgTrace struct {
+	root    Span
+	stage   Span
+	inBlock bool // Processed into the block at root.Height, it can no longer be dropped
+}
+
+var (
+	mutex    sync.Mutex
+	recorder Recorder // nil while tracing is off
+	traces   = make(map[[32]byte]*msgTrace)
+	done     chan struct{}
+)
+
+// Start hands the spans to the recorder from now on
+func Start(r Recorder) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if recorder != nil {
+		close(done)
+	}
+	recorder = r
+	done = make(chan struct{})
+	go expire(done)
+}
+
+// Stop ends the traces still open as unfinished, and closes the recorder
+func Stop() {
+	mutex.Lock()
+	r := recorder
+	if r != nil {
+		now := time.Now()
+		for hash, t := range traces {
+			t.end(now, StatusUnfinished, "shutdown")
+			delete(traces, hash)
+		}
+		close(done)
+		recorder = nil
+	}
+	mutex.Unlock()
+
+	if c, ok := r.(Closer); ok {
+		c.Close()
+	}
+}
+
+// Enabled is true while spans are being recorded
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return recorder != nil
+}
+
+// Traced is true for the message types that are followed; the consensus messages are left out
+func Traced(msgType byte) bool {
+	switch msgType {
+	case constants.FACTOID_TRANSACTION_MSG, constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG,
+		constants.REVEAL_ENTRY_MSG:
+		return true
+	}
+	return false
+}
+
+// Stage ends the current stage of a message and starts the next one.  The trace is started by the first
+// stage seen.
+func Stage(msg interfaces.IMsg, stage string) {
+	if msg == nil || !Traced(msg.Type()) || msg.GetMsgHash() == nil {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if recorder == nil {
+		return
+	}
+
+	now := time.Now()
+	hash := msg.GetMsgHash().Fixed()
+	t := traces[hash]
+	if t == nil {
+		if len(traces) >= maxTraces {
+			return
+		}
+		t = new(msgTrace)
+		t.root = Span{MsgHash: msg.GetMsgHash().String(), MsgType: constants.MessageName(msg.Type()), Start: now}
+		traces[hash] = t
+	} else {
+		t.endStage(now)
+	}
+	t.stage = t.root
+	t.stage.Stage = stage
+	t.stage.Start = now
+}
+
+// Processed moves a message into StageBlock, where it waits for the block at dbheight to be saved
+func Processed(msg interfaces.IMsg, dbheight uint32) {
+	Stage(msg, StageBlock)
+	if msg == nil || msg.GetMsgHash() == nil {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if t := traces[msg.GetMsgHash().Fixed()]; t != nil {
+		t.root.Height = dbheight
+		t.stage.Height = dbheight
+		t.inBlock = true
+	}
+}
+
+// BlockSaved ends the traces of the messages processed into blocks up to dbheight
+func BlockSaved(dbheight uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if recorder == nil {
+		return
+	}
+	now := time.Now()
+	for hash, t := range traces {
+		if t.inBlock && t.root.Height <= dbheight {
+			t.end(now, StatusSaved, "")
+			delete(traces, hash)
+		}
+	}
+}
+
+// Drop ends the trace of a message that was thrown away.  A message already processed into a block is
+// not dropped, its trace ends once the block is saved.
+func Drop(msg interfaces.IMsg, reason string) {
+	if msg == nil || msg.GetMsgHash() == nil {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	hash := msg.GetMsgHash().Fixed()
+	if t := traces[hash]; t != nil && !t.inBlock {
+		t.end(time.Now(), StatusDropped, reason)
+		delete(traces, hash)
+	}
+}
+
+func (t *msgTrace) endStage(now time.Time) {
+	t.stage.End = now
+	recorder.Record(t.stage)
+}
+
+// end records the last stage and the whole message, with why it didn't make it into a block if it didn't
+func (t *msgTrace) end(now time.Time, status string, reason string) {
+	t.endStage(now)
+	t.root.End = now
+	t.root.Status = status
+	t.root.Reason = reason
+	recorder.Record(t.root)
+}
+
+// expire ends the traces of messages that were never saved nor dropped, so they don't pile up
+func expire(done chan struct{}) {
+	ticker := time.NewTicker(expireSleep)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			mutex.Lock()
+			for hash, t := range traces {
+				if now.Sub(t.root.Start) > maxTraceAge {
+					t.end(now, StatusDropped, "expired")
+					delete(traces, hash)
+				}
+			}
+			mutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package tracing_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type memoryRecorder struct {
+	spans []Span
+}
+
+func (r *memoryRecorder) Record(span Span) {
+	r.spans = append(r.spans, span)
+}
+
+func newCommit(credits uint8) *messages.CommitEntryMsg {
+	msg := messages.NewCommitEntryMsg()
+	msg.CommitEntry = entryCreditBlock.NewCommitEntry()
+	msg.CommitEntry.Credits = credits
+	msg.Sign(primitives.RandomPrivateKey())
+	return msg
+}
+
+func TestMessageStages(t *testing.T) {
+	recorder := new(memoryRecorder)
+	Start(recorder)
+	assert.True(t, Enabled())
+
+	saved, dropped, open := newCommit(1), newCommit(2), newCommit(3)
+	for _, stage := range []string{StageAPIQueue, StageInMsgQueue, StageHolding, StageProcessList} {
+		Stage(saved, stage)
+	}
+	Processed(saved, 5)
+	Stage(dropped, StageInMsgQueue)
+	Stage(open, StageHolding)
+	Stage(new(messages.EOM), StageInMsgQueue) // Not traced
+
+	BlockSaved(4)
+	Drop(dropped, "expired")
+	Drop(saved, "msg.Process done") // Already in the block, it stays open until the block is saved
+	BlockSaved(5)
+	Stop()
+	assert.False(t, Enabled())
+
+	names := make(map[string]int)
+	statuses := make(map[string]string)
+	for _, span := range recorder.spans {
+		if span.Stage == "" {
+			assert.Equal(t, "Commit Entry", span.MsgType)
+			statuses[span.MsgHash] = span.Status
+		} else {
+			assert.Equal(t, "", span.Status)
+		}
+		names[span.Stage]++
+		assert.False(t, span.End.Before(span.Start))
+	}
+	assert.Equal(t, 10, len(recorder.spans))
+	assert.Equal(t, 3, names[""])
+	assert.Equal(t, 1, names[StageAPIQueue])
+	assert.Equal(t, 2, names[StageInMsgQueue])
+	assert.Equal(t, 1, names[StageBlock])
+
+	assert.Equal(t, StatusSaved, statuses[saved.GetMsgHash().String()])
+	assert.Equal(t, StatusDropped, statuses[dropped.GetMsgHash().String()])
+	assert.Equal(t, StatusUnfinished, statuses[open.GetMsgHash().String()])
+}
+
+func TestOTLPRecorder(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	Start(NewExporterRecorder(exporter, "FNode0"))
+
+	saved, dropped := newCommit(1), newCommit(2)
+	Stage(saved, StageInMsgQueue)
+	Stage(dropped, StageInMsgQueue)
+	Processed(saved, 5)
+	Drop(dropped, "expired")
+	BlockSaved(5)
+	Stop() // Flushes the spans to the exporter
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 5, len(spans))
+	roots := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		if span.Name == "message" {
+			assert.False(t, span.Parent.IsValid())
+			roots[span.SpanContext.TraceID().String()] = span
+		}
+	}
+	assert.Equal(t, 2, len(roots))
+	for _, span := range spans {
+		root, ok := roots[span.SpanContext.TraceID().String()]
+		assert.True(t, ok)
+		if span.Name != "message" {
+			assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID())
+			assert.False(t, span.StartTime.Before(root.StartTime))
+			assert.False(t, span.EndTime.After(root.EndTime))
+		} else if span.Status.Code == codes.Error {
+			assert.Equal(t, "expired", span.Status.Description)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package clock is the time as nodes see it: the wall clock, or a simulated clock shared by the nodes of
+// a simulation that moves on only when all of them are idle, so a simulation runs as fast as the nodes
+// can do their work, and runs the same way each time.
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/util/atomic"
+)
+
+// Clock is the time as a node sees it
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	// Join makes the calling goroutine a worker: a simulated clock doesn't move on while it is busy, only
+	// while it sleeps or waits.  Workers must Sleep, or wait between Idle and Busy, when they have nothing
+	// to do.
+	Join()
+	// Leave undoes Join, when the worker stops
+	Leave()
+	// Idle marks the calling worker idle while it blocks on a channel, or on anything else another
+	// goroutine ends
+	Idle()
+	// Busy marks the calling worker busy again once the wait of Idle is over
+	Busy()
+}
+
+type wallClock struct{}
+
+// Real is the wall clock
+var Real Clock = wallClock{}
+
+func (wallClock) Now() time.Time                         { return time.Now() }
+func (wallClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (wallClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (wallClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (wallClock) Join()                                  {}
+func (wallClock) Leave()                                 {}
+func (wallClock) Idle()                                  {}
+func (wallClock) Busy()                                  {}
+
+// waiter is a goroutine sleeping, or a channel waiting, until a time
+type waiter struct {
+	at     time.Time
+	seq    uint64 // Waiters due at the same time wake in the order they started waiting
+	worker bool   // A worker sleeping, which is idle until woken
+	c      chan time.Time
+}
+
+// Simulated is a clock that only moves on when every worker is asleep, straight to the time the next
+// waiter is due.  With no workers it moves on whenever anything waits.
+type Simulated struct {
+	mutex   sync.Mutex
+	now     time.Time
+	workers map[string]bool // Goroutines that Joined, true while waiting between Idle and Busy
+	idle    int             // Workers asleep or waiting
+	waiters []*waiter
+	seq     uint64
+}
+
+var _ Clock = (*Simulated)(nil)
+
+// NewSimulated makes a simulated clock starting at the given time
+func NewSimulated(start time.Time) *Simulated {
+	c := new(Simulated)
+	c.now = start
+	c.workers = make(map[string]bool)
+	return c
+}
+
+func (c *Simulated) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *Simulated) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *Simulated) Sleep(d time.Duration) {
+	c.mutex.Lock()
+	_, worker := c.workers[atomic.Goid()]
+	ch := c.wait(d, worker)
+	c.mutex.Unlock()
+	<-ch
+}
+
+func (c *Simulated) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.wait(d, false)
+}
+
+func (c *Simulated) Join() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.workers[atomic.Goid()] = false
+}
+
+func (c *Simulated) Leave() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := atomic.Goid()
+	if c.workers[id] {
+		c.idle--
+	}
+	delete(c.workers, id)
+	c.advance()
+}
+
+// Idle lets the clock move on while the worker waits.  The time can move on to the next wake up between
+// a message being sent to the worker and the worker calling Busy, as it would between two nodes.
+func (c *Simulated) Idle() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := atomic.Goid()
+	if waiting, worker := c.workers[id]; !worker || waiting {
+		return
+	}
+	c.workers[id] = true
+	c.idle++
+	c.advance()
+}
+
+func (c *Simulated) Busy() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := atomic.Goid()
+	if !c.workers[id] {
+		return
+	}
+	c.workers[id] = false
+	c.idle--
+}
+
+// Advance moves the clock on, waking the waiters due by then, whether or not the workers are idle
+func (c *Simulated) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	c.wake()
+	c.advance()
+}
+
+// Workers returns how many goroutines have joined the clock and how many of them are asleep
+func (c *Simulated) Workers() (workers int, idle int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.workers), c.idle
+}
+
+// wait adds a waiter due after d.  Called with the mutex held.
+func (c *Simulated) wait(d time.Duration, worker bool) chan time.Time {
+	w := &waiter{at: c.now.Add(d), worker: worker, c: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- c.now
+		return w.c
+	}
+	c.seq++
+	w.seq = c.seq
+	c.waiters = append(c.waiters, w)
+	if worker {
+		c.idle++
+	}
+	c.advance()
+	return w.c
+}
+
+// advance moves the clock to the next waiter due while all the workers are idle.  It stops once a worker
+// wakes, as the time must not move on while it works.  Called with the mutex held.
+func (c *Simulated) advance() {
+	for c.idle >= len(c.workers) && len(c.waiters) > 0 {
+		next := c.waiters[0]
+		for _, w := range c.waiters[1:] {
+			if w.before(next) {
+				next = w
+			}
+		}
+		if next.at.After(c.now) {
+			c.now = next.at
+		}
+		if c.wake() {
+			return
+		}
+	}
+}
+
+// wake wakes the waiters due, in the order they are due, returning true if any was a worker.  Called
+// with the mutex held.
+func (c *Simulated) wake() (worker bool) {
+	var due, rest []*waiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			rest = append(rest, w)
+		} else {
+			due = append(due, w)
+		}
+	}
+	c.waiters = rest
+	for i := 1; i < len(due); i++ { // Few are ever due at once
+		for j := i; j > 0 && due[j].before(due[j-1]); j-- {
+			due[j], due[j-1] = due[j-1], due[j]
+		}
+	}
+	for _, w := range due {
+		if w.worker {
+			c.idle--
+			worker = true
+		}
+		w.c <- w.at
+	}
+	return worker
+}
+
+func (w *waiter) before(o *waiter) bool {
+	return w.at.Before(o.at) || (w.at.Equal(o.at) && w.seq < o.seq)
+}
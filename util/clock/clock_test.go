@@ -0,0 +1,131 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/util/clock"
+)
+
+var start = time.Unix(1500000000, 0)
+
+func TestSimulatedSleep(t *testing.T) {
+	c := NewSimulated(start)
+	begin := time.Now()
+	c.Sleep(time.Hour)
+	if time.Since(begin) > time.Second {
+		t.Error("Sleeping an hour of simulated time took real time")
+	}
+	if got := c.Since(start); got != time.Hour {
+		t.Errorf("Expected the clock an hour on, it is %v on", got)
+	}
+}
+
+func TestSimulatedAdvance(t *testing.T) {
+	c := NewSimulated(start)
+	c.Join() // Hold the clock, so only Advance moves it
+	defer c.Leave()
+	durations := []time.Duration{3 * time.Second, time.Second, 2 * time.Second}
+	var waiting []<-chan time.Time
+	for _, d := range durations {
+		waiting = append(waiting, c.After(d))
+	}
+	c.Advance(2 * time.Second)
+	for i, ch := range waiting {
+		select {
+		case at := <-ch:
+			if at.Sub(start) != durations[i] {
+				t.Errorf("Waiter %d woke at %v, expected %v", i, at.Sub(start), durations[i])
+			}
+			if durations[i] > 2*time.Second {
+				t.Errorf("Waiter %d woke before it was due", i)
+			}
+		default:
+			if durations[i] <= 2*time.Second {
+				t.Errorf("Waiter %d didn't wake", i)
+			}
+		}
+	}
+}
+
+func TestSimulatedWaitsForWorkers(t *testing.T) {
+	c := NewSimulated(start)
+	busy := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		c.Join()
+		defer c.Leave()
+		<-busy // Working until told to stop
+		c.Sleep(time.Minute)
+		done <- true
+	}()
+	for workers, _ := c.Workers(); workers == 0; workers, _ = c.Workers() {
+		time.Sleep(time.Millisecond)
+	}
+
+	slept := make(chan time.Time)
+	go func() {
+		slept <- <-c.After(time.Second)
+	}()
+	select {
+	case <-slept:
+		t.Fatal("The clock moved on while a worker was busy")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(busy)
+	if now := <-slept; now.Sub(start) != time.Second {
+		t.Errorf("Woke at %v, expected a second on", now.Sub(start))
+	}
+	<-done
+	if got := c.Since(start); got != time.Minute {
+		t.Errorf("Expected the clock a minute on, it is %v on", got)
+	}
+}
+
+func TestSimulatedWorkerWaiting(t *testing.T) {
+	c := NewSimulated(start)
+	waiting := make(chan bool)
+	work := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		c.Join()
+		defer c.Leave()
+		c.Idle()
+		waiting <- true
+		<-work // Waiting on a channel doesn't hold the clock
+		c.Busy()
+		<-work // Busy until told to stop
+		done <- true
+	}()
+	<-waiting
+
+	if now := <-c.After(time.Second); now.Sub(start) != time.Second {
+		t.Errorf("Woke at %v, expected a second on", now.Sub(start))
+	}
+
+	work <- true
+	for _, idle := c.Workers(); idle > 0; _, idle = c.Workers() {
+		time.Sleep(time.Millisecond)
+	}
+	slept := c.After(time.Second)
+	select {
+	case <-slept:
+		t.Fatal("The clock moved on while a worker was busy")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	work <- true
+	<-done
+	if now := <-slept; now.Sub(start) != 2*time.Second {
+		t.Errorf("Woke at %v, expected two seconds on", now.Sub(start))
+	}
+}
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	Real.Sleep(time.Millisecond)
+	if Real.Since(before) < time.Millisecond {
+		t.Error("The real clock didn't sleep")
+	}
+}
@@ -15,6 +15,14 @@ import (
 
 var _ = fmt.Print
 
+// APIKeyConfig is a named API key, [APIKey "name"] in the config file
+type APIKeyConfig struct {
+	Key               string  // The token clients send as "Authorization: Bearer <Key>"
+	Methods           string  // Comma separated list of read, submit, debug and method names
+	RequestsPerSecond float64 // Zero is unlimited
+	MaxConcurrent     int     // Zero is unlimited
+}
+
 type FactomdConfig struct {
 	App struct {
 		PortNumber                             int
@@ -73,6 +81,44 @@ type FactomdConfig struct {
 		RequestLimit   int
 
 		CorsDomains string
+		// Most requests allowed in a JSON-RPC batch
+		FactomdRpcMaxBatchSize int
+
+		// Comma separated list of chain IDs whose entries this node keeps.  Empty keeps all chains.
+		KeepEntryChains string
+		// Number of directory blocks behind the entry sync height to leave alone when pruning.  Zero disables pruning.
+		PruneRetention int
+		// Number of directory blocks a ledger may fall behind before its anchoring is reported as stalled.
+		AnchorStallThreshold int
+		// Port of the gRPC API.  Zero disables it.
+		GrpcPort int
+		// host:port of the OTLP collector message traces are sent to.  Empty logs them if TraceLog is set.
+		TraceEndpoint string
+		// Log the stages of each transaction, commit and reveal, from the API or the network to the saved block.
+		TraceLog bool
+		// Number of fastboot generations kept, the newest one consistent with the database is booted from.
+		FastBootGenerations int
+		// Keep the fastboot generations in the database rather than in files in FastBootLocation.
+		FastBootInDB bool
+		// Hex private key that signs a portable copy of each fastboot saved.  Empty signs none.
+		FastBootSigningKey string
+		// Portable fastboot a node with an empty database starts from, rather than from genesis.
+		FastBootImport string
+		// Comma separated hex public keys a portable fastboot may be signed by.
+		FastBootTrustedKeys string
+		// height:keymr of the directory block the portable fastboot must have been saved at.
+		FastBootCheckpoint string
+		// Comma separated Name=height activations overriding the compiled in heights on this network.
+		ActivationHeights string
+		// JSON file of activation heights by network name and activation name.
+		ActivationFile string
+		// JSON grant list paid on top of the hard coded grants, and the hex sha256 checksum it must have.
+		GrantFile         string
+		GrantFileChecksum string
+		// Chain of signed grant lists paid on top of the hard coded grants, and the comma separated hex
+		// public keys the lists may be signed by.
+		GrantChainID   string
+		GrantChainKeys string
 
 		ChangeAcksHeight uint32
 	}
@@ -109,6 +155,14 @@ type FactomdConfig struct {
 		WalletdLocation     string
 		WalletEncrypted     bool
 	}
+	Anchor struct {
+		Backend      string
+		Ledger       string
+		Path         string
+		SigningKey   string
+		ECPrivateKey string
+	}
+	APIKey      map[string]*APIKeyConfig
 	LiveFeedAPI struct {
 		EnableLiveFeedAPI        bool
 		EventReceiverProtocol    string
@@ -198,6 +252,64 @@ RequestLimit						= 200
 ; Example paramaters are "http://www.example.com, http://anotherexample.com, *"
 CorsDomains                           = ""
 
+; The API accepts JSON-RPC 2.0 batches (an array of requests) of up to this many requests
+FactomdRpcMaxBatchSize                = 100
+
+; Only keep the entries of the listed chains (comma separated chain IDs).  Entry blocks, and the entries
+; of identity, anchor and FER chains, are always kept.  Leave empty to keep every entry.
+KeepEntryChains                       = ""
+
+; On a node with KeepEntryChains set, remove the entries of the chains not kept from blocks more than
+; PruneRetention blocks old, including entries synced before the setting.  0 disables pruning.
+PruneRetention                        = 0
+
+; Report anchoring as stalled (the factomd_state_anchor_stalled gauge) when a ledger's latest anchor is
+; more than AnchorStallThreshold directory blocks behind.  0 disables the check.
+AnchorStallThreshold                  = 36
+
+; Serve the gRPC API (see grpcapi/factomd.proto) on this port.  It uses the TLS settings, rpc user and
+; API keys of the JSON-RPC API.  0 disables it.
+GrpcPort                              = 0
+
+; Send OpenTelemetry traces of the path of each transaction, commit and reveal, from the API or the
+; network to the saved block, to the gRPC receiver of an OTLP collector, e.g. localhost:4317.  Each stage
+; it waits in (API queue, in message queue, holding, process list, block) is a span of the message.
+TraceEndpoint                         = ""
+; Without a TraceEndpoint, log the spans instead: each stage with its start and duration when it ends,
+; and the whole message when it is saved or dropped.
+TraceLog                              = false
+
+; Keep the last FastBootGenerations fastboot saves.  At boot the newest one matching the directory
+; blocks in the database is loaded, falling back to older ones if it is corrupt or from a fork.
+; FastBootInDB keeps them in the database instead of in files in FastBootLocation.
+FastBootGenerations                   = 3
+FastBootInDB                          = false
+
+; With a FastBootSigningKey each fastboot saved is also written signed, to FastBoot_<net>_v<n>_portable.db,
+; for new nodes to start from.  Such a node sets FastBootImport to the file; it is checked to be signed by
+; one of FastBootTrustedKeys and to have been saved at the FastBootCheckpoint directory block, given as
+; height:keymr, and its balances are checked against the leaders' acks once the node is following.  The
+; database has no blocks below the import, so such a node always boots with FastBoot.
+FastBootSigningKey                    = ""
+FastBootImport                        = ""
+FastBootTrustedKeys                   = ""
+FastBootCheckpoint                    = ""
+
+; Override the heights features activate at on this network, e.g. AuthorityMaxDelta=1000,TestNetCoinBasePeriod=never,
+; or for any network from an ActivationFile such as {"CUSTOM:mynet": {"AuthorityMaxDelta": 1000}}.  Activations
+; must stay in order; the schedule in use is shown by the "activations" debug API method.
+ActivationHeights                     = ""
+ActivationFile                        = ""
+
+; Pay grants on top of the hard coded ones, either from a JSON list of {"height", "amount", "address"} in a
+; GrantFile with the sha256 GrantFileChecksum, or from grant lists signed by one of the GrantChainKeys in
+; the entries of the GrantChainID.  A list disagreeing with the grants already set for a height is ignored.
+; The grant chain is always kept by a chain subset node.  These are only read at boot.
+GrantFile                             = ""
+GrantFileChecksum                     = ""
+GrantChainID                          = ""
+GrantChainKeys                        = ""
+
 ; Specifying when to change ACKs for switching leader servers
 ChangeAcksHeight                      = 0
 
@@ -238,6 +350,20 @@ WalletdLocation                       = "localhost:8089"
 ; cannot exist. If an unencrypted database exists, the wallet will exit.
 WalletEncrypted                       = false
 
+; ------------------------------------------------------------------------------
+; In-process anchoring, for networks without an anchor service.  Backend selects the
+; anchor backend ("file" writes a local mock chain); leave it empty to disable.
+; Ledger is bitcoin or ethereum and picks the anchor chain the records go into.
+; The public key of SigningKey must be in the matching *AnchorRecordPublicKeys list,
+; and the EC address of ECPrivateKey pays for the anchor entries.
+; ------------------------------------------------------------------------------
+[Anchor]
+Backend                               = ""
+Ledger                                = bitcoin
+Path                                  = ""
+SigningKey                            = ""
+ECPrivateKey                          = ""
+
 ; ------------------------------------------------------------------------------
 ; Configuration options for the live feed API
 ; ------------------------------------------------------------------------------
@@ -298,7 +424,26 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsPrivateKey     %v", s.App.FactomdTlsPrivateKey))
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsPublicCert     %v", s.App.FactomdTlsPublicCert))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcUser          	%v", s.App.FactomdRpcUser))
+	out.WriteString(fmt.Sprintf("\n    FactomdRpcMaxBatchSize   %v", s.App.FactomdRpcMaxBatchSize))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcPass          	%v", s.App.FactomdRpcPass))
+	out.WriteString(fmt.Sprintf("\n    KeepEntryChains          %v", s.App.KeepEntryChains))
+	out.WriteString(fmt.Sprintf("\n    PruneRetention           %v", s.App.PruneRetention))
+	out.WriteString(fmt.Sprintf("\n    AnchorStallThreshold     %v", s.App.AnchorStallThreshold))
+	out.WriteString(fmt.Sprintf("\n    GrpcPort                 %v", s.App.GrpcPort))
+	out.WriteString(fmt.Sprintf("\n    TraceEndpoint            %v", s.App.TraceEndpoint))
+	out.WriteString(fmt.Sprintf("\n    TraceLog                 %v", s.App.TraceLog))
+	out.WriteString(fmt.Sprintf("\n    FastBootGenerations      %v", s.App.FastBootGenerations))
+	out.WriteString(fmt.Sprintf("\n    FastBootInDB             %v", s.App.FastBootInDB))
+	out.WriteString(fmt.Sprintf("\n    FastBootSigningKey       %v", s.App.FastBootSigningKey != ""))
+	out.WriteString(fmt.Sprintf("\n    FastBootImport           %v", s.App.FastBootImport))
+	out.WriteString(fmt.Sprintf("\n    FastBootTrustedKeys      %v", s.App.FastBootTrustedKeys))
+	out.WriteString(fmt.Sprintf("\n    FastBootCheckpoint       %v", s.App.FastBootCheckpoint))
+	out.WriteString(fmt.Sprintf("\n    ActivationHeights        %v", s.App.ActivationHeights))
+	out.WriteString(fmt.Sprintf("\n    ActivationFile           %v", s.App.ActivationFile))
+	out.WriteString(fmt.Sprintf("\n    GrantFile                %v", s.App.GrantFile))
+	out.WriteString(fmt.Sprintf("\n    GrantFileChecksum        %v", s.App.GrantFileChecksum))
+	out.WriteString(fmt.Sprintf("\n    GrantChainID             %v", s.App.GrantChainID))
+	out.WriteString(fmt.Sprintf("\n    GrantChainKeys           %v", s.App.GrantChainKeys))
 	out.WriteString(fmt.Sprintf("\n    ChangeAcksHeight         %v", s.App.ChangeAcksHeight))
 	out.WriteString(fmt.Sprintf("\n    BitcoinAnchorRecordPublicKeys    %v", s.App.BitcoinAnchorRecordPublicKeys))
 	out.WriteString(fmt.Sprintf("\n    EthereumAnchorRecordPublicKeys    %v", s.App.EthereumAnchorRecordPublicKeys))
@@ -318,6 +463,16 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    WalletdLocation         %v", s.Walletd.WalletdLocation))
 	out.WriteString(fmt.Sprintf("\n    WalletEncryption        %v", s.Walletd.WalletEncrypted))
 
+	out.WriteString(fmt.Sprintf("\n  APIKeys"))
+	for name, key := range s.APIKey {
+		out.WriteString(fmt.Sprintf("\n    %-24s Methods %q, RequestsPerSecond %v, MaxConcurrent %v", name, key.Methods, key.RequestsPerSecond, key.MaxConcurrent))
+	}
+
+	out.WriteString(fmt.Sprintf("\n  Anchor"))
+	out.WriteString(fmt.Sprintf("\n    Backend                  %v", s.Anchor.Backend))
+	out.WriteString(fmt.Sprintf("\n    Ledger                   %v", s.Anchor.Ledger))
+	out.WriteString(fmt.Sprintf("\n    Path                     %v", s.Anchor.Path))
+
 	out.WriteString(fmt.Sprintf("\n  LiveFeedAPI"))
 	out.WriteString(fmt.Sprintf("\n    EnableLiveFeedAPI        %v", s.LiveFeedAPI.EnableLiveFeedAPI))
 	out.WriteString(fmt.Sprintf("\n    EventReceiverProtocol    %v", s.LiveFeedAPI.EventReceiverProtocol))
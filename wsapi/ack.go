@@ -75,6 +75,7 @@ func HandleV2FactoidACK(state interfaces.IState, params interface{}) (interface{
 	if answer.Status == "na" {
 		return nil, NewInternalError()
 	}
+	answer.setRejection(state, txhash, constants.FACTOID_TRANSACTION_MSG)
 
 	return answer, nil
 }
@@ -149,6 +150,7 @@ func HandleV2ACKWithChain(state interfaces.IState, params interface{}) (interfac
 		}
 
 		answer.CommitData.Status = constants.AckStatusString(status)
+		answer.CommitData.setRejection(state, hash, constants.COMMIT_ENTRY_MSG, constants.COMMIT_CHAIN_MSG)
 		return answer, nil
 	case hex.EncodeToString(constants.FACTOID_CHAINID):
 		// This is a factoid transaction, just use the old implementation for now
@@ -227,6 +229,10 @@ func handleAckByEntryHash(hash interfaces.IHash, state interfaces.IState) (inter
 		}
 	}
 
+	// Commits are also recorded under their entry hash
+	answer.EntryData.setRejection(state, hash, constants.REVEAL_ENTRY_MSG)
+	answer.CommitData.setRejection(state, hash, constants.COMMIT_ENTRY_MSG, constants.COMMIT_CHAIN_MSG)
+
 	return answer, nil
 }
 
@@ -450,6 +456,11 @@ func HandleV2EntryACK(state interfaces.IState, params interface{}) (interface{},
 		//We know nothing about the transaction, so we return unknown status
 		answer.CommitData.Status = AckStatusUnknown
 		answer.EntryData.Status = AckStatusUnknown
+		// but we may know why it was dropped
+		if h, err := primitives.NewShaHashFromStr(ackReq.TxID); err == nil {
+			answer.CommitData.setRejection(state, h, constants.COMMIT_ENTRY_MSG, constants.COMMIT_CHAIN_MSG)
+			answer.EntryData.setRejection(state, h, constants.REVEAL_ENTRY_MSG)
+		}
 		return answer, nil
 	}
 
@@ -588,6 +599,14 @@ func HandleV2EntryACK(state interfaces.IState, params interface{}) (interface{},
 		}
 	}
 
+	if h, err := primitives.NewShaHashFromStr(answer.CommitTxID); err == nil {
+		answer.CommitData.setRejection(state, h, constants.COMMIT_ENTRY_MSG, constants.COMMIT_CHAIN_MSG)
+	}
+	if h, err := primitives.NewShaHashFromStr(answer.EntryHash); err == nil {
+		answer.EntryData.setRejection(state, h, constants.REVEAL_ENTRY_MSG)
+		answer.CommitData.setRejection(state, h, constants.COMMIT_ENTRY_MSG, constants.COMMIT_CHAIN_MSG)
+	}
+
 	return answer, nil
 }
 
@@ -665,6 +684,38 @@ type GeneralTransactionData struct {
 
 	Malleated *Malleated `json:"malleated,omitempty"`
 	Status    string     `json:"status"`
+	Rejection *Rejection `json:"rejection,omitempty"` // Why the node last dropped it, if it isn't acknowledged
+}
+
+type Rejection struct {
+	Code       string `json:"code"`
+	Reason     string `json:"reason"`
+	Date       int64  `json:"date"`       //Unix time in milliseconds
+	DateString string `json:"datestring"` //ISO8601 time
+}
+
+// setRejection adds the reason a message was last dropped.  Nothing is added once the message is
+// acknowledged, as a copy that was dropped no longer matters.
+func (d *GeneralTransactionData) setRejection(state interfaces.IState, hash interfaces.IHash, msgTypes ...byte) {
+	switch d.Status {
+	case AckStatusACK, AckStatus1Minute, AckStatusDBlockConfirmed:
+		return
+	}
+	if d.Rejection != nil {
+		return
+	}
+	for _, t := range msgTypes {
+		code, reason, when := state.GetMsgRejection(hash, t)
+		if code == "" {
+			continue
+		}
+		d.Rejection = &Rejection{Code: code, Reason: reason}
+		if when != nil {
+			d.Rejection.Date = when.GetTimeMilli()
+			d.Rejection.DateString = when.String()
+		}
+		return
+	}
 }
 
 type Malleated struct {
@@ -0,0 +1,306 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+)
+
+// Named API keys from the config file.  A key gives access to a set of methods, and limits how fast and how
+// many requests at once the client may make.  Without any keys, access is as before: open, or all or nothing
+// with the rpc user and password.
+
+// The groups of methods a key can be given
+const (
+	APIPermissionRead   = "read"   // The V1 and V2 API, less the submit methods
+	APIPermissionSubmit = "submit" // Methods that put something on the network
+	APIPermissionDebug  = "debug"  // The /debug API
+)
+
+// The APIs a method can be called on
+const (
+	APIEndpointV2    = "v2" // V1 calls are run as V2 methods
+	APIEndpointDebug = "debug"
+)
+
+var submitMethods = map[string]bool{
+	"commit-chain":     true,
+	"commit-entry":     true,
+	"reveal-chain":     true,
+	"reveal-entry":     true,
+	"factoid-submit":   true,
+	"send-raw-message": true,
+}
+
+type APIKey struct {
+	Name          string
+	hash          []byte          // sha256 of the key, compared in constant time
+	permissions   map[string]bool // read, submit, debug
+	methods       map[string]bool // Individual methods
+	maxConcurrent int
+
+	mutex      sync.Mutex
+	rate       float64 // Requests per second, 0 is unlimited
+	tokens     float64
+	lastRefill time.Time
+	active     int
+}
+
+var apiKeysMutex sync.RWMutex
+var apiKeys []*APIKey
+
+type apiKeyContextKey struct{}
+
+// NewAPIKey creates a key from its config file entry
+func NewAPIKey(name string, cfg *util.APIKeyConfig) (*APIKey, error) {
+	if len(cfg.Key) == 0 {
+		return nil, fmt.Errorf("api key %s has no Key", name)
+	}
+	if cfg.RequestsPerSecond < 0 || cfg.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("api key %s has a negative limit", name)
+	}
+
+	k := new(APIKey)
+	k.Name = name
+	hash := sha256.Sum256([]byte(cfg.Key))
+	k.hash = hash[:]
+	k.permissions = make(map[string]bool)
+	k.methods = make(map[string]bool)
+	for _, m := range strings.Split(cfg.Methods, ",") {
+		m = strings.TrimSpace(m)
+		switch m {
+		case "":
+		case APIPermissionRead, APIPermissionSubmit, APIPermissionDebug:
+			k.permissions[m] = true
+		default:
+			k.methods[m] = true
+		}
+	}
+	k.maxConcurrent = cfg.MaxConcurrent
+	k.rate = cfg.RequestsPerSecond
+	k.tokens = k.burst()
+	k.lastRefill = time.Now()
+	return k, nil
+}
+
+// LoadAPIKeys replaces the API keys with the ones in the config file.  Called at startup and by
+// reload-configuration.
+func LoadAPIKeys(state interfaces.IState) error {
+	cfg, ok := state.GetCfg().(*util.FactomdConfig)
+	if !ok || cfg == nil {
+		return nil
+	}
+
+	var keys []*APIKey
+	for name, kc := range cfg.APIKey {
+		k, err := NewAPIKey(name, kc)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	SetAPIKeys(keys)
+	return nil
+}
+
+// SetAPIKeys replaces the API keys
+func SetAPIKeys(keys []*APIKey) {
+	apiKeysMutex.Lock()
+	defer apiKeysMutex.Unlock()
+	apiKeys = keys
+}
+
+func findAPIKey(token string) *APIKey {
+	hash := sha256.Sum256([]byte(token))
+	apiKeysMutex.RLock()
+	defer apiKeysMutex.RUnlock()
+	var found *APIKey
+	for _, k := range apiKeys {
+		// Look at every key, so the time taken doesn't tell which key is close
+		if subtle.ConstantTimeCompare(hash[:], k.hash) == 1 {
+			found = k
+		}
+	}
+	return found
+}
+
+func haveAPIKeys() bool {
+	apiKeysMutex.RLock()
+	defer apiKeysMutex.RUnlock()
+	return len(apiKeys) > 0
+}
+
+// authenticate works out who is making a request.  A nil key with no error is full access: either the API
+// is open, or the rpc user and password were given.
+func authenticate(state interfaces.IState, request *http.Request) (*APIKey, error) {
+	if !haveAPIKeys() {
+		return nil, checkAuthHeader(state, request)
+	}
+
+	authhdr := request.Header.Get("Authorization")
+	if strings.HasPrefix(authhdr, "Bearer ") {
+		key := findAPIKey(strings.TrimSpace(strings.TrimPrefix(authhdr, "Bearer ")))
+		if key == nil {
+			APIKeyRequests.WithLabelValues("unknown", "unauthorized").Inc()
+			return nil, errors.New("bad api key")
+		}
+		return key, nil
+	}
+	if state.GetRpcUser() == "" {
+		return nil, errors.New("no api key")
+	}
+	return nil, checkAuthHeader(state, request)
+}
+
+// AuthorizeCall checks a call made other than over HTTP, such as on the gRPC API, given the Authorization
+// header sent with it and the V2 method it is run as.  The call counts against the concurrency limit of the
+// key until release is called, which must be done once it returns.
+func AuthorizeCall(state interfaces.IState, authorization string, method string) (release func(), jsonError *primitives.JSONError) {
+	request := &http.Request{Header: make(http.Header)}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	key, err := authenticate(state, request)
+	if err != nil {
+		return nil, NewUnauthorizedError(err.Error())
+	}
+	if jsonError := key.authorize(APIEndpointV2, method); jsonError != nil {
+		return nil, jsonError
+	}
+	if !key.acquire() {
+		return nil, NewRateLimitError(fmt.Sprintf("%d requests at once", key.maxConcurrent))
+	}
+	return key.release, nil
+}
+
+func withAPIKey(request *http.Request, key *APIKey) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), apiKeyContextKey{}, key))
+}
+
+func apiKeyFromRequest(request *http.Request) *APIKey {
+	key, _ := request.Context().Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
+
+// Allows returns true if the key may call the method on the endpoint.  A nil key may call anything.
+func (k *APIKey) Allows(endpoint string, method string) bool {
+	if k == nil || k.methods[method] {
+		return true
+	}
+	switch {
+	case endpoint == APIEndpointDebug:
+		return k.permissions[APIPermissionDebug]
+	case submitMethods[method]:
+		return k.permissions[APIPermissionSubmit]
+	default:
+		return k.permissions[APIPermissionRead]
+	}
+}
+
+// authorize checks the method against the permissions and the request rate of the key
+func (k *APIKey) authorize(endpoint string, method string) *primitives.JSONError {
+	if k == nil {
+		return nil
+	}
+	if !k.Allows(endpoint, method) {
+		APIKeyRequests.WithLabelValues(k.Name, "denied").Inc()
+		return NewMethodNotAllowedError(method)
+	}
+	if !k.take() {
+		APIKeyRequests.WithLabelValues(k.Name, "limited").Inc()
+		return NewRateLimitError(fmt.Sprintf("%v requests per second", k.rate))
+	}
+	APIKeyRequests.WithLabelValues(k.Name, "ok").Inc()
+	return nil
+}
+
+// handler wraps a JSON request handler with the checks of the key, for the requests of a batch
+func (k *APIKey) handler(endpoint string, handler JSONRequestHandler) JSONRequestHandler {
+	if k == nil {
+		return handler
+	}
+	return func(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
+		if jsonError := k.authorize(endpoint, j.Method); jsonError != nil {
+			return nil, jsonError
+		}
+		return handler(state, j)
+	}
+}
+
+func (k *APIKey) burst() float64 {
+	if k.rate < 1 {
+		return 1
+	}
+	return k.rate
+}
+
+// take removes a token from the bucket, returns false if there are none
+func (k *APIKey) take() bool {
+	if k.rate == 0 {
+		return true
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	now := time.Now()
+	k.tokens += now.Sub(k.lastRefill).Seconds() * k.rate
+	if k.tokens > k.burst() {
+		k.tokens = k.burst()
+	}
+	k.lastRefill = now
+
+	if k.tokens < 1 {
+		return false
+	}
+	k.tokens--
+	return true
+}
+
+// acquire counts a request against the concurrency limit of the key, returns false if over the limit
+func (k *APIKey) acquire() bool {
+	if k == nil {
+		return true
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.maxConcurrent > 0 && k.active >= k.maxConcurrent {
+		APIKeyRequests.WithLabelValues(k.Name, "limited").Inc()
+		return false
+	}
+	k.active++
+	APIKeyConcurrent.WithLabelValues(k.Name).Set(float64(k.active))
+	return true
+}
+
+func (k *APIKey) release() {
+	if k == nil {
+		return
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.active--
+	APIKeyConcurrent.WithLabelValues(k.Name).Set(float64(k.active))
+}
+
+func handleTooManyRequests(writer http.ResponseWriter, key *APIKey) {
+	resp := primitives.NewJSON2Response()
+	resp.Error = NewRateLimitError(fmt.Sprintf("%d requests at once", key.maxConcurrent))
+	writer.WriteHeader(http.StatusTooManyRequests)
+	if _, err := writer.Write([]byte(resp.String())); err != nil {
+		wsLog.Errorf("failed to write error response: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package wsapi_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/FactomProject/factomd/util"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestAPIKeyAllows(t *testing.T) {
+	_, err := NewAPIKey("nokey", &util.APIKeyConfig{Methods: "read"})
+	assert.NotNil(t, err)
+	_, err = NewAPIKey("negative", &util.APIKeyConfig{Key: "k", RequestsPerSecond: -1})
+	assert.NotNil(t, err)
+
+	key, err := NewAPIKey("partner", &util.APIKeyConfig{Key: "k", Methods: "read, submit, prune-status"})
+	assert.Nil(t, err)
+	assert.True(t, key.Allows(APIEndpointV2, "properties"))
+	assert.True(t, key.Allows(APIEndpointV2, "commit-entry"))
+	assert.True(t, key.Allows(APIEndpointDebug, "prune-status"))
+	assert.False(t, key.Allows(APIEndpointDebug, "sim-ctrl"))
+
+	key, err = NewAPIKey("reader", &util.APIKeyConfig{Key: "k", Methods: "read"})
+	assert.Nil(t, err)
+	assert.True(t, key.Allows(APIEndpointV2, "entry"))
+	assert.False(t, key.Allows(APIEndpointV2, "factoid-submit"))
+	assert.False(t, key.Allows(APIEndpointDebug, "holding-queue"))
+
+	var full *APIKey // rpc user, or an open API
+	assert.True(t, full.Allows(APIEndpointDebug, "sim-ctrl"))
+}
+
+func TestAPIKeyRequests(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	state := testHelper.CreateAndPopulateTestState()
+	state.RpcUser = "user"
+	state.RpcPass = "password"
+	state.SetPort(18089)
+	Start(state)
+
+	var keys []*APIKey
+	for name, cfg := range map[string]*util.APIKeyConfig{
+		"reader":  {Key: "reader-key", Methods: "read"},
+		"debug":   {Key: "debug-key", Methods: "debug"},
+		"limited": {Key: "limited-key", Methods: "read", RequestsPerSecond: 0.001},
+	} {
+		key, err := NewAPIKey(name, cfg)
+		assert.Nil(t, err)
+		keys = append(keys, key)
+	}
+	SetAPIKeys(keys)
+	defer SetAPIKeys(nil)
+
+	v2 := "http://localhost:18089/v2"
+	debug := "http://localhost:18089/debug"
+	properties := primitives.NewJSON2Request("properties", 0, "")
+	commit := primitives.NewJSON2Request("commit-entry", 0, MessageRequest{Message: "00"})
+	pruneStatus := primitives.NewJSON2Request("prune-status", 0, "")
+
+	cases := map[string]struct {
+		Url       string
+		Token     string
+		BasicAuth bool
+		Request   *primitives.JSON2Request
+		Status    int
+		ErrorCode int
+	}{
+		"no-auth":          {v2, "", false, properties, http.StatusUnauthorized, 0},
+		"bad-key":          {v2, "wrong-key", false, properties, http.StatusUnauthorized, 0},
+		"rpc-user":         {debug, "", true, pruneStatus, http.StatusOK, 0},
+		"reader":           {v2, "reader-key", false, properties, http.StatusOK, 0},
+		"reader-submit":    {v2, "reader-key", false, commit, http.StatusBadRequest, NewMethodNotAllowedError(nil).Code},
+		"reader-debug":     {debug, "reader-key", false, pruneStatus, http.StatusBadRequest, NewMethodNotAllowedError(nil).Code},
+		"debug":            {debug, "debug-key", false, pruneStatus, http.StatusOK, 0},
+		"debug-v2":         {v2, "debug-key", false, properties, http.StatusBadRequest, NewMethodNotAllowedError(nil).Code},
+		"limited-first":    {v2, "limited-key", false, properties, http.StatusOK, 0},
+		"limited-too-fast": {v2, "limited-key", false, properties, http.StatusBadRequest, NewRateLimitError(nil).Code},
+	}
+
+	client := &http.Client{}
+	// The limited key has to be used in order
+	for _, name := range []string{"no-auth", "bad-key", "rpc-user", "reader", "reader-submit", "reader-debug", "debug", "debug-v2", "limited-first", "limited-too-fast"} {
+		testCase := cases[name]
+		request, err := http.NewRequest("POST", testCase.Url, body(testCase.Request))
+		assert.Nil(t, err)
+		if testCase.BasicAuth {
+			request.SetBasicAuth("user", "password")
+		}
+		if testCase.Token != "" {
+			request.Header.Set("Authorization", "Bearer "+testCase.Token)
+		}
+
+		response, err := client.Do(request)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+		data, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		assert.Equal(t, testCase.Status, response.StatusCode, "%s: %s", name, string(data))
+
+		if testCase.ErrorCode != 0 {
+			resp := primitives.NewJSON2Response()
+			assert.Nil(t, json.Unmarshal(data, resp), name)
+			if assert.NotNil(t, resp.Error, name) {
+				assert.Equal(t, testCase.ErrorCode, resp.Error.Code, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// JSON-RPC 2.0 batches (https://www.jsonrpc.org/specification#batch).  A batch is an array of requests; the
+// response is an array with one response for every request that isn't a notification.  Requests that only
+// read are run concurrently.  Requests that change something, or wait on the network, are run one at a time
+// in the order they appear in the batch.
+
+const batchWorkers = 8 // Most read only requests of a batch we run at the same time
+
+// DefaultRpcMaxBatchSize is used when no maximum is configured
+const DefaultRpcMaxBatchSize = 100
+
+// JSONRequestHandler runs one JSON-RPC request, i.e. HandleV2JSONRequest or HandleDebugRequest
+type JSONRequestHandler func(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError)
+
+// Methods that must run one at a time and in order when they are part of a batch
+var sequentialMethods = map[string]bool{
+	// v2
+	"commit-chain":       true,
+	"commit-entry":       true,
+	"reveal-chain":       true,
+	"reveal-entry":       true,
+	"factoid-submit":     true,
+	"send-raw-message":   true,
+	"replay-from-height": true,
+	// debug
+	"set-delay":            true,
+	"set-drop-rate":        true,
+	"set-logging":          true,
+	"write-configuration":  true,
+	"reload-configuration": true,
+	"sim-ctrl":             true,
+	"wait-blocks":          true,
+	"wait-for-block":       true,
+	"wait-minutes":         true,
+	"wait-for-minute":      true,
+	"message-filter":       true,
+}
+
+// IsBatchRequest returns true if the body of a request is a JSON array
+func IsBatchRequest(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// HandleBatch writes the response to a batch request
+func HandleBatch(writer http.ResponseWriter, state interfaces.IState, body []byte, handler JSONRequestHandler) {
+	n := time.Now()
+	defer HandleBatchCall.Observe(float64(time.Since(n).Nanoseconds()))
+
+	responses, jsonError := HandleBatchRequest(state, body, handler)
+	if jsonError != nil {
+		HandleV2Error(writer, nil, jsonError)
+		return
+	}
+	if len(responses) == 0 {
+		// All notifications, the spec says we return nothing at all
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	data, err := json.Marshal(responses)
+	if err != nil {
+		wsLog.Errorf("failed to marshal batch response: %v", err)
+		HandleV2Error(writer, nil, NewInternalError())
+		return
+	}
+	if _, err := writer.Write(data); err != nil {
+		wsLog.Errorf("failed to write batch response: %v", err)
+	}
+}
+
+// HandleBatchRequest runs every request of a batch, and returns the responses in the order of the requests.
+// An error is returned only if the batch as a whole is invalid.
+func HandleBatchRequest(state interfaces.IState, body []byte, handler JSONRequestHandler) ([]*primitives.JSON2Response, *primitives.JSONError) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, NewParseError()
+	}
+	if len(raw) == 0 {
+		return nil, NewInvalidRequestError()
+	}
+	max := state.GetRpcMaxBatchSize()
+	if max <= 0 {
+		max = DefaultRpcMaxBatchSize
+	}
+	if len(raw) > max {
+		BatchRejected.Inc()
+		return nil, NewBatchTooLargeError(fmt.Sprintf("batch of %d requests, the limit is %d", len(raw), max))
+	}
+	BatchSize.Observe(float64(len(raw)))
+
+	responses := make([]*primitives.JSON2Response, len(raw))
+	notification := make([]bool, len(raw))
+
+	var wg sync.WaitGroup
+	reads := make(chan int)
+	for w := 0; w < batchWorkers && w < len(raw); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range reads {
+				responses[i] = runBatchItem(state, raw[i], handler, &notification[i])
+			}
+		}()
+	}
+
+	// Hand out the reads, and run the writes here as we come to them
+	for i := range raw {
+		if sequentialMethods[batchMethod(raw[i])] {
+			responses[i] = runBatchItem(state, raw[i], handler, &notification[i])
+		} else {
+			reads <- i
+		}
+	}
+	close(reads)
+	wg.Wait()
+
+	var answer []*primitives.JSON2Response
+	for i, resp := range responses {
+		if !notification[i] {
+			answer = append(answer, resp)
+		}
+	}
+	return answer, nil
+}
+
+func batchMethod(raw json.RawMessage) string {
+	j := new(primitives.JSON2Request)
+	if err := json.Unmarshal(raw, j); err != nil {
+		return ""
+	}
+	return j.Method
+}
+
+// runBatchItem runs one request of a batch.  A request without an id is a notification; it is run, but
+// gets no response.  An invalid request always gets a response, with a null id.
+func runBatchItem(state interfaces.IState, raw json.RawMessage, handler JSONRequestHandler, notification *bool) *primitives.JSON2Response {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return batchError(nil, NewInvalidRequestError())
+	}
+	j, err := primitives.ParseJSON2Request(string(raw))
+	if err != nil || len(j.Method) == 0 {
+		return batchError(nil, NewInvalidRequestError())
+	}
+	_, hasID := fields["id"]
+	*notification = !hasID
+
+	resp, jsonError := handler(state, j)
+	if jsonError != nil {
+		return batchError(j.ID, jsonError)
+	}
+	return resp
+}
+
+func batchError(id interface{}, jsonError *primitives.JSONError) *primitives.JSON2Response {
+	resp := primitives.NewJSON2Response()
+	resp.ID = id
+	resp.Error = jsonError
+	return resp
+}
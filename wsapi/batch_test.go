@@ -0,0 +1,77 @@
+package wsapi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestIsBatchRequest(t *testing.T) {
+	assert.True(t, IsBatchRequest([]byte(`[{"jsonrpc": "2.0", "id": 1, "method": "heights"}]`)))
+	assert.True(t, IsBatchRequest([]byte(" \r\n\t[]")))
+	assert.False(t, IsBatchRequest([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "heights"}`)))
+	assert.False(t, IsBatchRequest([]byte("")))
+}
+
+func TestHandleBatchRequest(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	body := `[
+		{"jsonrpc": "2.0", "id": 1, "method": "heights"},
+		{"jsonrpc": "2.0", "method": "heights"},
+		{"jsonrpc": "2.0", "id": "two", "method": "no-such-method"},
+		{"foo": "boo"},
+		1,
+		{"jsonrpc": "2.0", "id": 3, "method": "dblock-by-height", "params": {"height": 1}}
+	]`
+	responses, jErr := HandleBatchRequest(state, []byte(body), HandleV2JSONRequest)
+	assert.Nil(t, jErr)
+	if !assert.Equal(t, 5, len(responses)) { // The notification gets no response
+		return
+	}
+
+	assert.Equal(t, float64(1), responses[0].ID)
+	assert.Nil(t, responses[0].Error)
+	assert.NotNil(t, responses[0].Result)
+
+	assert.Equal(t, "two", responses[1].ID)
+	assert.Equal(t, NewMethodNotFoundError().Code, responses[1].Error.Code)
+
+	for _, resp := range responses[2:4] {
+		assert.Nil(t, resp.ID)
+		assert.Equal(t, NewInvalidRequestError().Code, resp.Error.Code)
+	}
+
+	assert.Equal(t, float64(3), responses[4].ID)
+	assert.Nil(t, responses[4].Error)
+
+	// All notifications
+	responses, jErr = HandleBatchRequest(state, []byte(`[{"jsonrpc": "2.0", "method": "heights"}]`), HandleV2JSONRequest)
+	assert.Nil(t, jErr)
+	assert.Equal(t, 0, len(responses))
+
+	// Bad batches
+	_, jErr = HandleBatchRequest(state, []byte(`[]`), HandleV2JSONRequest)
+	assert.Equal(t, NewInvalidRequestError().Code, jErr.Code)
+	_, jErr = HandleBatchRequest(state, []byte(`[{"jsonrpc": "2.0", "id": 1`), HandleV2JSONRequest)
+	assert.Equal(t, NewParseError().Code, jErr.Code)
+
+	var requests []string
+	for i := 0; i <= DefaultRpcMaxBatchSize; i++ {
+		requests = append(requests, fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "method": "heights"}`, i))
+	}
+	_, jErr = HandleBatchRequest(state, []byte("["+strings.Join(requests, ",")+"]"), HandleV2JSONRequest)
+	assert.Equal(t, NewInvalidRequestError().Code, jErr.Code)
+
+	responses, jErr = HandleBatchRequest(state, []byte("["+strings.Join(requests[1:], ",")+"]"), HandleV2JSONRequest)
+	assert.Nil(t, jErr)
+	assert.Equal(t, DefaultRpcMaxBatchSize, len(responses))
+	for i, resp := range responses {
+		assert.Equal(t, float64(i+1), resp.ID) // Responses keep the order of the requests
+	}
+}
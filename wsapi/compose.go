@@ -0,0 +1,299 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+)
+
+// Helpers so clients don't have to reimplement the entry credit cost and the binary layouts of entries and
+// commits: entry-cost, compose-entry and compose-chain build them from the content, and decode-commit,
+// decode-reveal and decode-transaction turn them back into JSON.  Nothing is submitted.
+
+// newChainCost is the extra entry credits paid to create a chain
+const newChainCost = 10
+
+func HandleV2EntryCost(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallEntryCost.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(ComposeRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	newChain := req.ChainID == ""
+	entry, cost, jsonError := composeEntry(req, newChain)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	data, _ := entry.MarshalBinary()
+
+	resp := new(EntryCostResponse)
+	resp.ChainID = entry.ChainID.String()
+	resp.EntryHash = entry.GetHash().String()
+	resp.Size = len(data) - 35
+	resp.ECCost = cost
+	resp.NewChain = newChain
+	return resp, nil
+}
+
+// HandleV2ComposeEntry returns the commit and reveal of an entry in an existing chain
+func HandleV2ComposeEntry(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallComposeEntry.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(ComposeRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.ChainID == "" {
+		return nil, NewCustomInvalidParamsError("chainid is required, use compose-chain for a new chain")
+	}
+
+	entry, cost, jsonError := composeEntry(req, false)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	milliTime, ecPubKey, jsonError := commitFields(req)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.MilliTime = milliTime
+	commit.EntryHash = entry.GetHash()
+	commit.Credits = cost
+	commit.ECPubKey = ecPubKey
+
+	return composeResponse(commit, commit.CommitMsg(), commit.GetSigHash(), entry, cost)
+}
+
+// HandleV2ComposeChain returns the commit and reveal of the first entry of a new chain.  The chain ID is
+// worked out from the ExtIDs, any chainid given is ignored.
+func HandleV2ComposeChain(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallComposeChain.Observe(float64(time.Since(n).Nanoseconds()))
+
+	req := new(ComposeRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	entry, cost, jsonError := composeEntry(req, true)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	milliTime, ecPubKey, jsonError := commitFields(req)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	commit := entryCreditBlock.NewCommitChain()
+	commit.MilliTime = milliTime
+	commit.ChainIDHash = primitives.Shad(entry.ChainID.Bytes())
+	commit.Weld = entry.GetWeldHash()
+	commit.EntryHash = entry.GetHash()
+	commit.Credits = cost
+	commit.ECPubKey = ecPubKey
+
+	return composeResponse(commit, commit.CommitMsg(), commit.GetSigHash(), entry, cost)
+}
+
+// composeEntry builds the entry of a request and works out what it costs
+func composeEntry(req *ComposeRequest, newChain bool) (*entryBlock.Entry, uint8, *primitives.JSONError) {
+	entry := entryBlock.NewEntry()
+	for _, extID := range req.ExtIDs {
+		b, err := hex.DecodeString(extID)
+		if err != nil {
+			return nil, 0, NewCustomInvalidParamsError("extids must be hex")
+		}
+		entry.ExtIDs = append(entry.ExtIDs, primitives.ByteSlice{Bytes: b})
+	}
+	content, err := hex.DecodeString(req.Content)
+	if err != nil {
+		return nil, 0, NewCustomInvalidParamsError("content must be hex")
+	}
+	entry.Content = primitives.ByteSlice{Bytes: content}
+
+	if newChain {
+		if len(entry.ExtIDs) == 0 {
+			return nil, 0, NewCustomInvalidParamsError("a new chain needs at least one extid")
+		}
+		entry.ChainID = entryBlock.ExternalIDsToChainID(entry.ExternalIDs())
+	} else {
+		entry.ChainID, err = primitives.HexToHash(req.ChainID)
+		if err != nil {
+			return nil, 0, NewInvalidHashError()
+		}
+	}
+
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, 0, NewInvalidEntryError()
+	}
+	cost, err := util.EntryCost(data)
+	if err != nil {
+		return nil, 0, NewCustomInvalidParamsError(err.Error())
+	}
+	if newChain {
+		cost += newChainCost
+	}
+	return entry, cost, nil
+}
+
+// commitFields returns the time and EC public key of the commit, the public key is zeros if none is given
+func commitFields(req *ComposeRequest) (*primitives.ByteSlice6, *primitives.ByteSlice32, *primitives.JSONError) {
+	ms := uint64(req.Timestamp)
+	if ms == 0 {
+		ms = primitives.NewTimestampNow().GetTimeMilliUInt64()
+	}
+	milliTime := new(primitives.ByteSlice6)
+	for i := 5; i >= 0; i-- {
+		milliTime[i] = byte(ms)
+		ms >>= 8
+	}
+
+	ecPubKey := new(primitives.ByteSlice32)
+	if req.ECPubKey != "" {
+		var key []byte
+		if primitives.ValidateECUserStr(req.ECPubKey) {
+			key = primitives.ConvertUserStrToAddress(req.ECPubKey)
+		} else {
+			var err error
+			key, err = hex.DecodeString(req.ECPubKey)
+			if err != nil || len(key) != constants.HASH_LENGTH {
+				return nil, nil, NewInvalidAddressError()
+			}
+		}
+		copy(ecPubKey[:], key)
+	}
+	return milliTime, ecPubKey, nil
+}
+
+func composeResponse(commit interfaces.BinaryMarshallable, sigData []byte, txID interfaces.IHash, entry *entryBlock.Entry,
+	cost uint8) (interface{}, *primitives.JSONError) {
+	c, err := commit.MarshalBinary()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+	reveal, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+
+	resp := new(ComposeResponse)
+	resp.Commit = hex.EncodeToString(c)
+	resp.SigData = hex.EncodeToString(sigData)
+	resp.Reveal = hex.EncodeToString(reveal)
+	resp.TxID = txID.String()
+	resp.EntryHash = entry.GetHash().String()
+	resp.ChainID = entry.ChainID.String()
+	resp.ECCost = cost
+	return resp, nil
+}
+
+func HandleV2DecodeCommit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallDecodeCommit.Observe(float64(time.Since(n).Nanoseconds()))
+
+	m := new(MessageRequest)
+	err := MapToObject(params, m)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	p, err := hex.DecodeString(m.Message)
+	if err != nil {
+		return nil, NewInvalidDataPassedError()
+	}
+
+	resp := new(DecodeCommitResponse)
+	switch len(p) {
+	case entryCreditBlock.CommitChainSize:
+		commit := entryCreditBlock.NewCommitChain()
+		if _, err := commit.UnmarshalBinaryData(p); err != nil {
+			return nil, NewInvalidCommitChainError()
+		}
+		resp.Type = "commit-chain"
+		resp.Commit = commit
+		resp.TxID = commit.GetSigHash().String()
+		resp.ValidSignature = commit.ValidateSignatures() == nil
+	case entryCreditBlock.CommitEntrySize:
+		commit := entryCreditBlock.NewCommitEntry()
+		if _, err := commit.UnmarshalBinaryData(p); err != nil {
+			return nil, NewInvalidCommitEntryError()
+		}
+		resp.Type = "commit-entry"
+		resp.Commit = commit
+		resp.TxID = commit.GetSigHash().String()
+		resp.ValidSignature = commit.ValidateSignatures() == nil
+	default:
+		return nil, NewInvalidDataPassedError()
+	}
+	return resp, nil
+}
+
+func HandleV2DecodeReveal(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallDecodeReveal.Observe(float64(time.Since(n).Nanoseconds()))
+
+	e := new(EntryRequest)
+	err := MapToObject(params, e)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	p, err := hex.DecodeString(e.Entry)
+	if err != nil {
+		return nil, NewInvalidEntryError()
+	}
+	entry := entryBlock.NewEntry()
+	if err := entry.UnmarshalBinary(p); err != nil {
+		return nil, NewInvalidEntryError()
+	}
+
+	resp := new(DecodeRevealResponse)
+	resp.Entry = entry
+	resp.EntryHash = entry.GetHash().String()
+	resp.ECCost, _ = util.EntryCost(p)
+	resp.Valid = entry.IsValid()
+	return resp, nil
+}
+
+func HandleV2DecodeTransaction(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallDecodeTransaction.Observe(float64(time.Since(n).Nanoseconds()))
+
+	t := new(TransactionRequest)
+	err := MapToObject(params, t)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	p, err := hex.DecodeString(t.Transaction)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+	tx := new(factoid.Transaction)
+	if err := tx.UnmarshalBinary(p); err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+
+	resp := new(DecodeTransactionResponse)
+	resp.Transaction = tx
+	resp.TxID = tx.GetSigHash().String()
+	resp.ValidSignatures = tx.ValidateSignatures() == nil
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleV2EntryCost(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+	extIDs := []string{hex.EncodeToString([]byte("one")), hex.EncodeToString([]byte("two"))}
+
+	resp, jErr := HandleV2EntryCost(state, ComposeRequest{ExtIDs: extIDs, Content: "00"})
+	assert.Nil(t, jErr)
+	cost := resp.(*EntryCostResponse)
+	chainID := entryBlock.ExternalIDsToChainID([][]byte{[]byte("one"), []byte("two")})
+	assert.Equal(t, chainID.String(), cost.ChainID)
+	assert.True(t, cost.NewChain)
+	assert.EqualValues(t, 11, cost.ECCost)
+
+	// 1 EC per KiB, not counting the 35 byte header
+	content := strings.Repeat("00", 1024-2-3-2-3)
+	resp, jErr = HandleV2EntryCost(state, ComposeRequest{ChainID: chainID.String(), ExtIDs: extIDs, Content: content})
+	assert.Nil(t, jErr)
+	cost = resp.(*EntryCostResponse)
+	assert.False(t, cost.NewChain)
+	assert.Equal(t, 1024, cost.Size)
+	assert.EqualValues(t, 1, cost.ECCost)
+
+	resp, jErr = HandleV2EntryCost(state, ComposeRequest{ChainID: chainID.String(), ExtIDs: extIDs, Content: content + "00"})
+	assert.Nil(t, jErr)
+	assert.EqualValues(t, 2, resp.(*EntryCostResponse).ECCost)
+
+	_, jErr = HandleV2EntryCost(state, ComposeRequest{ChainID: chainID.String(), Content: strings.Repeat("00", 10241)})
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2EntryCost(state, ComposeRequest{Content: "00"})
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2EntryCost(state, ComposeRequest{ExtIDs: []string{"zz"}})
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2ComposeAndDecode(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+	key := primitives.RandomPrivateKey()
+	req := ComposeRequest{
+		ExtIDs:    []string{hex.EncodeToString([]byte("compose"))},
+		Content:   hex.EncodeToString([]byte("some content")),
+		ECPubKey:  key.PublicKeyString(),
+		Timestamp: 1500000000000,
+	}
+
+	resp, jErr := HandleV2ComposeChain(state, req)
+	assert.Nil(t, jErr)
+	chain := resp.(*ComposeResponse)
+	assert.EqualValues(t, 11, chain.ECCost)
+
+	// Sign the commit as a wallet would, then decode it
+	sigData, _ := hex.DecodeString(chain.SigData)
+	commit, _ := hex.DecodeString(chain.Commit)
+	copy(commit[len(commit)-64:], key.Sign(sigData).GetSignature()[:])
+	resp, jErr = HandleV2DecodeCommit(state, MessageRequest{Message: hex.EncodeToString(commit)})
+	assert.Nil(t, jErr)
+	decoded := resp.(*DecodeCommitResponse)
+	assert.Equal(t, "commit-chain", decoded.Type)
+	assert.Equal(t, chain.TxID, decoded.TxID)
+	assert.True(t, decoded.ValidSignature)
+
+	resp, jErr = HandleV2DecodeReveal(state, EntryRequest{Entry: chain.Reveal})
+	assert.Nil(t, jErr)
+	reveal := resp.(*DecodeRevealResponse)
+	assert.Equal(t, chain.EntryHash, reveal.EntryHash)
+	assert.True(t, reveal.Valid)
+
+	req.ChainID = chain.ChainID
+	resp, jErr = HandleV2ComposeEntry(state, req)
+	assert.Nil(t, jErr)
+	entry := resp.(*ComposeResponse)
+	assert.Equal(t, chain.ChainID, entry.ChainID)
+	assert.EqualValues(t, 1, entry.ECCost)
+
+	resp, jErr = HandleV2DecodeCommit(state, MessageRequest{Message: entry.Commit})
+	assert.Nil(t, jErr)
+	decoded = resp.(*DecodeCommitResponse)
+	assert.Equal(t, "commit-entry", decoded.Type)
+	assert.False(t, decoded.ValidSignature)
+
+	req.ChainID = ""
+	_, jErr = HandleV2ComposeEntry(state, req)
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2DecodeCommit(state, MessageRequest{Message: "0011"})
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2DecodeTransaction(t *testing.T) {
+	state := testHelper.CreateEmptyTestState()
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(1), 1e8)
+	tx.AddOutput(testHelper.NewFactoidAddress(2), 1e8)
+	tx.SetTimestamp(primitives.NewTimestampNow())
+	testHelper.SignFactoidTransaction(1, tx)
+	data, err := tx.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr := HandleV2DecodeTransaction(state, TransactionRequest{Transaction: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	decoded := resp.(*DecodeTransactionResponse)
+	assert.Equal(t, tx.GetSigHash().String(), decoded.TxID)
+	assert.True(t, decoded.ValidSignatures)
+
+	_, jErr = HandleV2DecodeTransaction(state, TransactionRequest{Transaction: "00"})
+	assert.NotNil(t, jErr)
+}
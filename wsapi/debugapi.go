@@ -12,12 +12,14 @@ import (
 	"os"
 	"time"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/globals"
 
 	"regexp"
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	llog "github.com/FactomProject/factomd/log"
 )
 
 type success struct {
@@ -34,10 +36,16 @@ func HandleDebug(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	if err := checkAuthHeader(state, request); err != nil {
+	key, err := authenticate(state, request)
+	if err != nil {
 		handleUnauthorized(request, writer)
 		return
 	}
+	if !key.acquire() {
+		handleTooManyRequests(writer, key)
+		return
+	}
+	defer key.release()
 
 	body, err := ioutil.ReadAll(request.Body)
 	if err != nil {
@@ -45,12 +53,22 @@ func HandleDebug(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	if IsBatchRequest(body) {
+		HandleBatch(writer, state, body, key.handler(APIEndpointDebug, HandleDebugRequest))
+		return
+	}
+
 	j, err := primitives.ParseJSON2Request(string(body))
 	if err != nil {
 		HandleV2Error(writer, nil, NewInvalidRequestError())
 		return
 	}
 
+	if jsonError := key.authorize(APIEndpointDebug, j.Method); jsonError != nil {
+		HandleV2Error(writer, j, jsonError)
+		return
+	}
+
 	jsonResp, jsonError := HandleDebugRequest(state, j)
 
 	if jsonError != nil {
@@ -137,6 +155,18 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "message-filter":
 		resp, jsonError = HandleMessageFilter(state, params)
 		break
+	case "prune-status":
+		resp, jsonError = HandlePruneStatus(state, params)
+		break
+	case "logging":
+		resp, jsonError = HandleLogging(state, params)
+		break
+	case "set-logging":
+		resp, jsonError = HandleSetLogging(state, params)
+		break
+	case "activations":
+		resp, jsonError = HandleActivations(state, params)
+		break
 	default:
 		jsonError = NewMethodNotFoundError()
 		break
@@ -321,9 +351,49 @@ func HandleProcessList(state interfaces.IState, params interface{}) (interface{}
 	return r, nil
 }
 
+func HandlePruneStatus(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	return state.GetPruneStatus(), nil
+}
+
+// HandleActivations returns the activation schedule of the node's network, and which have activated
+func HandleActivations(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	type activation struct {
+		activations.ScheduledActivation
+		Active bool `json:"active"`
+	}
+	schedule := activations.Schedule()
+	resp := make([]activation, 0, len(schedule))
+	for _, a := range schedule {
+		resp = append(resp, activation{a, state.IsActive(activations.ActivationType(a.Id))})
+	}
+	return resp, nil
+}
+
+func HandleLogging(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	return llog.CurrentSettings(), nil
+}
+
+// HandleSetLogging changes the log level, the -debuglog subsystem regex and the sinks without a restart.
+// Fields left out are not changed.
+func HandleSetLogging(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	settings := new(llog.Settings)
+	if err := MapToObject(params, settings); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if err := llog.Configure(*settings); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return llog.CurrentSettings(), nil
+}
+
 func HandleReloadConfig(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	// LoacConfig with "" strings should load the default location
-	state.LoadConfig(state.GetConfigPath(), state.GetNetworkName())
+	if err := state.LoadConfig(state.GetConfigPath(), state.GetNetworkName()); err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	if err := LoadAPIKeys(state); err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
 
 	return state.GetCfg(), nil
 }
@@ -0,0 +1,288 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+)
+
+// decoder tries to unmarshal raw data as one type.  It returns the decoded value, its hash and any validation
+// notes, or an error if the data is not that type.  Data that is not used up completely is not a match.
+type decoder struct {
+	name   string
+	decode func(p []byte) (interface{}, interfaces.IHash, []string, error)
+}
+
+// decoders in the order they are tried.  The admin, entry credit and factoid blocks start with their fixed
+// chain IDs so they go first, messages start with their type byte, and the directory block, entry block and
+// entry are left for last as their layouts are the least distinct.
+var decoders = []decoder{
+	{"admin-block", decodeAdminBlock},
+	{"entry-credit-block", decodeECBlock},
+	{"factoid-block", decodeFactoidBlock},
+	{"message", decodeMessage},
+	{"directory-block", decodeDirectoryBlock},
+	{"entry-block", decodeEntryBlock},
+	{"entry", decodeEntry},
+}
+
+// DecodeRaw works out what raw data is and decodes it.  Any message the general message factory knows about
+// is recognised, as are the directory, admin, factoid, entry credit and entry blocks and entries.  If the
+// data also decodes cleanly as another type that is added to the notes.
+func DecodeRaw(p []byte) (*DecodeResponse, error) {
+	if len(p) == 0 {
+		return nil, fmt.Errorf("No data provided")
+	}
+
+	var resp *DecodeResponse
+	var others []string
+	for _, d := range decoders {
+		value, hash, notes, err := tryDecode(d, p)
+		if err != nil {
+			continue
+		}
+		if resp != nil {
+			others = append(others, d.name)
+			continue
+		}
+		resp = new(DecodeResponse)
+		resp.Type = d.name
+		resp.Value = value
+		if hash != nil {
+			resp.Hash = hash.String()
+		}
+		resp.Notes = notes
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("Data is not a known message, block or entry")
+	}
+	for _, other := range others {
+		resp.Notes = append(resp.Notes, fmt.Sprintf("data also decodes as a %s", other))
+	}
+	if resp.Notes == nil {
+		resp.Notes = []string{}
+	}
+	return resp, nil
+}
+
+// tryDecode runs one decoder, turning any panic from a bad unmarshal into an error
+func tryDecode(d decoder, p []byte) (value interface{}, hash interfaces.IHash, notes []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Error decoding %s: %v", d.name, r)
+		}
+	}()
+	return d.decode(p)
+}
+
+// leftover returns an error if unmarshalling did not use up all of the data
+func leftover(rest []byte) error {
+	if len(rest) > 0 {
+		return fmt.Errorf("%d bytes left over", len(rest))
+	}
+	return nil
+}
+
+func decodeAdminBlock(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	if !bytes.HasPrefix(p, constants.ADMIN_CHAINID) {
+		return nil, nil, nil, fmt.Errorf("Not an admin block")
+	}
+	block := adminBlock.NewAdminBlock(nil)
+	rest, err := block.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	keyMR, err := block.GetKeyMR()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	notes := []string{fmt.Sprintf("height %d with %d entries", block.GetDatabaseHeight(), len(block.GetABEntries()))}
+	return block, keyMR, notes, nil
+}
+
+func decodeECBlock(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	if !bytes.HasPrefix(p, constants.EC_CHAINID) {
+		return nil, nil, nil, fmt.Errorf("Not an entry credit block")
+	}
+	block := entryCreditBlock.NewECBlock()
+	rest, err := block.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	notes := []string{fmt.Sprintf("height %d with %d entries", block.GetDatabaseHeight(), len(block.GetEntries()))}
+	for i, entry := range block.GetEntries() {
+		var err error
+		switch commit := entry.(type) {
+		case *entryCreditBlock.CommitChain:
+			err = commit.ValidateSignatures()
+		case *entryCreditBlock.CommitEntry:
+			err = commit.ValidateSignatures()
+		}
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("entry %d has an invalid signature: %v", i, err))
+		}
+	}
+	return block, block.GetHash(), notes, nil
+}
+
+func decodeFactoidBlock(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	if !bytes.HasPrefix(p, constants.FACTOID_CHAINID) {
+		return nil, nil, nil, fmt.Errorf("Not a factoid block")
+	}
+	block := factoid.NewFBlock(nil)
+	rest, err := block.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fblock := block.(*factoid.FBlock)
+	notes := []string{fmt.Sprintf("height %d with %d transactions", fblock.GetDatabaseHeight(), len(fblock.GetTransactions()))}
+	if err := fblock.Validate(); err != nil {
+		notes = append(notes, fmt.Sprintf("block is not valid: %v", err))
+	}
+	return fblock, fblock.GetKeyMR(), notes, nil
+}
+
+func decodeMessage(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	// The factory is set up by the engine, a tool that doesn't set it up can't decode messages
+	if messages.General == nil {
+		return nil, nil, nil, fmt.Errorf("No message factory")
+	}
+	rest, msg, err := messages.General.UnmarshalMessageData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	notes := []string{fmt.Sprintf("%s message", constants.MessageName(msg.Type()))}
+	if signed, ok := msg.(interface {
+		VerifySignature() (bool, error)
+	}); ok {
+		valid, err := signed.VerifySignature()
+		switch {
+		case err != nil:
+			notes = append(notes, fmt.Sprintf("signature could not be checked: %v", err))
+		case !valid:
+			notes = append(notes, "signature is not valid")
+		default:
+			notes = append(notes, "signature is valid")
+		}
+	}
+	return msg, msg.GetMsgHash(), notes, nil
+}
+
+func decodeDirectoryBlock(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	block := directoryBlock.NewDirectoryBlock(nil)
+	rest, err := block.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dblock := block.(*directoryBlock.DirectoryBlock)
+	if len(dblock.GetDBEntries()) == 0 {
+		return nil, nil, nil, fmt.Errorf("Directory block has no entries")
+	}
+	notes := []string{fmt.Sprintf("height %d with %d entries", dblock.GetDatabaseHeight(), len(dblock.GetDBEntries()))}
+	bodyMR := dblock.GetHeader().GetBodyMR()
+	if built, err := dblock.BuildBodyMR(); err != nil || !built.IsSameAs(bodyMR) {
+		notes = append(notes, "body merkle root in the header does not match the entries")
+	}
+	return dblock, dblock.GetKeyMR(), notes, nil
+}
+
+func decodeEntryBlock(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	block := entryBlock.NewEBlock()
+	rest, err := block.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	notes := []string{fmt.Sprintf("height %d with %d entries", block.GetDatabaseHeight(), len(block.GetEntryHashes()))}
+	bodyMR := block.GetHeader().GetBodyMR()
+	if !block.GetBody().MR().IsSameAs(bodyMR) {
+		notes = append(notes, "body merkle root in the header does not match the entries")
+	}
+	keyMR, err := block.KeyMR()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return block, keyMR, notes, nil
+}
+
+func decodeEntry(p []byte) (interface{}, interfaces.IHash, []string, error) {
+	entry := entryBlock.NewEntry()
+	rest, err := entry.UnmarshalBinaryData(p)
+	if err == nil {
+		err = leftover(rest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var notes []string
+	if cost, err := util.EntryCost(p); err != nil {
+		notes = append(notes, fmt.Sprintf("entry is too large: %v", err))
+	} else {
+		notes = append(notes, fmt.Sprintf("costs %d entry credits", cost))
+	}
+	if !entry.IsValid() {
+		notes = append(notes, "entry is not valid")
+	}
+	return entry, entry.GetHash(), notes, nil
+}
+
+func HandleV2Decode(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallDecode.Observe(float64(time.Since(n).Nanoseconds()))
+
+	m := new(MessageRequest)
+	err := MapToObject(params, m)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	p, err := hex.DecodeString(m.Message)
+	if err != nil {
+		return nil, NewInvalidDataPassedError()
+	}
+
+	resp, err := DecodeRaw(p)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return resp, nil
+}
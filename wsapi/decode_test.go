@@ -0,0 +1,84 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRawEntryAndMessage(t *testing.T) {
+	messages.General = new(msgsupport.GeneralFactory)
+
+	entry := entryBlock.NewEntry()
+	entry.ChainID = primitives.Sha([]byte("chain"))
+	entry.Content = primitives.ByteSlice{Bytes: []byte("decode me")}
+	data, err := entry.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, err := DecodeRaw(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "entry", resp.Type)
+	assert.Equal(t, entry.GetHash().String(), resp.Hash)
+	assert.Contains(t, resp.Notes, "costs 1 entry credits")
+
+	msg := messages.NewCommitEntryMsg()
+	msg.CommitEntry = entryCreditBlock.NewCommitEntry()
+	msg.CommitEntry.Credits = 1
+	assert.Nil(t, msg.Sign(primitives.RandomPrivateKey()))
+	data, err = msg.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, err = DecodeRaw(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "message", resp.Type)
+	assert.Equal(t, msg.GetMsgHash().String(), resp.Hash)
+	assert.Contains(t, resp.Notes, "signature is valid")
+
+	_, err = DecodeRaw([]byte{0xff, 0x01, 0x02})
+	assert.NotNil(t, err)
+	_, err = DecodeRaw(nil)
+	assert.NotNil(t, err)
+}
+
+func TestHandleV2DecodeBlocks(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	dblock, err := state.GetDB().FetchDBlockByHeight(1)
+	assert.Nil(t, err)
+	data, err := dblock.MarshalBinary()
+	assert.Nil(t, err)
+	resp, jErr := HandleV2Decode(state, MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	decoded := resp.(*DecodeResponse)
+	assert.Equal(t, "directory-block", decoded.Type)
+	assert.Equal(t, dblock.GetKeyMR().String(), decoded.Hash)
+
+	fblock, err := state.GetDB().FetchFBlockByHeight(1)
+	assert.Nil(t, err)
+	data, err = fblock.MarshalBinary()
+	assert.Nil(t, err)
+	resp, jErr = HandleV2Decode(state, MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	decoded = resp.(*DecodeResponse)
+	assert.Equal(t, "factoid-block", decoded.Type)
+	assert.Equal(t, fblock.GetKeyMR().String(), decoded.Hash)
+
+	eblock, err := state.GetDB().FetchEBlock(dblock.GetEBlockDBEntries()[0].GetKeyMR())
+	assert.Nil(t, err)
+	data, err = eblock.MarshalBinary()
+	assert.Nil(t, err)
+	resp, jErr = HandleV2Decode(state, MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	assert.Equal(t, "entry-block", resp.(*DecodeResponse).Type)
+
+	_, jErr = HandleV2Decode(state, MessageRequest{Message: "zz"})
+	assert.NotNil(t, jErr)
+}
@@ -47,6 +47,9 @@ func NewCustomInternalError(data interface{}) *primitives.JSONError {
 func NewCustomInvalidParamsError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32602, "Invalid params", data)
 }
+func NewBatchTooLargeError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32600, "Invalid Request", data)
+}
 
 /*******************************************************************/
 
@@ -103,3 +106,15 @@ func NewReceiptError() *primitives.JSONError {
 func NewRepeatCommitError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32011, "Repeated Commit", data)
 }
+func NewChainNotKeptError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32012, "Chain not kept by this node", data)
+}
+func NewMethodNotAllowedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32013, "Method not allowed for this API key", data)
+}
+func NewRateLimitError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32014, "Rate limit exceeded", data)
+}
+func NewUnauthorizedError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32015, "Unauthorized", data)
+}
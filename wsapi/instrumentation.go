@@ -15,6 +15,31 @@ var (
 		Help: "Time it takes to compelete a call",
 	})
 
+	HandleBatchCall = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_batch_call_ns",
+		Help: "Time it takes to compelete a batch of calls",
+	})
+
+	BatchSize = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_batch_size",
+		Help: "Number of requests in a batch",
+	})
+
+	BatchRejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_wsapi_batch_rejected_total",
+		Help: "Number of batches rejected for being too large",
+	})
+
+	APIKeyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_apikey_requests_total",
+		Help: "Requests made with an api key, by key and result (ok, denied, limited, unauthorized)",
+	}, []string{"key", "result"})
+
+	APIKeyConcurrent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_wsapi_apikey_concurrent_requests",
+		Help: "Requests being handled for an api key",
+	}, []string{"key"})
+
 	HandleV2APICallChainHead = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_chainhead_ns",
 		Help: "Time it takes to compelete a chainhead",
@@ -100,6 +125,56 @@ var (
 		Help: "Time it takes to compelete a ",
 	})
 
+	HandleV2APICallAnchorStatus = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_anchor_status_ns",
+		Help: "Time it takes to compelete a ",
+	})
+
+	HandleV2APICallValidateTransaction = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_validate_transaction_ns",
+		Help: "Time it takes to compelete a validate-transaction",
+	})
+
+	HandleV2APICallValidateCommit = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_validate_commit_ns",
+		Help: "Time it takes to compelete a validate-commit",
+	})
+
+	HandleV2APICallEntryCost = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_entry_cost_ns",
+		Help: "Time it takes to compelete a entry-cost",
+	})
+
+	HandleV2APICallComposeEntry = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_compose_entry_ns",
+		Help: "Time it takes to compelete a compose-entry",
+	})
+
+	HandleV2APICallComposeChain = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_compose_chain_ns",
+		Help: "Time it takes to compelete a compose-chain",
+	})
+
+	HandleV2APICallDecodeCommit = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_decode_commit_ns",
+		Help: "Time it takes to compelete a decode-commit",
+	})
+
+	HandleV2APICallDecodeReveal = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_decode_reveal_ns",
+		Help: "Time it takes to compelete a decode-reveal",
+	})
+
+	HandleV2APICallDecodeTransaction = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_decode_transaction_ns",
+		Help: "Time it takes to compelete a decode-transaction",
+	})
+
+	HandleV2APICallDecode = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name: "factomd_wsapi_v2_api_call_decode_ns",
+		Help: "Time it takes to compelete a decode",
+	})
+
 	HandleV2APICallReceipt = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name: "factomd_wsapi_v2_api_call_receipt_ns",
 		Help: "Time it takes to compelete a ",
@@ -203,6 +278,11 @@ func RegisterPrometheus() {
 
 	prometheus.MustRegister(GensisFblockCall)
 	prometheus.MustRegister(HandleV2APICallGeneral)
+	prometheus.MustRegister(HandleBatchCall)
+	prometheus.MustRegister(BatchSize)
+	prometheus.MustRegister(BatchRejected)
+	prometheus.MustRegister(APIKeyRequests)
+	prometheus.MustRegister(APIKeyConcurrent)
 	prometheus.MustRegister(HandleV2APICallChainHead)
 	prometheus.MustRegister(HandleV2APICallCommitChain)
 	prometheus.MustRegister(HandleV2APICallCommitEntry)
@@ -218,6 +298,16 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(HandleV2APICallProp)
 	prometheus.MustRegister(HandleV2APICallRawData)
 	prometheus.MustRegister(HandleV2APICallReceipt)
+	prometheus.MustRegister(HandleV2APICallAnchorStatus)
+	prometheus.MustRegister(HandleV2APICallValidateTransaction)
+	prometheus.MustRegister(HandleV2APICallValidateCommit)
+	prometheus.MustRegister(HandleV2APICallEntryCost)
+	prometheus.MustRegister(HandleV2APICallComposeEntry)
+	prometheus.MustRegister(HandleV2APICallComposeChain)
+	prometheus.MustRegister(HandleV2APICallDecodeCommit)
+	prometheus.MustRegister(HandleV2APICallDecodeReveal)
+	prometheus.MustRegister(HandleV2APICallDecodeTransaction)
+	prometheus.MustRegister(HandleV2APICallDecode)
 	prometheus.MustRegister(HandleV2APICallRevealEntry)
 	prometheus.MustRegister(HandleV2APICallFctAck)
 	prometheus.MustRegister(HandleV2APICallEntryAck)
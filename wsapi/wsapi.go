@@ -52,6 +52,9 @@ func Start(state interfaces.IState) {
 		h.Write(httpBasicAuth(rpcUser, rpcPass))
 		// TODO verify if there already runs a Server on the port, this code isn't executed, would change behavior.
 		state.SetRpcAuthHash(h.Sum(nil)) //set this in the beginning to prevent timing attacks
+		if err := LoadAPIKeys(state); err != nil {
+			wsLog.Errorf("failed to load api keys: %v", err)
+		}
 
 		server.Start()
 	}
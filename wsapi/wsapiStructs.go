@@ -91,6 +91,23 @@ type AnchorsResponse struct {
 	Ethereum interface{} `json:"ethereum"`
 }
 
+type AnchorBlockStatus struct {
+	Height             uint32 `json:"directoryblockheight"`
+	KeyMR              string `json:"directoryblockkeymr"`
+	Bitcoin            bool   `json:"bitcoin"`
+	BitcoinBlockHeight int32  `json:"bitcoinblockheight,omitempty"`
+	Ethereum           bool   `json:"ethereum"`
+}
+
+type AnchorStatusResponse struct {
+	StartHeight        uint32              `json:"startheight"`
+	EndHeight          uint32              `json:"endheight"`
+	Blocks             []AnchorBlockStatus `json:"blocks"`
+	UnanchoredBitcoin  int                 `json:"unanchoredbitcoin"`
+	UnanchoredEthereum int                 `json:"unanchoredethereum"`
+	Status             interface{}         `json:"status"` // Latest anchored height per ledger
+}
+
 type BitcoinAnchorResponse struct {
 	TransactionHash string `json:"transactionhash"`
 	BlockHash       string `json:"blockhash"`
@@ -172,6 +189,51 @@ type SendReplayMessageResponse struct {
 	End     uint32 `json:"endheight"`
 }
 
+type EntryCostResponse struct {
+	ChainID   string `json:"chainid"`
+	EntryHash string `json:"entryhash"`
+	Size      int    `json:"size"`     // Bytes paid for: the entry less its 35 byte header
+	ECCost    uint8  `json:"eccost"`   // Includes the 10 EC of a new chain
+	NewChain  bool   `json:"newchain"` // The entry creates its chain
+}
+
+type ComposeResponse struct {
+	Commit    string `json:"commit"`  // Unsigned, the signature and public key are zeros if no key is given
+	SigData   string `json:"sigdata"` // The part of the commit the EC key signs
+	Reveal    string `json:"reveal"`
+	TxID      string `json:"txid"`
+	EntryHash string `json:"entryhash"`
+	ChainID   string `json:"chainid"`
+	ECCost    uint8  `json:"eccost"`
+}
+
+type DecodeCommitResponse struct {
+	Type           string      `json:"type"` // commit-chain or commit-entry
+	Commit         interface{} `json:"commit"`
+	TxID           string      `json:"txid"`
+	ValidSignature bool        `json:"validsignature"`
+}
+
+type DecodeRevealResponse struct {
+	Entry     interface{} `json:"entry"`
+	EntryHash string      `json:"entryhash"`
+	ECCost    uint8       `json:"eccost"` // Not counting the 10 EC of a new chain
+	Valid     bool        `json:"valid"`
+}
+
+type DecodeTransactionResponse struct {
+	Transaction     interface{} `json:"transaction"`
+	TxID            string      `json:"txid"`
+	ValidSignatures bool        `json:"validsignatures"`
+}
+
+type DecodeResponse struct {
+	Type  string      `json:"type"`
+	Hash  string      `json:"hash"`
+	Value interface{} `json:"value"`
+	Notes []string    `json:"notes"`
+}
+
 type TransactionRateResponse struct {
 	TotalTransactionRate   float64 `json:"totaltxrate"`
 	InstantTransactionRate float64 `json:"instanttxrate"`
@@ -280,6 +342,11 @@ type ReplayRequest struct {
 	EndHeight   uint32 `json:"endheight,omitempty"`
 }
 
+type AnchorStatusRequest struct {
+	StartHeight uint32 `json:"startheight"`
+	EndHeight   uint32 `json:"endheight,omitempty"`
+}
+
 type HeightOrHashRequest struct {
 	Height *int64 `json:"height,omitempty"`
 	Hash   string `json:"hash,omitempty"`
@@ -309,6 +376,15 @@ type MessageRequest struct {
 	Message string `json:"message"`
 }
 
+// Entry content for entry-cost, compose-entry and compose-chain.  Binary fields are hex.
+type ComposeRequest struct {
+	ChainID   string   `json:"chainid,omitempty"` // Empty for the first entry of a new chain
+	ExtIDs    []string `json:"extids"`
+	Content   string   `json:"content"`
+	ECPubKey  string   `json:"ecpubkey,omitempty"`  // EC public address or key to put in the commit
+	Timestamp int64    `json:"timestamp,omitempty"` // Of the commit in milliseconds, defaults to now
+}
+
 type PendingEntry struct {
 	EntryHash interfaces.IHash `json:"entryhash"`
 	ChainID   interfaces.IHash `json:"chainid"`
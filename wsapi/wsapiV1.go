@@ -44,30 +44,36 @@ func (server *Server) AddV1Endpoints() {
 func CheckHttpPasswordOkV1Middleware() Middleware {
 	return func(f http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			if checkHttpPasswordOkV1(w, r) {
+			if key, ok := checkHttpPasswordOkV1(w, r); ok {
+				if !key.acquire() {
+					handleTooManyRequests(w, key)
+					return
+				}
+				defer key.release()
 				// Call the next middleware/handler in chain
-				f(w, r)
+				f(w, withAPIKey(r, key))
 			}
 		}
 	}
 }
 
-func checkHttpPasswordOkV1(writer http.ResponseWriter, request *http.Request) bool {
-	if state, err := GetState(request); err == nil {
-		if err := checkAuthHeader(state, request); err != nil {
-			remoteIP := ""
-			remoteIP += strings.Split(request.RemoteAddr, ":")[0]
-			wsLog.Debugf("Unauthorized V1 API client connection attempt from %s\n", remoteIP)
-			writer.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
-			http.Error(writer, "401 Unauthorized.", http.StatusUnauthorized)
-			return false
-		}
-	} else {
+func checkHttpPasswordOkV1(writer http.ResponseWriter, request *http.Request) (*APIKey, bool) {
+	state, err := GetState(request)
+	if err != nil {
 		wsLog.Errorf("failed to get state from request: %s", err)
 		writer.WriteHeader(http.StatusBadRequest)
-		return false
+		return nil, false
 	}
-	return true
+	key, err := authenticate(state, request)
+	if err != nil {
+		remoteIP := ""
+		remoteIP += strings.Split(request.RemoteAddr, ":")[0]
+		wsLog.Debugf("Unauthorized V1 API client connection attempt from %s\n", remoteIP)
+		writer.Header().Add("WWW-Authenticate", `Basic realm="factomd RPC"`)
+		http.Error(writer, "401 Unauthorized.", http.StatusUnauthorized)
+		return nil, false
+	}
+	return key, true
 }
 
 func extractURLHeightParam(_ http.ResponseWriter, request *http.Request) (param HeightRequest, err error) {
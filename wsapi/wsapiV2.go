@@ -26,6 +26,7 @@ import (
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
+	"github.com/FactomProject/factomd/tracing"
 )
 
 const API_VERSION string = "2.0"
@@ -45,10 +46,16 @@ func HandleV2(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	if err := checkAuthHeader(state, request); err != nil {
+	key, err := authenticate(state, request)
+	if err != nil {
 		handleUnauthorized(request, writer)
 		return
 	}
+	if !key.acquire() {
+		handleTooManyRequests(writer, key)
+		return
+	}
+	defer key.release()
 
 	body, err := ioutil.ReadAll(request.Body)
 	if err != nil {
@@ -56,12 +63,22 @@ func HandleV2(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	if IsBatchRequest(body) {
+		HandleBatch(writer, state, body, key.handler(APIEndpointV2, HandleV2JSONRequest))
+		return
+	}
+
 	j, err := primitives.ParseJSON2Request(string(body))
 	if err != nil {
 		HandleV2Error(writer, nil, NewInvalidRequestError())
 		return
 	}
 
+	if jsonError := key.authorize(APIEndpointV2, j.Method); jsonError != nil {
+		HandleV2Error(writer, j, jsonError)
+		return
+	}
+
 	jsonResp, jsonError := HandleV2JSONRequest(state, j)
 	if jsonError != nil {
 		HandleV2Error(writer, j, jsonError)
@@ -82,6 +99,9 @@ func HandleV2Request(_ http.ResponseWriter, request *http.Request, j *primitives
 		wsLog.Errorf("failed to extract port from request: %s", err)
 		return nil, NewParseError()
 	}
+	if jsonError := apiKeyFromRequest(request).authorize(APIEndpointV2, j.Method); jsonError != nil {
+		return nil, jsonError
+	}
 	return HandleV2JSONRequest(state, j)
 }
 
@@ -95,6 +115,8 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2ReplayDBFromHeight(state, params)
 	case "anchors":
 		resp, jsonError = HandleV2Anchors(state, params)
+	case "anchor-status":
+		resp, jsonError = HandleV2AnchorStatus(state, params)
 	case "chain-head":
 		resp, jsonError = HandleV2ChainHead(state, params)
 	case "commit-chain":
@@ -147,6 +169,24 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2GetPendingTransactions(state, params)
 	case "send-raw-message":
 		resp, jsonError = HandleV2SendRawMessage(state, params)
+	case "validate-transaction":
+		resp, jsonError = HandleV2ValidateTransaction(state, params)
+	case "validate-commit":
+		resp, jsonError = HandleV2ValidateCommit(state, params)
+	case "entry-cost":
+		resp, jsonError = HandleV2EntryCost(state, params)
+	case "compose-entry":
+		resp, jsonError = HandleV2ComposeEntry(state, params)
+	case "compose-chain":
+		resp, jsonError = HandleV2ComposeChain(state, params)
+	case "decode-commit":
+		resp, jsonError = HandleV2DecodeCommit(state, params)
+	case "decode-reveal":
+		resp, jsonError = HandleV2DecodeReveal(state, params)
+	case "decode-transaction":
+		resp, jsonError = HandleV2DecodeTransaction(state, params)
+	case "decode":
+		resp, jsonError = HandleV2Decode(state, params)
 	case "transaction":
 		resp, jsonError = HandleV2GetTranasction(state, params)
 	case "dblock-by-height":
@@ -599,6 +639,7 @@ func HandleV2CommitChain(state interfaces.IState, params interface{}) (interface
 	if !state.IsHighestCommit(msg.CommitChain.GetEntryHash(), msg) {
 		return nil, NewRepeatCommitError(RepeatedEntryMessage{"A commit with equal or greater payment already exists", msg.CommitChain.GetEntryHash().String()})
 	}
+	state.TraceMessage(msg, tracing.StageAPIQueue)
 	state.APIQueue().Enqueue(msg)
 	state.IncECCommits()
 
@@ -648,6 +689,7 @@ func HandleV2CommitEntry(state interfaces.IState, params interface{}) (interface
 		return nil, NewRepeatCommitError(RepeatedEntryMessage{"A commit with equal or greater payment already exists", msg.CommitEntry.GetEntryHash().String()})
 	}
 
+	state.TraceMessage(msg, tracing.StageAPIQueue)
 	state.APIQueue().Enqueue(msg)
 	state.IncECommits()
 
@@ -686,6 +728,7 @@ func HandleV2RevealEntry(state interfaces.IState, params interface{}) (interface
 	msg := new(messages.RevealEntryMsg)
 	msg.Entry = entry
 	msg.Timestamp = state.GetTimestamp()
+	state.TraceMessage(msg, tracing.StageAPIQueue)
 	state.APIQueue().Enqueue(msg)
 
 	resp := new(RevealEntryResponse)
@@ -751,6 +794,8 @@ func HandleV2RawData(state interfaces.IState, params interface{}) (interface{},
 			b, _ = block.MarshalBinary()
 		} else if block, _ = dbase.FetchEntry(h); block != nil {
 			b, _ = block.MarshalBinary()
+		} else if chainID := EntryChainNotKept(state, h); chainID != nil {
+			return nil, NewChainNotKeptError(chainID.String())
 		} else {
 			return nil, NewObjectNotFoundError()
 		}
@@ -892,6 +937,86 @@ func HandleV2Anchors(state interfaces.IState, params interface{}) (interface{},
 	return response, nil
 }
 
+// HandleV2AnchorStatus reports which directory blocks in a range of heights are anchored on each ledger,
+// along with the latest anchored height per ledger.
+func HandleV2AnchorStatus(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallAnchorStatus.Observe(float64(time.Since(n).Nanoseconds()))
+
+	request := new(AnchorStatusRequest)
+	err := MapToObject(params, request)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	top := state.GetHighestSavedBlk()
+	beginning := request.StartHeight
+	end := top
+	if request.EndHeight != 0 && request.EndHeight < end {
+		end = request.EndHeight
+	}
+	if beginning > end {
+		return nil, NewInvertedHeightError()
+	}
+	if (end - beginning) > 1000 {
+		end = beginning + 1000
+	}
+
+	// Ethereum anchors windows of blocks, with the record on the highest block of the window.  So look
+	// past the end of the range, and count a block as anchored if a block at or above it is.
+	scanEnd := end + 1000
+	if scanEnd > top {
+		scanEnd = top
+	}
+
+	dbo := state.GetDB()
+	response := new(AnchorStatusResponse)
+	response.StartHeight = beginning
+	response.EndHeight = end
+	response.Blocks = make([]AnchorBlockStatus, int(end-beginning)+1)
+	ethereum := false
+	for i := scanEnd; i >= beginning; i-- {
+		keyMR, err := dbo.FetchDBKeyMRByHeight(i)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		var dirBlockInfo interfaces.IDirBlockInfo
+		if keyMR != nil {
+			dirBlockInfo, err = dbo.FetchDirBlockInfoByKeyMR(keyMR)
+			if err != nil {
+				return nil, NewInternalDatabaseError()
+			}
+		}
+		if dirBlockInfo != nil && dirBlockInfo.GetEthereumConfirmed() {
+			ethereum = true
+		}
+
+		if i <= end {
+			block := &response.Blocks[i-beginning]
+			block.Height = i
+			if keyMR != nil {
+				block.KeyMR = keyMR.String()
+			}
+			if dirBlockInfo != nil && dirBlockInfo.GetBTCConfirmed() {
+				block.Bitcoin = true
+				block.BitcoinBlockHeight = dirBlockInfo.GetBTCBlockHeight()
+			} else {
+				response.UnanchoredBitcoin++
+			}
+			block.Ethereum = ethereum
+			if !ethereum {
+				response.UnanchoredEthereum++
+			}
+		}
+		if i == 0 {
+			break
+		}
+	}
+	response.Status = state.GetAnchorStatus()
+
+	return response, nil
+}
+
 func HandleV2Receipt(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallReceipt.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1057,6 +1182,9 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 			return nil, NewInvalidHashError()
 		}
 		if entry == nil {
+			if chainID := EntryChainNotKept(state, h); chainID != nil {
+				return nil, NewChainNotKeptError(chainID.String())
+			}
 			return nil, NewEntryNotFoundError()
 		}
 
@@ -1083,6 +1211,24 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 	return e, nil
 }
 
+// EntryChainNotKept returns the chain of an entry the blockchain has, but this chain subset node
+// doesn't keep.  Returns nil otherwise.
+func EntryChainNotKept(state interfaces.IState, entryHash interfaces.IHash) interfaces.IHash {
+	dbase := state.GetDB()
+	keymr, err := dbase.FetchIncludedIn(entryHash)
+	if err != nil || keymr == nil {
+		return nil
+	}
+	eblock, err := dbase.FetchEBlock(keymr)
+	if err != nil || eblock == nil {
+		return nil
+	}
+	if state.IsChainKept(eblock.GetChainID()) {
+		return nil
+	}
+	return eblock.GetChainID()
+}
+
 func HandleV2ChainHead(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallChainHead.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1218,6 +1364,7 @@ func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interfa
 
 	state.IncFCTSubmits()
 
+	state.TraceMessage(msg, tracing.StageAPIQueue)
 	state.APIQueue().Enqueue(msg)
 
 	resp := new(FactoidSubmitResponse)
@@ -1227,6 +1374,74 @@ func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interfa
 	return resp, nil
 }
 
+// HandleV2ValidateTransaction runs the checks of factoid-submit on a transaction and returns every reason it
+// would be refused.  Nothing is submitted.
+func HandleV2ValidateTransaction(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallValidateTransaction.Observe(float64(time.Since(n).Nanoseconds()))
+
+	t := new(TransactionRequest)
+	err := MapToObject(params, t)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	p, err := hex.DecodeString(t.Transaction)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+
+	msg := new(messages.FactoidTransaction)
+	_, err = msg.UnmarshalTransData(p)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+
+	return state.DryRunValidate(msg), nil
+}
+
+// HandleV2ValidateCommit runs the checks of commit-chain or commit-entry on a commit and returns every reason
+// it would be refused.  The kind of commit is taken from its length.  Nothing is submitted.
+func HandleV2ValidateCommit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	n := time.Now()
+	defer HandleV2APICallValidateCommit.Observe(float64(time.Since(n).Nanoseconds()))
+
+	m := new(MessageRequest)
+	err := MapToObject(params, m)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	p, err := hex.DecodeString(m.Message)
+	if err != nil {
+		return nil, NewInvalidCommitEntryError()
+	}
+
+	var msg interfaces.IMsg
+	switch len(p) {
+	case entryCreditBlock.CommitChainSize:
+		commit := entryCreditBlock.NewCommitChain()
+		if _, err := commit.UnmarshalBinaryData(p); err != nil {
+			return nil, NewInvalidCommitChainError()
+		}
+		cc := new(messages.CommitChainMsg)
+		cc.CommitChain = commit
+		msg = cc
+	case entryCreditBlock.CommitEntrySize:
+		commit := entryCreditBlock.NewCommitEntry()
+		if _, err := commit.UnmarshalBinaryData(p); err != nil {
+			return nil, NewInvalidCommitEntryError()
+		}
+		ce := new(messages.CommitEntryMsg)
+		ce.CommitEntry = commit
+		msg = ce
+	default:
+		return nil, NewInvalidCommitEntryError()
+	}
+
+	return state.DryRunValidate(msg), nil
+}
+
 func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallFABal.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1336,6 +1551,7 @@ func HandleV2SendRawMessage(state interfaces.IState, params interface{}) (interf
 		return nil, NewInvalidParamsError()
 	}
 
+	state.TraceMessage(msg, tracing.StageAPIQueue)
 	state.APIQueue().Enqueue(msg)
 
 	resp := new(SendRawMessageResponse)
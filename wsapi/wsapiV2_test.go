@@ -2,6 +2,8 @@ package wsapi_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -12,6 +14,9 @@ import (
 
 	"time"
 
+	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
@@ -512,3 +517,134 @@ func v2Request(req *primitives.JSON2Request) (*primitives.JSON2Response, error)
 func number(n string) json.Number {
 	return json.Number(n)
 }
+
+func TestHandleV2AnchorStatus(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+	dbo := state.GetDB()
+	if state.GetHighestSavedBlk() < 4 {
+		t.Fatalf("Test state only has %d blocks", state.GetHighestSavedBlk())
+	}
+
+	// Bitcoin anchors block 2, and an Ethereum window ends at block 3
+	for height, eth := range map[uint32]bool{2: false, 3: true} {
+		keyMR, err := dbo.FetchDBKeyMRByHeight(height)
+		assert.Nil(t, err)
+		dbi := dbInfo.NewDirBlockInfo()
+		dbi.DBHash = keyMR
+		dbi.DBMerkleRoot = keyMR
+		dbi.DBHeight = height
+		dbi.BTCConfirmed = !eth
+		dbi.EthereumConfirmed = eth
+		assert.Nil(t, dbo.SaveDirBlockInfo(dbi))
+	}
+
+	resp, jErr := HandleV2AnchorStatus(state, AnchorStatusRequest{StartHeight: 0, EndHeight: 4})
+	assert.Nil(t, jErr)
+	status := resp.(*AnchorStatusResponse)
+	assert.Equal(t, 5, len(status.Blocks))
+	for i, block := range status.Blocks {
+		assert.Equal(t, uint32(i), block.Height)
+		assert.Equal(t, i == 2, block.Bitcoin, "bitcoin at height %d", i)
+		assert.Equal(t, i <= 3, block.Ethereum, "ethereum at height %d", i)
+	}
+	assert.Equal(t, 4, status.UnanchoredBitcoin)
+	assert.Equal(t, 1, status.UnanchoredEthereum)
+
+	_, jErr = HandleV2AnchorStatus(state, AnchorStatusRequest{StartHeight: 4, EndHeight: 2})
+	assert.NotNil(t, jErr)
+}
+
+// dryRunCodes returns the reason codes of a validate-transaction or validate-commit response
+func dryRunCodes(t *testing.T, resp interface{}) (bool, []string) {
+	data, err := json.Marshal(resp)
+	assert.Nil(t, err)
+	result := struct {
+		Valid   bool `json:"valid"`
+		Reasons []struct {
+			Code string `json:"code"`
+		} `json:"reasons"`
+	}{}
+	assert.Nil(t, json.Unmarshal(data, &result))
+	var codes []string
+	for _, r := range result.Reasons {
+		codes = append(codes, r.Code)
+	}
+	return result.Valid, codes
+}
+
+func TestHandleV2ValidateTransaction(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	// An address with no factoids
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(9), 1e8)
+	tx.AddOutput(testHelper.NewFactoidAddress(10), 1e8)
+	tx.SetTimestamp(primitives.NewTimestampNow())
+	testHelper.SignFactoidTransaction(9, tx)
+	data, err := tx.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr := HandleV2ValidateTransaction(state, TransactionRequest{Transaction: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	valid, codes := dryRunCodes(t, resp)
+	assert.False(t, valid)
+	assert.Contains(t, codes, "insufficient_balance")
+	assert.Contains(t, codes, "fee_too_low")
+	assert.NotContains(t, codes, "bad_signature")
+
+	// Signed by the wrong key
+	tx = new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(9), 1e8)
+	tx.AddOutput(testHelper.NewFactoidAddress(10), 1e8)
+	tx.SetTimestamp(primitives.NewTimestampNow())
+	tx.AddAuthorization(testHelper.NewFactoidRCDAddress(9))
+	sigData, err := tx.MarshalBinarySig()
+	assert.Nil(t, err)
+	tx.SetSignatureBlock(0, factoid.NewSingleSignatureBlock(testHelper.NewPrivKey(10), sigData))
+	data, err = tx.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr = HandleV2ValidateTransaction(state, TransactionRequest{Transaction: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	_, codes = dryRunCodes(t, resp)
+	assert.Contains(t, codes, "bad_signature")
+
+	_, jErr = HandleV2ValidateTransaction(state, TransactionRequest{Transaction: "zz"})
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2ValidateCommit(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
+
+	ms := make([]byte, 8)
+	binary.BigEndian.PutUint64(ms, uint64(time.Now().UnixNano()/1e6))
+
+	// An EC address with no credits
+	commit := entryCreditBlock.NewCommitEntry()
+	assert.Nil(t, commit.MilliTime.UnmarshalBinary(ms[2:]))
+	commit.EntryHash = primitives.Sha([]byte("validate-commit"))
+	commit.Credits = 1
+	testHelper.SignCommit(9, commit)
+	data, err := commit.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr := HandleV2ValidateCommit(state, MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	valid, codes := dryRunCodes(t, resp)
+	assert.False(t, valid)
+	assert.Equal(t, []string{"insufficient_balance"}, codes)
+
+	// Paying nothing, and changed after signing
+	commit.Credits = 0
+	data, err = commit.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr = HandleV2ValidateCommit(state, MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	_, codes = dryRunCodes(t, resp)
+	assert.Contains(t, codes, "fee_too_low")
+	assert.Contains(t, codes, "bad_signature")
+
+	_, jErr = HandleV2ValidateCommit(state, MessageRequest{Message: "00"})
+	assert.NotNil(t, jErr)
+}